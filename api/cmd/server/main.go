@@ -18,6 +18,7 @@ import (
 	"github.com/yash/transaction-system/api/internal/service"
 	"github.com/yash/transaction-system/shared/config"
 	"github.com/yash/transaction-system/shared/db"
+	"github.com/yash/transaction-system/shared/tenant"
 	"github.com/yash/transaction-system/shared/tracing"
 	"go.uber.org/zap"
 )
@@ -55,10 +56,41 @@ func main() {
 	// Initialize services
 	accountService := service.NewAccountService(database.DB, logger)
 	transactionService := service.NewTransactionService(database.DB, logger)
+	transferService := service.NewTransferService(database.DB, logger)
+	fxService := service.NewFXService(database.DB, cfg.FXQuoteTTL, logger)
+	balanceService := service.NewBalanceService(database.DB, logger)
+	statementService := service.NewStatementService(database.DB, logger)
+	reconciliationService := service.NewReconciliationService(database.DB, logger)
+	scheduleService := service.NewScheduleService(database.DB, logger)
+	limitService := service.NewLimitService(database.DB, logger)
+	riskRuleService := service.NewRiskRuleService(database.DB, logger)
+	feeService := service.NewFeeService(database.DB, logger)
+	customerService := service.NewCustomerService(database.DB, logger)
+	scheduleRunner := service.NewScheduleRunner(database.DB, transactionService, cfg.ScheduleRunnerInterval, logger)
 
 	// Initialize handlers
 	accountHandler := handler.NewAccountHandler(accountService, logger)
-	transactionHandler := handler.NewTransactionHandler(transactionService, logger)
+	transactionHandler := handler.NewTransactionHandler(transactionService, cfg.TransactionBatchMaxSize, logger)
+	transferHandler := handler.NewTransferHandler(transferService, logger)
+	fxHandler := handler.NewFXHandler(fxService, logger)
+	balanceHandler := handler.NewBalanceHandler(balanceService, logger)
+	statementHandler := handler.NewStatementHandler(statementService, logger)
+	reconciliationHandler := handler.NewReconciliationHandler(reconciliationService, logger)
+	scheduleHandler := handler.NewScheduleHandler(scheduleService, logger)
+	limitHandler := handler.NewLimitHandler(limitService, logger)
+	riskRuleHandler := handler.NewRiskRuleHandler(riskRuleService, logger)
+	feeHandler := handler.NewFeeHandler(feeService, logger)
+	customerHandler := handler.NewCustomerHandler(customerService, logger)
+	currencyHandler := handler.NewCurrencyHandler(logger)
+
+	// API keys and their tenants; API_KEY belongs to the default tenant
+	apiKeys := make(map[string]string, len(cfg.APIKeys)+1)
+	for key, tenantID := range cfg.APIKeys {
+		apiKeys[key] = tenantID
+	}
+	if cfg.APIKey != "" {
+		apiKeys[cfg.APIKey] = tenant.Default
+	}
 
 	// Setup router
 	r := chi.NewRouter()
@@ -72,8 +104,8 @@ func main() {
 	r.Use(middleware.Metrics)
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins: []string{"*"},
-		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-API-Key"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-API-Key", "X-Actor"},
 	}))
 
 	// Health check
@@ -88,17 +120,86 @@ func main() {
 	// API routes
 	r.Route("/v1", func(r chi.Router) {
 		// Apply API key auth to all v1 routes
-		r.Use(middleware.APIKeyAuth(cfg.APIKey))
+		r.Use(middleware.APIKeyAuth(apiKeys))
 
 		r.Route("/accounts", func(r chi.Router) {
 			r.Post("/", accountHandler.CreateAccount)
+			r.Get("/", accountHandler.ListAccounts)
 			r.Get("/{id}", accountHandler.GetAccount)
+			r.Put("/{id}/overdraft-limit", accountHandler.UpdateOverdraftLimit)
+			r.Post("/{id}/suspend", accountHandler.SuspendAccount)
+			r.Post("/{id}/reactivate", accountHandler.ReactivateAccount)
+			r.Post("/{id}/close", accountHandler.CloseAccount)
+			r.Get("/{id}/audit-logs", accountHandler.ListAuditLogs)
+			r.Get("/{id}/limits", limitHandler.GetAccountLimits)
+			r.Put("/{id}/limits", limitHandler.SetAccountLimits)
+			r.Get("/{id}/balance", balanceHandler.GetBalance)
+			r.Get("/{id}/balance-history", balanceHandler.GetBalanceHistory)
+			r.Get("/{id}/statements/{period}", statementHandler.GetStatement)
+			r.Put("/{id}/interest", accountHandler.SetInterest)
+			r.Get("/{id}/interest-accruals", accountHandler.ListInterestAccruals)
+			r.Get("/{id}/owners", customerHandler.ListAccountOwners)
+			r.Post("/{id}/owners", customerHandler.AddAccountOwner)
+			r.Delete("/{id}/owners/{customer_id}", customerHandler.RemoveAccountOwner)
+		})
+
+		r.Get("/currencies", currencyHandler.ListCurrencies)
+
+		r.Route("/customers", func(r chi.Router) {
+			r.Post("/", customerHandler.CreateCustomer)
+			r.Get("/{id}", customerHandler.GetCustomer)
+			r.Patch("/{id}", customerHandler.UpdateCustomer)
+			r.Get("/{id}/accounts", customerHandler.ListCustomerAccounts)
 		})
 
 		r.Route("/transactions", func(r chi.Router) {
 			r.Post("/", transactionHandler.CreateTransaction)
+			r.Post("/batch", transactionHandler.CreateTransactionBatch)
 			r.Get("/", transactionHandler.ListTransactions)
 			r.Get("/{id}", transactionHandler.GetTransaction)
+			r.Get("/{id}/history", transactionHandler.GetTransactionHistory)
+			r.Delete("/{id}", transactionHandler.CancelTransaction)
+			r.Post("/{id}/cancel", transactionHandler.CancelPendingTransaction)
+			r.Post("/{id}/reverse", transactionHandler.ReverseTransaction)
+			r.Post("/{id}/approve", transactionHandler.ApproveTransaction)
+			r.Post("/{id}/reject", transactionHandler.RejectTransaction)
+		})
+
+		r.Route("/transfers", func(r chi.Router) {
+			r.Post("/", transferHandler.CreateTransfer)
+			r.Get("/{id}", transferHandler.GetTransfer)
+		})
+
+		r.Route("/fx", func(r chi.Router) {
+			// Rates are shared by all tenants; only the default tenant sets them
+			r.With(middleware.RequireTenant(tenant.Default)).Post("/rates", fxHandler.CreateRate)
+			r.Get("/rates", fxHandler.ListRates)
+			r.Post("/quotes", fxHandler.CreateQuote)
+			r.Get("/quotes/{id}", fxHandler.GetQuote)
+		})
+
+		r.Route("/schedules", func(r chi.Router) {
+			r.Post("/", scheduleHandler.CreateSchedule)
+			r.Get("/", scheduleHandler.ListSchedules)
+			r.Get("/{id}", scheduleHandler.GetSchedule)
+			r.Patch("/{id}", scheduleHandler.UpdateSchedule)
+			r.Delete("/{id}", scheduleHandler.CancelSchedule)
+		})
+
+		// Configuration and reports shared by all tenants belong to the
+		// operator of the deployment, the default tenant
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(middleware.RequireTenant(tenant.Default))
+
+			r.Get("/reconciliations", reconciliationHandler.ListReconciliations)
+			r.Get("/limit-tiers", limitHandler.ListTierLimits)
+			r.Put("/limit-tiers/{tier}", limitHandler.SetTierLimits)
+			r.Get("/risk-rules", riskRuleHandler.ListRiskRules)
+			r.Put("/risk-rules/{name}", riskRuleHandler.SetRiskRule)
+			r.Delete("/risk-rules/{name}", riskRuleHandler.DeleteRiskRule)
+			r.Get("/fee-schedules", feeHandler.ListFeeSchedules)
+			r.Put("/fee-schedules/{type}/{currency}", feeHandler.SetFeeSchedule)
+			r.Delete("/fee-schedules/{type}/{currency}", feeHandler.DeleteFeeSchedule)
 		})
 	})
 
@@ -115,6 +216,9 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	// Start recurring schedule runner
+	go scheduleRunner.Start(ctx)
+
 	go func() {
 		logger.Info("API server starting", zap.Int("port", cfg.APIPort))
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/yash/transaction-system/api/internal/service"
+	"github.com/yash/transaction-system/shared/config"
+	"github.com/yash/transaction-system/shared/db"
+	"go.uber.org/zap"
+)
+
+// statements pre-generates month-end statements for every ACTIVE account of
+// every tenant. Run it after the month closes, e.g. from cron on the 1st:
+//
+//	statements -period 2024-01
+func main() {
+	defaultPeriod := time.Now().UTC().AddDate(0, -1, 0).Format("2006-01")
+	period := flag.String("period", defaultPeriod, "statement period (YYYY-MM); defaults to the previous month")
+	flag.Parse()
+
+	// Initialize logger
+	logger, err := initLogger()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
+	}
+	defer func() {
+		_ = logger.Sync()
+	}()
+
+	// Load config
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logger.Fatal("Failed to load config", zap.Error(err))
+	}
+
+	// Connect to database
+	database, err := db.NewDB(cfg.GetPostgresDSN(), logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	defer database.Close()
+
+	statementService := service.NewStatementService(database.DB, logger)
+
+	created, err := statementService.GenerateStatements(context.Background(), *period)
+	if err != nil {
+		logger.Fatal("Failed to generate statements", zap.String("period", *period), zap.Error(err))
+	}
+
+	fmt.Printf("Generated %d statements for %s\n", created, *period)
+}
+
+func initLogger() (*zap.Logger, error) {
+	env := os.Getenv("ENV")
+	if env == "production" {
+		return zap.NewProduction()
+	}
+	return zap.NewDevelopment()
+}
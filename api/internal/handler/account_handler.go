@@ -2,11 +2,17 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/yash/transaction-system/api/internal/service"
+	"github.com/yash/transaction-system/shared/currency"
+	"github.com/yash/transaction-system/shared/interest"
 	"github.com/yash/transaction-system/shared/types"
 	"go.uber.org/zap"
 )
@@ -34,13 +40,44 @@ func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate
-	if req.Currency == "" {
-		h.respondError(w, http.StatusBadRequest, "Currency is required", nil)
+	if err := currency.Validate("currency", req.Currency); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
 		return
 	}
+	if req.Kind != "" && req.Kind != types.AccountKindStandard && req.Kind != types.AccountKindCreditLine {
+		h.respondError(w, http.StatusBadRequest, "kind must be STANDARD or CREDIT_LINE", nil)
+		return
+	}
+	if req.OverdraftLimitCents < 0 {
+		h.respondError(w, http.StatusBadRequest, "overdraft_limit_cents must not be negative", nil)
+		return
+	}
+	if len(req.JointOwnerIDs) > 0 && req.CustomerID == nil {
+		h.respondError(w, http.StatusBadRequest, "joint_owner_ids requires customer_id", nil)
+		return
+	}
+	owners := make(map[uuid.UUID]bool)
+	if req.CustomerID != nil {
+		owners[*req.CustomerID] = true
+	}
+	for _, ownerID := range req.JointOwnerIDs {
+		if owners[ownerID] {
+			h.respondError(w, http.StatusBadRequest, "joint_owner_ids must be distinct from each other and from customer_id", nil)
+			return
+		}
+		owners[ownerID] = true
+	}
 
-	account, err := h.accountService.CreateAccount(r.Context(), req)
+	account, err := h.accountService.CreateAccount(r.Context(), req, actorFromRequest(r))
 	if err != nil {
+		switch err.Error() {
+		case "customer not found":
+			h.respondError(w, http.StatusNotFound, "Customer not found", err)
+			return
+		case "unsupported currency":
+			h.respondError(w, http.StatusBadRequest, "Unsupported currency", err)
+			return
+		}
 		h.logger.Error("Failed to create account", zap.Error(err))
 		h.respondError(w, http.StatusInternalServerError, "Failed to create account", err)
 		return
@@ -72,6 +109,310 @@ func (h *AccountHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, account)
 }
 
+// ListAccounts handles GET /v1/accounts?cursor=&limit=
+func (h *AccountHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	filter, message := parseAccountFilter(r.URL.Query())
+	if message != "" {
+		h.respondError(w, http.StatusBadRequest, message, nil)
+		return
+	}
+
+	accounts, page, err := h.accountService.ListAccounts(r.Context(), filter, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		if err.Error() == "invalid cursor" {
+			h.respondError(w, http.StatusBadRequest, "Invalid cursor", err)
+			return
+		}
+		h.logger.Error("Failed to list accounts", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to list accounts", err)
+		return
+	}
+	totals, err := h.accountService.AccountTotals(r.Context(), filter)
+	if err != nil {
+		h.logger.Error("Failed to total accounts", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to list accounts", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"accounts":    accounts,
+		"totals":      totals,
+		"limit":       limit,
+		"next_cursor": page.NextCursor,
+		"prev_cursor": page.PrevCursor,
+	})
+}
+
+// parseAccountFilter reads the filters of an account list from its query
+// parameters. It returns why a parameter is invalid, or an empty string.
+func parseAccountFilter(query url.Values) (types.AccountFilter, string) {
+	var filter types.AccountFilter
+
+	if value := query.Get("status"); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			status := types.AccountStatus(strings.TrimSpace(part))
+			if !status.Valid() {
+				return filter, fmt.Sprintf("invalid status %q", status)
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+	if value := query.Get("currency"); value != "" {
+		if err := currency.Validate("currency", value); err != nil {
+			return filter, err.Error()
+		}
+		filter.Currency = value
+	}
+
+	var message string
+	if filter.CreatedFrom, filter.CreatedTo, message = parseCreatedRange(query); message != "" {
+		return filter, message
+	}
+
+	// Credit line balances are negative, so the bounds may be too
+	for _, param := range []string{"min_balance_cents", "max_balance_cents"} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		balanceCents, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return filter, param + " must be an integer"
+		}
+		if param == "min_balance_cents" {
+			filter.MinBalanceCents = &balanceCents
+		} else {
+			filter.MaxBalanceCents = &balanceCents
+		}
+	}
+	if filter.MinBalanceCents != nil && filter.MaxBalanceCents != nil && *filter.MinBalanceCents > *filter.MaxBalanceCents {
+		return filter, "min_balance_cents must not exceed max_balance_cents"
+	}
+
+	if value := query.Get("owner_id"); value != "" {
+		ownerID, err := uuid.Parse(value)
+		if err != nil {
+			return filter, "owner_id must be a UUID"
+		}
+		filter.OwnerID = &ownerID
+	}
+
+	if value := query.Get("sort"); value != "" {
+		filter.Sort = types.AccountSort(value)
+		if !filter.Sort.Valid() {
+			return filter, "sort must be created_at or balance_cents, optionally prefixed with -"
+		}
+	}
+
+	return filter, ""
+}
+
+// UpdateOverdraftLimit handles PUT /v1/accounts/:id/overdraft-limit
+func (h *AccountHandler) UpdateOverdraftLimit(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	var req types.UpdateOverdraftLimitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.OverdraftLimitCents < 0 {
+		h.respondError(w, http.StatusBadRequest, "overdraft_limit_cents must not be negative", nil)
+		return
+	}
+
+	account, err := h.accountService.UpdateOverdraftLimit(r.Context(), accountID, req, actorFromRequest(r))
+	if err != nil {
+		if err.Error() == "account not found" {
+			h.respondError(w, http.StatusNotFound, "Account not found", err)
+			return
+		}
+		h.logger.Error("Failed to update overdraft limit", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to update overdraft limit", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, account)
+}
+
+// SetInterest handles PUT /v1/accounts/:id/interest
+func (h *AccountHandler) SetInterest(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	var req types.SetInterestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.AnnualRate != nil {
+		if _, err := interest.ParseRate(*req.AnnualRate); err != nil {
+			h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+	}
+	if req.DayCount != "" && !req.DayCount.Valid() {
+		h.respondError(w, http.StatusBadRequest, "day_count must be ACT/365 or 30/360", nil)
+		return
+	}
+
+	account, err := h.accountService.SetInterest(r.Context(), accountID, req, actorFromRequest(r))
+	if err != nil {
+		if err.Error() == "account not found" {
+			h.respondError(w, http.StatusNotFound, "Account not found", err)
+			return
+		}
+		h.logger.Error("Failed to set interest", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to set interest", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, account)
+}
+
+// ListInterestAccruals handles GET /v1/accounts/:id/interest-accruals?cursor=&limit=
+func (h *AccountHandler) ListInterestAccruals(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	limit := 50
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	accruals, page, err := h.accountService.ListInterestAccruals(r.Context(), accountID, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		if err.Error() == "account not found" {
+			h.respondError(w, http.StatusNotFound, "Account not found", err)
+			return
+		}
+		if err.Error() == "invalid cursor" {
+			h.respondError(w, http.StatusBadRequest, "Invalid cursor", err)
+			return
+		}
+		h.logger.Error("Failed to list interest accruals", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to list interest accruals", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items":       accruals,
+		"limit":       limit,
+		"next_cursor": page.NextCursor,
+		"prev_cursor": page.PrevCursor,
+	})
+}
+
+// SuspendAccount handles POST /v1/accounts/:id/suspend
+func (h *AccountHandler) SuspendAccount(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, types.AccountStatusSuspended)
+}
+
+// ReactivateAccount handles POST /v1/accounts/:id/reactivate
+func (h *AccountHandler) ReactivateAccount(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, types.AccountStatusActive)
+}
+
+// CloseAccount handles POST /v1/accounts/:id/close
+func (h *AccountHandler) CloseAccount(w http.ResponseWriter, r *http.Request) {
+	h.changeStatus(w, r, types.AccountStatusClosed)
+}
+
+func (h *AccountHandler) changeStatus(w http.ResponseWriter, r *http.Request, status types.AccountStatus) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	var req types.ChangeAccountStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if !req.ReasonCode.Valid() {
+		h.respondError(w, http.StatusBadRequest, "reason_code must be one of CUSTOMER_REQUEST, FRAUD_SUSPECTED, COMPLIANCE, DORMANT, RESOLVED, OTHER", nil)
+		return
+	}
+
+	account, err := h.accountService.ChangeStatus(r.Context(), accountID, status, req, actorFromRequest(r))
+	if err != nil {
+		switch err.Error() {
+		case "account not found":
+			h.respondError(w, http.StatusNotFound, "Account not found", err)
+		case "invalid status transition":
+			h.respondError(w, http.StatusConflict, fmt.Sprintf("Account cannot move to %s from its current status", status), err)
+		case "account balance is not zero", "account has held funds", "account has pending transactions":
+			h.respondError(w, http.StatusUnprocessableEntity, "Account cannot be closed", err)
+		default:
+			h.logger.Error("Failed to change account status", zap.Error(err))
+			h.respondError(w, http.StatusInternalServerError, "Failed to change account status", err)
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, account)
+}
+
+// ListAuditLogs handles GET /v1/accounts/:id/audit-logs?cursor=&limit=
+func (h *AccountHandler) ListAuditLogs(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	limit := 50
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	logs, page, err := h.accountService.ListAuditLogs(r.Context(), accountID, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		if err.Error() == "account not found" {
+			h.respondError(w, http.StatusNotFound, "Account not found", err)
+			return
+		}
+		if err.Error() == "invalid cursor" {
+			h.respondError(w, http.StatusBadRequest, "Invalid cursor", err)
+			return
+		}
+		h.logger.Error("Failed to list audit logs", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to list audit logs", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"audit_logs":  logs,
+		"limit":       limit,
+		"next_cursor": page.NextCursor,
+		"prev_cursor": page.PrevCursor,
+	})
+}
+
+// actorFromRequest identifies who made an administrative change for the audit trail
+func actorFromRequest(r *http.Request) string {
+	if actor := r.Header.Get("X-Actor"); actor != "" {
+		return actor
+	}
+	return "api"
+}
+
 func (h *AccountHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/api/internal/service"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// BalanceHandler handles point-in-time balance HTTP requests
+type BalanceHandler struct {
+	balanceService *service.BalanceService
+	logger         *zap.Logger
+}
+
+// NewBalanceHandler creates a new balance handler
+func NewBalanceHandler(balanceService *service.BalanceService, logger *zap.Logger) *BalanceHandler {
+	return &BalanceHandler{
+		balanceService: balanceService,
+		logger:         logger,
+	}
+}
+
+// GetBalance handles GET /v1/accounts/:id/balance?as_of=
+func (h *BalanceHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	// A bare date means the end of that day
+	asOf := time.Now().UTC()
+	if asOfStr := r.URL.Query().Get("as_of"); asOfStr != "" {
+		parsed, dateOnly, err := parseTimeParam(asOfStr)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "as_of must be an RFC 3339 timestamp or YYYY-MM-DD", err)
+			return
+		}
+		if dateOnly {
+			parsed = parsed.AddDate(0, 0, 1).Add(-time.Microsecond)
+		}
+		asOf = parsed
+	}
+
+	balance, err := h.balanceService.GetBalance(r.Context(), accountID, asOf)
+	if err != nil {
+		if err.Error() == "account not found" {
+			h.respondError(w, http.StatusNotFound, "Account not found", err)
+			return
+		}
+		h.logger.Error("Failed to get balance", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get balance", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, balance)
+}
+
+// GetBalanceHistory handles GET /v1/accounts/:id/balance-history?from=&to=&interval=
+func (h *BalanceHandler) GetBalanceHistory(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	// Defaults to the last 30 days; a bare `to` date includes that whole day
+	to := time.Now().UTC()
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		parsed, dateOnly, err := parseTimeParam(toStr)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "to must be an RFC 3339 timestamp or YYYY-MM-DD", err)
+			return
+		}
+		if dateOnly {
+			parsed = parsed.AddDate(0, 0, 1)
+		}
+		to = parsed
+	}
+	from := to.AddDate(0, 0, -30)
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		parsed, _, err := parseTimeParam(fromStr)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "from must be an RFC 3339 timestamp or YYYY-MM-DD", err)
+			return
+		}
+		from = parsed
+	}
+	if !from.Before(to) {
+		h.respondError(w, http.StatusBadRequest, "from must be before to", nil)
+		return
+	}
+
+	interval := types.BalanceInterval(r.URL.Query().Get("interval"))
+	switch interval {
+	case "":
+		interval = types.BalanceIntervalDay
+	case types.BalanceIntervalHour, types.BalanceIntervalDay, types.BalanceIntervalWeek, types.BalanceIntervalMonth:
+	default:
+		h.respondError(w, http.StatusBadRequest, "interval must be hour, day, week or month", nil)
+		return
+	}
+
+	history, err := h.balanceService.GetBalanceHistory(r.Context(), accountID, from, to, interval)
+	if err != nil {
+		if err.Error() == "account not found" {
+			h.respondError(w, http.StatusNotFound, "Account not found", err)
+			return
+		}
+		if err.Error() == "too many intervals" {
+			h.respondError(w, http.StatusBadRequest, "Range too large for interval", err)
+			return
+		}
+		h.logger.Error("Failed to get balance history", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get balance history", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, history)
+}
+
+// parseTimeParam parses an RFC 3339 timestamp or a YYYY-MM-DD date (UTC midnight)
+func parseTimeParam(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.UTC(), false, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	return time.Time{}, false, fmt.Errorf("invalid time %q", value)
+}
+
+func (h *BalanceHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *BalanceHandler) respondError(w http.ResponseWriter, status int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	response := map[string]string{
+		"error": message,
+	}
+	if err != nil {
+		response["details"] = err.Error()
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/yash/transaction-system/shared/currency"
+	"go.uber.org/zap"
+)
+
+// CurrencyHandler serves the currency registry
+type CurrencyHandler struct {
+	logger *zap.Logger
+}
+
+// NewCurrencyHandler creates a new currency handler
+func NewCurrencyHandler(logger *zap.Logger) *CurrencyHandler {
+	return &CurrencyHandler{
+		logger: logger,
+	}
+}
+
+// ListCurrencies handles GET /v1/currencies
+func (h *CurrencyHandler) ListCurrencies(w http.ResponseWriter, r *http.Request) {
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items": currency.All(),
+	})
+}
+
+func (h *CurrencyHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/api/internal/service"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// CustomerHandler handles customer and account ownership HTTP requests
+type CustomerHandler struct {
+	customerService *service.CustomerService
+	logger          *zap.Logger
+}
+
+// NewCustomerHandler creates a new customer handler
+func NewCustomerHandler(customerService *service.CustomerService, logger *zap.Logger) *CustomerHandler {
+	return &CustomerHandler{
+		customerService: customerService,
+		logger:          logger,
+	}
+}
+
+// CreateCustomer handles POST /v1/customers
+func (h *CustomerHandler) CreateCustomer(w http.ResponseWriter, r *http.Request) {
+	var req types.CreateCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if strings.TrimSpace(req.FullName) == "" {
+		h.respondError(w, http.StatusBadRequest, "full_name is required", nil)
+		return
+	}
+	if err := validateProfile(req.Email, req.DateOfBirth); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	customer, err := h.customerService.CreateCustomer(r.Context(), req, actorFromRequest(r))
+	if err != nil {
+		h.logger.Error("Failed to create customer", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to create customer", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, customer)
+}
+
+// GetCustomer handles GET /v1/customers/:id
+func (h *CustomerHandler) GetCustomer(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	customer, err := h.customerService.GetCustomer(r.Context(), customerID)
+	if err != nil {
+		if err.Error() == "customer not found" {
+			h.respondError(w, http.StatusNotFound, "Customer not found", err)
+			return
+		}
+		h.logger.Error("Failed to get customer", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get customer", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, customer)
+}
+
+// UpdateCustomer handles PATCH /v1/customers/:id
+func (h *CustomerHandler) UpdateCustomer(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	var req types.UpdateCustomerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.FullName != nil && strings.TrimSpace(*req.FullName) == "" {
+		h.respondError(w, http.StatusBadRequest, "full_name must not be empty", nil)
+		return
+	}
+	if err := validateProfile(req.Email, req.DateOfBirth); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	customer, err := h.customerService.UpdateCustomer(r.Context(), customerID, req, actorFromRequest(r))
+	if err != nil {
+		if err.Error() == "customer not found" {
+			h.respondError(w, http.StatusNotFound, "Customer not found", err)
+			return
+		}
+		h.logger.Error("Failed to update customer", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to update customer", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, customer)
+}
+
+// ListCustomerAccounts handles GET /v1/customers/:id/accounts
+func (h *CustomerHandler) ListCustomerAccounts(w http.ResponseWriter, r *http.Request) {
+	customerID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	accounts, err := h.customerService.ListCustomerAccounts(r.Context(), customerID)
+	if err != nil {
+		if err.Error() == "customer not found" {
+			h.respondError(w, http.StatusNotFound, "Customer not found", err)
+			return
+		}
+		h.logger.Error("Failed to list customer accounts", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to list customer accounts", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, accounts)
+}
+
+// ListAccountOwners handles GET /v1/accounts/:id/owners
+func (h *CustomerHandler) ListAccountOwners(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	owners, err := h.customerService.ListAccountOwners(r.Context(), accountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			h.respondError(w, http.StatusNotFound, "Account not found", err)
+			return
+		}
+		h.logger.Error("Failed to list account owners", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to list account owners", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items": owners,
+	})
+}
+
+// AddAccountOwner handles POST /v1/accounts/:id/owners
+func (h *CustomerHandler) AddAccountOwner(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	var req types.AddAccountOwnerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.CustomerID == uuid.Nil {
+		h.respondError(w, http.StatusBadRequest, "customer_id is required", nil)
+		return
+	}
+
+	owners, err := h.customerService.AddAccountOwner(r.Context(), accountID, req.CustomerID, actorFromRequest(r))
+	if err != nil {
+		switch err.Error() {
+		case "account not found":
+			h.respondError(w, http.StatusNotFound, "Account not found", err)
+		case "customer not found":
+			h.respondError(w, http.StatusNotFound, "Customer not found", err)
+		case "account is closed", "account has no primary owner", "customer already owns the account":
+			h.respondError(w, http.StatusConflict, "Owner cannot be added", err)
+		default:
+			h.logger.Error("Failed to add account owner", zap.Error(err))
+			h.respondError(w, http.StatusInternalServerError, "Failed to add account owner", err)
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"items": owners,
+	})
+}
+
+// RemoveAccountOwner handles DELETE /v1/accounts/:id/owners/:customer_id
+func (h *CustomerHandler) RemoveAccountOwner(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+	customerID, err := uuid.Parse(chi.URLParam(r, "customer_id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid customer ID", err)
+		return
+	}
+
+	err = h.customerService.RemoveAccountOwner(r.Context(), accountID, customerID, actorFromRequest(r))
+	if err != nil {
+		switch err.Error() {
+		case "account owner not found":
+			h.respondError(w, http.StatusNotFound, "Account owner not found", err)
+		case "cannot remove the primary owner":
+			h.respondError(w, http.StatusConflict, "Owner cannot be removed", err)
+		default:
+			h.logger.Error("Failed to remove account owner", zap.Error(err))
+			h.respondError(w, http.StatusInternalServerError, "Failed to remove account owner", err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateProfile checks the optional profile fields of a customer request.
+// Empty values are allowed; they clear the field on update.
+func validateProfile(email, dateOfBirth *string) error {
+	if email != nil && *email != "" {
+		if _, err := mail.ParseAddress(*email); err != nil {
+			return fmt.Errorf("email is not a valid address")
+		}
+	}
+	if dateOfBirth != nil && *dateOfBirth != "" {
+		born, err := time.Parse("2006-01-02", *dateOfBirth)
+		if err != nil {
+			return fmt.Errorf("date_of_birth must be YYYY-MM-DD")
+		}
+		if born.After(time.Now()) {
+			return fmt.Errorf("date_of_birth must not be in the future")
+		}
+	}
+	return nil
+}
+
+func (h *CustomerHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *CustomerHandler) respondError(w http.ResponseWriter, status int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	response := map[string]string{
+		"error": message,
+	}
+	if err != nil {
+		response["details"] = err.Error()
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
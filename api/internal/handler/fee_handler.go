@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yash/transaction-system/api/internal/service"
+	"github.com/yash/transaction-system/shared/currency"
+	"github.com/yash/transaction-system/shared/fees"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// FeeHandler handles fee schedule HTTP requests
+type FeeHandler struct {
+	feeService *service.FeeService
+	logger     *zap.Logger
+}
+
+// NewFeeHandler creates a new fee handler
+func NewFeeHandler(feeService *service.FeeService, logger *zap.Logger) *FeeHandler {
+	return &FeeHandler{
+		feeService: feeService,
+		logger:     logger,
+	}
+}
+
+// ListFeeSchedules handles GET /v1/admin/fee-schedules
+func (h *FeeHandler) ListFeeSchedules(w http.ResponseWriter, r *http.Request) {
+	schedules, err := h.feeService.ListFeeSchedules(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to list fee schedules", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to list fee schedules", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items": schedules,
+	})
+}
+
+// SetFeeSchedule handles PUT /v1/admin/fee-schedules/:type/:currency
+func (h *FeeHandler) SetFeeSchedule(w http.ResponseWriter, r *http.Request) {
+	transactionType := types.TransactionType(chi.URLParam(r, "type"))
+	if !fees.Chargeable(transactionType) {
+		h.respondError(w, http.StatusBadRequest, "type must be DEBIT, CREDIT or CAPTURE", nil)
+		return
+	}
+
+	feeCurrency := chi.URLParam(r, "currency")
+	if feeCurrency != types.FeeCurrencyAny && !currency.Valid(feeCurrency) {
+		h.respondError(w, http.StatusBadRequest, "currency must be ANY or a supported ISO 4217 code", nil)
+		return
+	}
+
+	var req types.SetFeeScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if err := fees.Validate(req); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	schedule, err := h.feeService.SetFeeSchedule(r.Context(), transactionType, feeCurrency, req, actorFromRequest(r))
+	if err != nil {
+		h.logger.Error("Failed to set fee schedule", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to set fee schedule", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, schedule)
+}
+
+// DeleteFeeSchedule handles DELETE /v1/admin/fee-schedules/:type/:currency
+func (h *FeeHandler) DeleteFeeSchedule(w http.ResponseWriter, r *http.Request) {
+	transactionType := types.TransactionType(chi.URLParam(r, "type"))
+	err := h.feeService.DeleteFeeSchedule(r.Context(), transactionType, chi.URLParam(r, "currency"), actorFromRequest(r))
+	if err != nil {
+		if err.Error() == "fee schedule not found" {
+			h.respondError(w, http.StatusNotFound, "Fee schedule not found", err)
+			return
+		}
+		h.logger.Error("Failed to delete fee schedule", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to delete fee schedule", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *FeeHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *FeeHandler) respondError(w http.ResponseWriter, status int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	response := map[string]string{
+		"error": message,
+	}
+	if err != nil {
+		response["details"] = err.Error()
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
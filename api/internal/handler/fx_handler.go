@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/api/internal/service"
+	"github.com/yash/transaction-system/shared/currency"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// FXHandler handles FX rate and quote HTTP requests
+type FXHandler struct {
+	fxService *service.FXService
+	logger    *zap.Logger
+}
+
+// NewFXHandler creates a new FX handler
+func NewFXHandler(fxService *service.FXService, logger *zap.Logger) *FXHandler {
+	return &FXHandler{
+		fxService: fxService,
+		logger:    logger,
+	}
+}
+
+// CreateRate handles POST /v1/fx/rates
+func (h *FXHandler) CreateRate(w http.ResponseWriter, r *http.Request) {
+	var req types.CreateFXRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// Validate
+	if err := currency.Validate("base_currency", req.BaseCurrency); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if err := currency.Validate("quote_currency", req.QuoteCurrency); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if req.BaseCurrency == req.QuoteCurrency {
+		h.respondError(w, http.StatusBadRequest, "base_currency and quote_currency must differ", nil)
+		return
+	}
+	if req.Rate == "" {
+		h.respondError(w, http.StatusBadRequest, "rate is required", nil)
+		return
+	}
+	if req.SpreadBps < 0 || req.SpreadBps >= 10000 {
+		h.respondError(w, http.StatusBadRequest, "spread_bps must be between 0 and 9999", nil)
+		return
+	}
+
+	rate, err := h.fxService.CreateRate(r.Context(), req)
+	if err != nil {
+		switch err.Error() {
+		case "invalid rate":
+			h.respondError(w, http.StatusBadRequest, "rate must be a positive decimal", err)
+			return
+		case "unsupported currency":
+			h.respondError(w, http.StatusBadRequest, "Unsupported currency", err)
+			return
+		}
+		h.logger.Error("Failed to create fx rate", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to create fx rate", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, rate)
+}
+
+// ListRates handles GET /v1/fx/rates
+func (h *FXHandler) ListRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.fxService.ListRates(r.Context(), r.URL.Query().Get("currency"))
+	if err != nil {
+		h.logger.Error("Failed to list fx rates", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to list fx rates", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"rates": rates,
+	})
+}
+
+// CreateQuote handles POST /v1/fx/quotes
+func (h *FXHandler) CreateQuote(w http.ResponseWriter, r *http.Request) {
+	var req types.CreateFXQuoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// Validate
+	if err := currency.Validate("base_currency", req.BaseCurrency); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if err := currency.Validate("quote_currency", req.QuoteCurrency); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if req.BaseCurrency == req.QuoteCurrency {
+		h.respondError(w, http.StatusBadRequest, "base_currency and quote_currency must differ", nil)
+		return
+	}
+
+	quote, err := h.fxService.CreateQuote(r.Context(), req)
+	if err != nil {
+		if err.Error() == "fx rate not found" {
+			h.respondError(w, http.StatusNotFound, "No rate for currency pair", err)
+			return
+		}
+		h.logger.Error("Failed to create fx quote", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to create fx quote", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, quote)
+}
+
+// GetQuote handles GET /v1/fx/quotes/:id
+func (h *FXHandler) GetQuote(w http.ResponseWriter, r *http.Request) {
+	quoteID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid quote ID", err)
+		return
+	}
+
+	quote, err := h.fxService.GetQuote(r.Context(), quoteID)
+	if err != nil {
+		if err.Error() == "fx quote not found" {
+			h.respondError(w, http.StatusNotFound, "FX quote not found", err)
+			return
+		}
+		h.logger.Error("Failed to get fx quote", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get fx quote", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, quote)
+}
+
+func (h *FXHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *FXHandler) respondError(w http.ResponseWriter, status int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	response := map[string]string{
+		"error": message,
+	}
+	if err != nil {
+		response["details"] = err.Error()
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
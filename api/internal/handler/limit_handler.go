@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/api/internal/service"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// LimitHandler handles debit limit HTTP requests
+type LimitHandler struct {
+	limitService *service.LimitService
+	logger       *zap.Logger
+}
+
+// NewLimitHandler creates a new limit handler
+func NewLimitHandler(limitService *service.LimitService, logger *zap.Logger) *LimitHandler {
+	return &LimitHandler{
+		limitService: limitService,
+		logger:       logger,
+	}
+}
+
+// GetAccountLimits handles GET /v1/accounts/:id/limits
+func (h *LimitHandler) GetAccountLimits(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	accountLimits, err := h.limitService.GetAccountLimits(r.Context(), accountID)
+	if err != nil {
+		if err.Error() == "account not found" {
+			h.respondError(w, http.StatusNotFound, "Account not found", err)
+			return
+		}
+		h.logger.Error("Failed to get account limits", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get account limits", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, accountLimits)
+}
+
+// SetAccountLimits handles PUT /v1/accounts/:id/limits
+func (h *LimitHandler) SetAccountLimits(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	var req types.SetAccountLimitsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if message := validateLimits(req.Overrides); message != "" {
+		h.respondError(w, http.StatusBadRequest, message, nil)
+		return
+	}
+
+	accountLimits, err := h.limitService.SetAccountLimits(r.Context(), accountID, req, actorFromRequest(r))
+	if err != nil {
+		if err.Error() == "account not found" {
+			h.respondError(w, http.StatusNotFound, "Account not found", err)
+			return
+		}
+		h.logger.Error("Failed to set account limits", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to set account limits", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, accountLimits)
+}
+
+// ListTierLimits handles GET /v1/admin/limit-tiers
+func (h *LimitHandler) ListTierLimits(w http.ResponseWriter, r *http.Request) {
+	tiers, err := h.limitService.ListTierLimits(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to list tier limits", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to list tier limits", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items": tiers,
+	})
+}
+
+// SetTierLimits handles PUT /v1/admin/limit-tiers/:tier
+func (h *LimitHandler) SetTierLimits(w http.ResponseWriter, r *http.Request) {
+	tier := chi.URLParam(r, "tier")
+
+	var req types.TransactionLimits
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if message := validateLimits(req); message != "" {
+		h.respondError(w, http.StatusBadRequest, message, nil)
+		return
+	}
+
+	tierLimits, err := h.limitService.SetTierLimits(r.Context(), tier, req, actorFromRequest(r))
+	if err != nil {
+		h.logger.Error("Failed to set tier limits", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to set tier limits", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, tierLimits)
+}
+
+// validateLimits returns why a set of limits is invalid, or an empty string
+func validateLimits(limits types.TransactionLimits) string {
+	for _, amount := range []*int64{limits.MaxSingleDebitCents, limits.MaxDailyDebitCents, limits.MaxMonthlyDebitCents} {
+		if amount != nil && *amount < 0 {
+			return "limit amounts must not be negative"
+		}
+	}
+	for _, count := range []*int{limits.MaxDailyDebitCount, limits.MaxMonthlyDebitCount} {
+		if count != nil && *count < 0 {
+			return "limit counts must not be negative"
+		}
+	}
+	return ""
+}
+
+func (h *LimitHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *LimitHandler) respondError(w http.ResponseWriter, status int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	response := map[string]string{
+		"error": message,
+	}
+	if err != nil {
+		response["details"] = err.Error()
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/yash/transaction-system/api/internal/service"
+	"go.uber.org/zap"
+)
+
+// ReconciliationHandler handles reconciliation report HTTP requests
+type ReconciliationHandler struct {
+	reconciliationService *service.ReconciliationService
+	logger                *zap.Logger
+}
+
+// NewReconciliationHandler creates a new reconciliation handler
+func NewReconciliationHandler(reconciliationService *service.ReconciliationService, logger *zap.Logger) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		reconciliationService: reconciliationService,
+		logger:                logger,
+	}
+}
+
+// ListReconciliations handles GET /v1/admin/reconciliations?drift_only=&cursor=&limit=
+func (h *ReconciliationHandler) ListReconciliations(w http.ResponseWriter, r *http.Request) {
+	limit := 20
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+	driftOnly, _ := strconv.ParseBool(r.URL.Query().Get("drift_only"))
+
+	runs, page, err := h.reconciliationService.ListReconciliations(r.Context(), driftOnly, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		if err.Error() == "invalid cursor" {
+			h.respondError(w, http.StatusBadRequest, "Invalid cursor", err)
+			return
+		}
+		h.logger.Error("Failed to list reconciliations", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to list reconciliations", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"reconciliations": runs,
+		"limit":           limit,
+		"next_cursor":     page.NextCursor,
+		"prev_cursor":     page.PrevCursor,
+	})
+}
+
+func (h *ReconciliationHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *ReconciliationHandler) respondError(w http.ResponseWriter, status int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	response := map[string]string{
+		"error": message,
+	}
+	if err != nil {
+		response["details"] = err.Error()
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yash/transaction-system/api/internal/service"
+	"github.com/yash/transaction-system/shared/risk"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// RiskRuleHandler handles risk rule HTTP requests
+type RiskRuleHandler struct {
+	riskRuleService *service.RiskRuleService
+	logger          *zap.Logger
+}
+
+// NewRiskRuleHandler creates a new risk rule handler
+func NewRiskRuleHandler(riskRuleService *service.RiskRuleService, logger *zap.Logger) *RiskRuleHandler {
+	return &RiskRuleHandler{
+		riskRuleService: riskRuleService,
+		logger:          logger,
+	}
+}
+
+// ListRiskRules handles GET /v1/admin/risk-rules
+func (h *RiskRuleHandler) ListRiskRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.riskRuleService.ListRiskRules(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to list risk rules", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to list risk rules", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items": rules,
+	})
+}
+
+// SetRiskRule handles PUT /v1/admin/risk-rules/:name
+func (h *RiskRuleHandler) SetRiskRule(w http.ResponseWriter, r *http.Request) {
+	var rule types.RiskRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	rule.Name = chi.URLParam(r, "name")
+	if err := risk.Validate(rule); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	saved, err := h.riskRuleService.SetRiskRule(r.Context(), rule, actorFromRequest(r))
+	if err != nil {
+		h.logger.Error("Failed to set risk rule", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to set risk rule", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, saved)
+}
+
+// DeleteRiskRule handles DELETE /v1/admin/risk-rules/:name
+func (h *RiskRuleHandler) DeleteRiskRule(w http.ResponseWriter, r *http.Request) {
+	err := h.riskRuleService.DeleteRiskRule(r.Context(), chi.URLParam(r, "name"), actorFromRequest(r))
+	if err != nil {
+		if err.Error() == "risk rule not found" {
+			h.respondError(w, http.StatusNotFound, "Risk rule not found", err)
+			return
+		}
+		h.logger.Error("Failed to delete risk rule", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to delete risk rule", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *RiskRuleHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *RiskRuleHandler) respondError(w http.ResponseWriter, status int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	response := map[string]string{
+		"error": message,
+	}
+	if err != nil {
+		response["details"] = err.Error()
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/api/internal/service"
+	"github.com/yash/transaction-system/shared/currency"
+	"github.com/yash/transaction-system/shared/recurrence"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// ScheduleHandler handles recurring schedule HTTP requests
+type ScheduleHandler struct {
+	scheduleService *service.ScheduleService
+	logger          *zap.Logger
+}
+
+// NewScheduleHandler creates a new schedule handler
+func NewScheduleHandler(scheduleService *service.ScheduleService, logger *zap.Logger) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+		logger:          logger,
+	}
+}
+
+// CreateSchedule handles POST /v1/schedules
+func (h *ScheduleHandler) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	var req types.CreateScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// Validate
+	if req.Template.AccountID == uuid.Nil {
+		h.respondError(w, http.StatusBadRequest, "template.account_id is required", nil)
+		return
+	}
+	if req.Template.AmountCents <= 0 {
+		h.respondError(w, http.StatusBadRequest, "template.amount_cents must be positive", nil)
+		return
+	}
+	if err := currency.Validate("template.currency", req.Template.Currency); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if req.Template.Type != types.TransactionTypeDebit && req.Template.Type != types.TransactionTypeCredit {
+		h.respondError(w, http.StatusBadRequest, "template.type must be DEBIT or CREDIT", nil)
+		return
+	}
+	rule, ok := h.resolveRule(w, req.Rule, req.RRule, true)
+	if !ok {
+		return
+	}
+	req.Rule = rule
+
+	schedule, err := h.scheduleService.CreateSchedule(r.Context(), req)
+	if err != nil {
+		switch err.Error() {
+		case "account not found":
+			h.respondError(w, http.StatusNotFound, "Account not found", err)
+		case "account is not active":
+			h.respondError(w, http.StatusBadRequest, "Account is not active", err)
+		case "unsupported currency":
+			h.respondError(w, http.StatusBadRequest, "Unsupported currency", err)
+		case "rule has no occurrences":
+			h.respondError(w, http.StatusBadRequest, "Rule has no occurrences after start_at", err)
+		default:
+			h.logger.Error("Failed to create schedule", zap.Error(err))
+			h.respondError(w, http.StatusInternalServerError, "Failed to create schedule", err)
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, schedule)
+}
+
+// GetSchedule handles GET /v1/schedules/:id
+func (h *ScheduleHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	scheduleID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid schedule ID", err)
+		return
+	}
+
+	schedule, err := h.scheduleService.GetSchedule(r.Context(), scheduleID)
+	if err != nil {
+		if err.Error() == "schedule not found" {
+			h.respondError(w, http.StatusNotFound, "Schedule not found", err)
+			return
+		}
+		h.logger.Error("Failed to get schedule", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get schedule", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, schedule)
+}
+
+// ListSchedules handles GET /v1/schedules?cursor=&limit=
+func (h *ScheduleHandler) ListSchedules(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := 50
+	if limitStr := query.Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 1 || l > 100 {
+			h.respondError(w, http.StatusBadRequest, "limit must be between 1 and 100", nil)
+			return
+		}
+		limit = l
+	}
+
+	var accountID *uuid.UUID
+	if accountIDStr := query.Get("account_id"); accountIDStr != "" {
+		id, err := uuid.Parse(accountIDStr)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "Invalid account ID", err)
+			return
+		}
+		accountID = &id
+	}
+
+	status := types.ScheduleStatus(query.Get("status"))
+	switch status {
+	case "", types.ScheduleStatusActive, types.ScheduleStatusPaused, types.ScheduleStatusCompleted, types.ScheduleStatusCancelled:
+	default:
+		h.respondError(w, http.StatusBadRequest, "status must be ACTIVE, PAUSED, COMPLETED or CANCELLED", nil)
+		return
+	}
+
+	schedules, page, err := h.scheduleService.ListSchedules(r.Context(), accountID, status, query.Get("cursor"), limit)
+	if err != nil {
+		if err.Error() == "invalid cursor" {
+			h.respondError(w, http.StatusBadRequest, "Invalid cursor", err)
+			return
+		}
+		h.logger.Error("Failed to list schedules", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to list schedules", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items":       schedules,
+		"limit":       limit,
+		"next_cursor": page.NextCursor,
+		"prev_cursor": page.PrevCursor,
+	})
+}
+
+// UpdateSchedule handles PATCH /v1/schedules/:id
+func (h *ScheduleHandler) UpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	scheduleID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid schedule ID", err)
+		return
+	}
+
+	var req types.UpdateScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	if req.AmountCents != nil && *req.AmountCents <= 0 {
+		h.respondError(w, http.StatusBadRequest, "amount_cents must be positive", nil)
+		return
+	}
+	if req.Status != nil && *req.Status != types.ScheduleStatusActive && *req.Status != types.ScheduleStatusPaused {
+		h.respondError(w, http.StatusBadRequest, "status must be ACTIVE or PAUSED", nil)
+		return
+	}
+	if req.Rule != nil || req.RRule != nil {
+		rrule := ""
+		if req.RRule != nil {
+			rrule = *req.RRule
+		}
+		rule, ok := h.resolveRule(w, req.Rule, rrule, false)
+		if !ok {
+			return
+		}
+		req.Rule = rule
+	}
+
+	schedule, err := h.scheduleService.UpdateSchedule(r.Context(), scheduleID, req)
+	if err != nil {
+		switch err.Error() {
+		case "schedule not found":
+			h.respondError(w, http.StatusNotFound, "Schedule not found", err)
+		case "schedule has ended":
+			h.respondError(w, http.StatusConflict, "Completed or cancelled schedules cannot be changed", err)
+		default:
+			h.logger.Error("Failed to update schedule", zap.Error(err))
+			h.respondError(w, http.StatusInternalServerError, "Failed to update schedule", err)
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, schedule)
+}
+
+// CancelSchedule handles DELETE /v1/schedules/:id
+func (h *ScheduleHandler) CancelSchedule(w http.ResponseWriter, r *http.Request) {
+	scheduleID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid schedule ID", err)
+		return
+	}
+
+	schedule, err := h.scheduleService.CancelSchedule(r.Context(), scheduleID)
+	if err != nil {
+		switch err.Error() {
+		case "schedule not found":
+			h.respondError(w, http.StatusNotFound, "Schedule not found", err)
+		case "schedule has ended":
+			h.respondError(w, http.StatusConflict, "Schedule has already ended", err)
+		default:
+			h.logger.Error("Failed to cancel schedule", zap.Error(err))
+			h.respondError(w, http.StatusInternalServerError, "Failed to cancel schedule", err)
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, schedule)
+}
+
+// resolveRule returns the rule given either as a structured rule or as an
+// RRULE string, writing a 400 if neither or both are set or it is invalid
+func (h *ScheduleHandler) resolveRule(w http.ResponseWriter, rule *types.RecurrenceRule, rrule string, required bool) (*types.RecurrenceRule, bool) {
+	if rule != nil && rrule != "" {
+		h.respondError(w, http.StatusBadRequest, "rule and rrule are mutually exclusive", nil)
+		return nil, false
+	}
+	if rrule != "" {
+		parsed, err := recurrence.Parse(rrule)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "Invalid rrule", err)
+			return nil, false
+		}
+		return &parsed, true
+	}
+	if rule == nil {
+		if required {
+			h.respondError(w, http.StatusBadRequest, "rule or rrule is required", nil)
+		} else {
+			h.respondError(w, http.StatusBadRequest, "rrule must not be empty", nil)
+		}
+		return nil, false
+	}
+	if err := recurrence.Validate(*rule); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid rule", err)
+		return nil, false
+	}
+	return rule, true
+}
+
+func (h *ScheduleHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *ScheduleHandler) respondError(w http.ResponseWriter, status int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	response := map[string]string{
+		"error": message,
+	}
+	if err != nil {
+		response["details"] = err.Error()
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
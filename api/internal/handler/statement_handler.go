@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/api/internal/service"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// StatementHandler handles account statement HTTP requests
+type StatementHandler struct {
+	statementService *service.StatementService
+	logger           *zap.Logger
+}
+
+// NewStatementHandler creates a new statement handler
+func NewStatementHandler(statementService *service.StatementService, logger *zap.Logger) *StatementHandler {
+	return &StatementHandler{
+		statementService: statementService,
+		logger:           logger,
+	}
+}
+
+// GetStatement handles GET /v1/accounts/:id/statements/:period
+// CSV is returned for ?format=csv or an Accept header of text/csv.
+func (h *StatementHandler) GetStatement(w http.ResponseWriter, r *http.Request) {
+	accountID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid account ID", err)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+		if strings.Contains(r.Header.Get("Accept"), "text/csv") {
+			format = "csv"
+		}
+	}
+	if format != "json" && format != "csv" {
+		h.respondError(w, http.StatusBadRequest, "format must be json or csv", nil)
+		return
+	}
+
+	period := chi.URLParam(r, "period")
+	statement, err := h.statementService.GetStatement(r.Context(), accountID, period)
+	if err != nil {
+		switch err.Error() {
+		case "invalid period":
+			h.respondError(w, http.StatusBadRequest, "period must be YYYY-MM", err)
+		case "period has not started":
+			h.respondError(w, http.StatusBadRequest, "Period is in the future", err)
+		case "account not found":
+			h.respondError(w, http.StatusNotFound, "Account not found", err)
+		default:
+			h.logger.Error("Failed to get statement", zap.Error(err))
+			h.respondError(w, http.StatusInternalServerError, "Failed to get statement", err)
+		}
+		return
+	}
+
+	if format == "csv" {
+		h.respondCSV(w, statement)
+		return
+	}
+	h.respondJSON(w, http.StatusOK, statement)
+}
+
+// respondCSV writes the statement as CSV: an opening balance row, one row per
+// line, then the per-type totals and the closing balance
+func (h *StatementHandler) respondCSV(w http.ResponseWriter, statement *types.Statement) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"statement-%s-%s.csv\"", statement.AccountID, statement.Period))
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"posted_at", "transaction_id", "type", "direction", "amount_cents", "running_balance_cents", "description"})
+	_ = cw.Write([]string{statement.PeriodStart.Format(time.RFC3339), "", "OPENING_BALANCE", "", "", strconv.FormatInt(statement.OpeningBalanceCents, 10), ""})
+	for _, line := range statement.Lines {
+		transactionID := ""
+		if line.TransactionID != nil {
+			transactionID = line.TransactionID.String()
+		}
+		_ = cw.Write([]string{
+			line.PostedAt.UTC().Format(time.RFC3339Nano),
+			transactionID,
+			line.Type,
+			string(line.Direction),
+			strconv.FormatInt(line.AmountCents, 10),
+			strconv.FormatInt(line.RunningBalanceCents, 10),
+			line.Description,
+		})
+	}
+	for _, total := range statement.Totals {
+		_ = cw.Write([]string{"", "", "TOTAL_" + total.Type, string(types.PostingDirectionCredit), strconv.FormatInt(total.CreditsCents, 10), "", fmt.Sprintf("%d lines", total.Count)})
+		_ = cw.Write([]string{"", "", "TOTAL_" + total.Type, string(types.PostingDirectionDebit), strconv.FormatInt(total.DebitsCents, 10), "", fmt.Sprintf("%d lines", total.Count)})
+	}
+	_ = cw.Write([]string{statement.PeriodEnd.Format(time.RFC3339), "", "CLOSING_BALANCE", "", "", strconv.FormatInt(statement.ClosingBalanceCents, 10), ""})
+	cw.Flush()
+}
+
+func (h *StatementHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *StatementHandler) respondError(w http.ResponseWriter, status int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	response := map[string]string{
+		"error": message,
+	}
+	if err != nil {
+		response["details"] = err.Error()
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
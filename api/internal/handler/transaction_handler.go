@@ -2,12 +2,19 @@ package handler
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/yash/transaction-system/api/internal/service"
+	"github.com/yash/transaction-system/shared/currency"
 	"github.com/yash/transaction-system/shared/types"
 	"go.uber.org/zap"
 )
@@ -15,13 +22,15 @@ import (
 // TransactionHandler handles transaction HTTP requests
 type TransactionHandler struct {
 	transactionService *service.TransactionService
+	maxBatchSize       int
 	logger             *zap.Logger
 }
 
 // NewTransactionHandler creates a new transaction handler
-func NewTransactionHandler(transactionService *service.TransactionService, logger *zap.Logger) *TransactionHandler {
+func NewTransactionHandler(transactionService *service.TransactionService, maxBatchSize int, logger *zap.Logger) *TransactionHandler {
 	return &TransactionHandler{
 		transactionService: transactionService,
+		maxBatchSize:       maxBatchSize,
 		logger:             logger,
 	}
 }
@@ -34,44 +43,115 @@ func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	// Validate
-	if req.AccountID == uuid.Nil {
-		h.respondError(w, http.StatusBadRequest, "account_id is required", nil)
+	if message := validateCreateTransactionRequest(req); message != "" {
+		h.respondError(w, http.StatusBadRequest, message, nil)
 		return
 	}
-	if req.AmountCents <= 0 {
-		h.respondError(w, http.StatusBadRequest, "amount_cents must be positive", nil)
+
+	transaction, err := h.transactionService.CreateTransaction(r.Context(), req)
+	if err != nil {
+		status, message := createTransactionErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			h.logger.Error("Failed to create transaction", zap.Error(err))
+		}
+		h.respondError(w, status, message, err)
 		return
 	}
-	if req.Currency == "" {
-		h.respondError(w, http.StatusBadRequest, "currency is required", nil)
+
+	h.respondJSON(w, http.StatusCreated, transaction)
+}
+
+// CreateTransactionBatch handles POST /v1/transactions/batch
+func (h *TransactionHandler) CreateTransactionBatch(w http.ResponseWriter, r *http.Request) {
+	var req types.CreateTransactionBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
 		return
 	}
-	if req.IdempotencyKey == "" {
-		h.respondError(w, http.StatusBadRequest, "idempotency_key is required", nil)
+
+	if req.Mode == "" {
+		req.Mode = types.BatchModeAtomic
+	}
+	if req.Mode != types.BatchModeAtomic && req.Mode != types.BatchModeBestEffort {
+		h.respondError(w, http.StatusBadRequest, "mode must be ATOMIC or BEST_EFFORT", nil)
+		return
+	}
+	if len(req.Transactions) == 0 {
+		h.respondError(w, http.StatusBadRequest, "transactions is required", nil)
 		return
 	}
-	if req.Type != types.TransactionTypeDebit && req.Type != types.TransactionTypeCredit {
-		h.respondError(w, http.StatusBadRequest, "type must be DEBIT or CREDIT", nil)
+	if len(req.Transactions) > h.maxBatchSize {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("a batch may contain at most %d transactions", h.maxBatchSize), nil)
 		return
 	}
 
-	transaction, err := h.transactionService.CreateTransaction(r.Context(), req)
-	if err != nil {
-		if err.Error() == "account not found" {
-			h.respondError(w, http.StatusNotFound, "Account not found", err)
+	// Invalid items fail the whole batch in ATOMIC mode and are reported
+	// without reaching the service in BEST_EFFORT mode
+	var invalid []types.TransactionBatchItem
+	valid := make([]types.CreateTransactionRequest, 0, len(req.Transactions))
+	indexes := make([]int, 0, len(req.Transactions))
+	for i, item := range req.Transactions {
+		if message := validateCreateTransactionRequest(item); message != "" {
+			if req.Mode == types.BatchModeAtomic {
+				h.respondError(w, http.StatusBadRequest, fmt.Sprintf("transactions[%d]: %s", i, message), nil)
+				return
+			}
+			invalid = append(invalid, types.TransactionBatchItem{Index: i, Status: http.StatusBadRequest, Error: message})
+			continue
+		}
+		valid = append(valid, item)
+		indexes = append(indexes, i)
+	}
+
+	result := &types.TransactionBatchResult{Mode: req.Mode, Committed: true, Items: []types.TransactionBatchItem{}}
+	if len(valid) > 0 {
+		var err error
+		result, err = h.transactionService.CreateTransactionBatch(r.Context(), req.Mode, valid)
+		if err != nil && err.Error() == "batch conflicted with a concurrent request" {
+			// Nothing was committed; the same batch can be retried safely
+			w.Header().Set("Retry-After", "1")
+			h.respondError(w, http.StatusServiceUnavailable, "Batch conflicted with a concurrent request, retry it", err)
 			return
 		}
-		if err.Error() == "account is not active" {
-			h.respondError(w, http.StatusBadRequest, "Account is not active", err)
+		if err != nil && err.Error() != "batch rolled back" {
+			h.logger.Error("Failed to create transaction batch", zap.Error(err))
+			h.respondError(w, http.StatusInternalServerError, "Failed to create transaction batch", err)
 			return
 		}
-		h.logger.Error("Failed to create transaction", zap.Error(err))
-		h.respondError(w, http.StatusInternalServerError, "Failed to create transaction", err)
-		return
 	}
 
-	h.respondJSON(w, http.StatusCreated, transaction)
+	for i := range result.Items {
+		item := &result.Items[i]
+		item.Index = indexes[item.Index]
+		switch {
+		case item.Err != nil:
+			item.Status, item.Error = createTransactionErrorStatus(item.Err)
+			item.Details = item.Err.Error()
+			if item.Status == http.StatusInternalServerError {
+				h.logger.Error("Failed to create batch transaction", zap.Int("index", item.Index), zap.Error(item.Err))
+			}
+		case item.Committed:
+			item.Status = http.StatusCreated
+		default:
+			item.Status, item.Error = http.StatusFailedDependency, "Rolled back with the batch"
+		}
+	}
+	if len(invalid) > 0 {
+		result.FailedCount += len(invalid)
+		result.Items = append(result.Items, invalid...)
+		sort.Slice(result.Items, func(i, j int) bool {
+			return result.Items[i].Index < result.Items[j].Index
+		})
+	}
+
+	switch {
+	case !result.Committed:
+		h.respondJSON(w, http.StatusUnprocessableEntity, result)
+	case req.Mode == types.BatchModeAtomic:
+		h.respondJSON(w, http.StatusCreated, result)
+	default:
+		h.respondJSON(w, http.StatusOK, result)
+	}
 }
 
 // GetTransaction handles GET /v1/transactions/:id
@@ -97,12 +177,169 @@ func (h *TransactionHandler) GetTransaction(w http.ResponseWriter, r *http.Reque
 	h.respondJSON(w, http.StatusOK, transaction)
 }
 
-// ListTransactions handles GET /v1/transactions
+// GetTransactionHistory handles GET /v1/transactions/:id/history
+func (h *TransactionHandler) GetTransactionHistory(w http.ResponseWriter, r *http.Request) {
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid transaction ID", err)
+		return
+	}
+
+	history, err := h.transactionService.GetTransactionHistory(r.Context(), transactionID)
+	if err != nil {
+		if err.Error() == "transaction not found" {
+			h.respondError(w, http.StatusNotFound, "Transaction not found", err)
+			return
+		}
+		h.logger.Error("Failed to get transaction history", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get transaction history", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"items": history,
+	})
+}
+
+// CancelTransaction handles DELETE /v1/transactions/:id
+func (h *TransactionHandler) CancelTransaction(w http.ResponseWriter, r *http.Request) {
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid transaction ID", err)
+		return
+	}
+
+	transaction, err := h.transactionService.CancelScheduledTransaction(r.Context(), transactionID, actorFromRequest(r))
+	if err != nil {
+		switch err.Error() {
+		case "transaction not found":
+			h.respondError(w, http.StatusNotFound, "Transaction not found", err)
+		case "transaction is not scheduled":
+			h.respondError(w, http.StatusConflict, "Only scheduled transactions can be cancelled", err)
+		default:
+			h.logger.Error("Failed to cancel transaction", zap.Error(err))
+			h.respondError(w, http.StatusInternalServerError, "Failed to cancel transaction", err)
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, transaction)
+}
+
+// CancelPendingTransaction handles POST /v1/transactions/:id/cancel
+func (h *TransactionHandler) CancelPendingTransaction(w http.ResponseWriter, r *http.Request) {
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid transaction ID", err)
+		return
+	}
+
+	transaction, err := h.transactionService.CancelTransaction(r.Context(), transactionID, actorFromRequest(r))
+	if err != nil {
+		switch err.Error() {
+		case "transaction not found":
+			h.respondError(w, http.StatusNotFound, "Transaction not found", err)
+		case "transaction is not pending":
+			h.respondError(w, http.StatusConflict, "Only pending transactions can be cancelled", err)
+		case "transaction cannot be cancelled":
+			h.respondError(w, http.StatusConflict, "Transaction cannot be cancelled", err)
+		default:
+			h.logger.Error("Failed to cancel transaction", zap.Error(err))
+			h.respondError(w, http.StatusInternalServerError, "Failed to cancel transaction", err)
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, transaction)
+}
+
+// ApproveTransaction handles POST /v1/transactions/:id/approve
+func (h *TransactionHandler) ApproveTransaction(w http.ResponseWriter, r *http.Request) {
+	h.reviewTransaction(w, r, types.ReviewDecisionApproved)
+}
+
+// RejectTransaction handles POST /v1/transactions/:id/reject
+func (h *TransactionHandler) RejectTransaction(w http.ResponseWriter, r *http.Request) {
+	h.reviewTransaction(w, r, types.ReviewDecisionRejected)
+}
+
+// reviewTransaction resolves a transaction held for review. The body is optional.
+func (h *TransactionHandler) reviewTransaction(w http.ResponseWriter, r *http.Request, decision types.ReviewDecision) {
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid transaction ID", err)
+		return
+	}
+
+	var req types.ReviewTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	transaction, err := h.transactionService.ReviewTransaction(r.Context(), transactionID, decision, req.Note, actorFromRequest(r))
+	if err != nil {
+		switch err.Error() {
+		case "transaction not found":
+			h.respondError(w, http.StatusNotFound, "Transaction not found", err)
+		case "transaction is not in review":
+			h.respondError(w, http.StatusConflict, "Only transactions in review can be approved or rejected", err)
+		default:
+			h.logger.Error("Failed to review transaction", zap.Error(err))
+			h.respondError(w, http.StatusInternalServerError, "Failed to review transaction", err)
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, transaction)
+}
+
+// ReverseTransaction handles POST /v1/transactions/:id/reverse
+func (h *TransactionHandler) ReverseTransaction(w http.ResponseWriter, r *http.Request) {
+	transactionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid transaction ID", err)
+		return
+	}
+
+	var req types.ReverseTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	if req.AmountCents < 0 {
+		h.respondError(w, http.StatusBadRequest, "amount_cents must be positive", nil)
+		return
+	}
+	if req.IdempotencyKey == "" {
+		h.respondError(w, http.StatusBadRequest, "idempotency_key is required", nil)
+		return
+	}
+
+	transaction, err := h.transactionService.ReverseTransaction(r.Context(), transactionID, req)
+	if err != nil {
+		switch err.Error() {
+		case "transaction not found":
+			h.respondError(w, http.StatusNotFound, "Transaction not found", err)
+		case "idempotency key already used":
+			h.respondError(w, http.StatusConflict, "Idempotency key already used by another transaction", err)
+		case "transaction cannot be reversed", "transaction is not processed",
+			"transaction already fully reversed", "reversal exceeds remaining amount", "account is closed":
+			h.respondError(w, http.StatusUnprocessableEntity, "Transaction cannot be reversed", err)
+		default:
+			h.logger.Error("Failed to reverse transaction", zap.Error(err))
+			h.respondError(w, http.StatusInternalServerError, "Failed to reverse transaction", err)
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, transaction)
+}
+
+// ListTransactions handles GET /v1/transactions?cursor=&limit=
 func (h *TransactionHandler) ListTransactions(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters
-	accountIDStr := r.URL.Query().Get("account_id")
 	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
 
 	limit := 50
 	if limitStr != "" {
@@ -111,22 +348,18 @@ func (h *TransactionHandler) ListTransactions(w http.ResponseWriter, r *http.Req
 		}
 	}
 
-	offset := 0
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
-		}
-	}
-
-	var accountID *uuid.UUID
-	if accountIDStr != "" {
-		if id, err := uuid.Parse(accountIDStr); err == nil {
-			accountID = &id
-		}
+	filter, message := parseTransactionFilter(r.URL.Query())
+	if message != "" {
+		h.respondError(w, http.StatusBadRequest, message, nil)
+		return
 	}
 
-	transactions, err := h.transactionService.ListTransactions(r.Context(), accountID, limit, offset)
+	transactions, page, err := h.transactionService.ListTransactions(r.Context(), filter, r.URL.Query().Get("cursor"), limit)
 	if err != nil {
+		if err.Error() == "invalid cursor" {
+			h.respondError(w, http.StatusBadRequest, "Invalid cursor", err)
+			return
+		}
 		h.logger.Error("Failed to list transactions", zap.Error(err))
 		h.respondError(w, http.StatusInternalServerError, "Failed to list transactions", err)
 		return
@@ -135,10 +368,182 @@ func (h *TransactionHandler) ListTransactions(w http.ResponseWriter, r *http.Req
 	h.respondJSON(w, http.StatusOK, map[string]interface{}{
 		"transactions": transactions,
 		"limit":        limit,
-		"offset":       offset,
+		"next_cursor":  page.NextCursor,
+		"prev_cursor":  page.PrevCursor,
 	})
 }
 
+// parseTransactionFilter reads the filters of a transaction list from its
+// query parameters. It returns why a parameter is invalid, or an empty string.
+func parseTransactionFilter(query url.Values) (types.TransactionFilter, string) {
+	var filter types.TransactionFilter
+
+	if value := query.Get("account_id"); value != "" {
+		accountID, err := uuid.Parse(value)
+		if err != nil {
+			return filter, "account_id must be a UUID"
+		}
+		filter.AccountID = &accountID
+	}
+	if value := query.Get("status"); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			status := types.TransactionStatus(strings.TrimSpace(part))
+			if !status.Valid() {
+				return filter, fmt.Sprintf("invalid status %q", status)
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+	if value := query.Get("type"); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			txType := types.TransactionType(strings.TrimSpace(part))
+			if !txType.Valid() {
+				return filter, fmt.Sprintf("invalid type %q", txType)
+			}
+			filter.Types = append(filter.Types, txType)
+		}
+	}
+	if value := query.Get("currency"); value != "" {
+		if err := currency.Validate("currency", value); err != nil {
+			return filter, err.Error()
+		}
+		filter.Currency = value
+	}
+
+	var message string
+	if filter.CreatedFrom, filter.CreatedTo, message = parseCreatedRange(query); message != "" {
+		return filter, message
+	}
+
+	for _, param := range []string{"min_amount_cents", "max_amount_cents"} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		amountCents, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || amountCents < 0 {
+			return filter, param + " must be a non-negative integer"
+		}
+		if param == "min_amount_cents" {
+			filter.MinAmountCents = &amountCents
+		} else {
+			filter.MaxAmountCents = &amountCents
+		}
+	}
+	if filter.MinAmountCents != nil && filter.MaxAmountCents != nil && *filter.MinAmountCents > *filter.MaxAmountCents {
+		return filter, "min_amount_cents must not exceed max_amount_cents"
+	}
+
+	// metadata[key]=value matches transactions whose metadata contains the pair
+	for param, values := range query {
+		if !strings.HasPrefix(param, "metadata[") || !strings.HasSuffix(param, "]") {
+			continue
+		}
+		key := strings.TrimSuffix(strings.TrimPrefix(param, "metadata["), "]")
+		if key == "" {
+			return filter, "metadata filters must name a key: metadata[key]=value"
+		}
+		if filter.Metadata == nil {
+			filter.Metadata = make(map[string]string)
+		}
+		filter.Metadata[key] = values[0]
+	}
+
+	if value := query.Get("sort"); value != "" {
+		filter.Sort = types.TransactionSort(value)
+		if !filter.Sort.Valid() {
+			return filter, "sort must be created_at, updated_at or amount_cents, optionally prefixed with -"
+		}
+	}
+
+	return filter, ""
+}
+
+// parseCreatedRange reads the created_from (inclusive) and created_to
+// (exclusive) query parameters of a list. A bare created_to date includes that
+// whole day. It returns why a parameter is invalid, or an empty string.
+func parseCreatedRange(query url.Values) (*time.Time, *time.Time, string) {
+	var createdFrom, createdTo *time.Time
+	if value := query.Get("created_from"); value != "" {
+		parsed, _, err := parseTimeParam(value)
+		if err != nil {
+			return nil, nil, "created_from must be an RFC 3339 timestamp or YYYY-MM-DD"
+		}
+		createdFrom = &parsed
+	}
+	if value := query.Get("created_to"); value != "" {
+		parsed, dateOnly, err := parseTimeParam(value)
+		if err != nil {
+			return nil, nil, "created_to must be an RFC 3339 timestamp or YYYY-MM-DD"
+		}
+		if dateOnly {
+			parsed = parsed.AddDate(0, 0, 1)
+		}
+		createdTo = &parsed
+	}
+	if createdFrom != nil && createdTo != nil && !createdFrom.Before(*createdTo) {
+		return nil, nil, "created_from must be before created_to"
+	}
+	return createdFrom, createdTo, ""
+}
+
+// validateCreateTransactionRequest returns why a create request is invalid,
+// or an empty string if it is valid
+func validateCreateTransactionRequest(req types.CreateTransactionRequest) string {
+	if req.AccountID == uuid.Nil {
+		return "account_id is required"
+	}
+	if req.AmountCents < 0 || (req.AmountCents == 0 && req.Type != types.TransactionTypeVoid) {
+		return "amount_cents must be positive"
+	}
+	if err := currency.Validate("currency", req.Currency); err != nil {
+		return err.Error()
+	}
+	if req.IdempotencyKey == "" {
+		return "idempotency_key is required"
+	}
+	switch req.Type {
+	case types.TransactionTypeDebit, types.TransactionTypeCredit, types.TransactionTypeAuthorize:
+	case types.TransactionTypeCapture, types.TransactionTypeVoid:
+		if req.AuthorizationID == nil {
+			return "authorization_id is required for CAPTURE and VOID"
+		}
+	default:
+		return "type must be DEBIT, CREDIT, AUTHORIZE, CAPTURE or VOID"
+	}
+	if req.ExecuteAt != nil {
+		if req.Type != types.TransactionTypeDebit && req.Type != types.TransactionTypeCredit {
+			return "only DEBIT and CREDIT transactions can be scheduled"
+		}
+		if req.FXQuoteID != nil {
+			return "fx_quote_id cannot be combined with execute_at"
+		}
+	}
+	return ""
+}
+
+// createTransactionErrorStatus maps a CreateTransaction error to its HTTP status and message
+func createTransactionErrorStatus(err error) (int, string) {
+	switch err.Error() {
+	case "account not found":
+		return http.StatusNotFound, "Account not found"
+	case "account is not active":
+		return http.StatusBadRequest, "Account is not active"
+	case "unsupported currency":
+		return http.StatusBadRequest, "Unsupported currency"
+	case "authorization not found":
+		return http.StatusNotFound, "Authorization not found"
+	case "authorization is not active", "amount exceeds authorized amount":
+		return http.StatusUnprocessableEntity, "Invalid authorization reference"
+	case "fx quote not found":
+		return http.StatusNotFound, "FX quote not found"
+	case "fx quote does not match currency pair", "fx quote has expired":
+		return http.StatusUnprocessableEntity, "Invalid FX quote"
+	default:
+		return http.StatusInternalServerError, "Failed to create transaction"
+	}
+}
+
 func (h *TransactionHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/api/internal/service"
+	"github.com/yash/transaction-system/shared/currency"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// TransferHandler handles transfer HTTP requests
+type TransferHandler struct {
+	transferService *service.TransferService
+	logger          *zap.Logger
+}
+
+// NewTransferHandler creates a new transfer handler
+func NewTransferHandler(transferService *service.TransferService, logger *zap.Logger) *TransferHandler {
+	return &TransferHandler{
+		transferService: transferService,
+		logger:          logger,
+	}
+}
+
+// CreateTransfer handles POST /v1/transfers
+func (h *TransferHandler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
+	var req types.CreateTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+
+	// Validate
+	if req.FromAccountID == uuid.Nil {
+		h.respondError(w, http.StatusBadRequest, "from_account_id is required", nil)
+		return
+	}
+	if req.ToAccountID == uuid.Nil {
+		h.respondError(w, http.StatusBadRequest, "to_account_id is required", nil)
+		return
+	}
+	if req.FromAccountID == req.ToAccountID {
+		h.respondError(w, http.StatusBadRequest, "from_account_id and to_account_id must differ", nil)
+		return
+	}
+	if req.AmountCents <= 0 {
+		h.respondError(w, http.StatusBadRequest, "amount_cents must be positive", nil)
+		return
+	}
+	if err := currency.Validate("currency", req.Currency); err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if req.IdempotencyKey == "" {
+		h.respondError(w, http.StatusBadRequest, "idempotency_key is required", nil)
+		return
+	}
+
+	transfer, err := h.transferService.CreateTransfer(r.Context(), req)
+	if err != nil {
+		switch err.Error() {
+		case "account not found":
+			h.respondError(w, http.StatusNotFound, "Account not found", err)
+			return
+		case "account is not active":
+			h.respondError(w, http.StatusBadRequest, "Account is not active", err)
+			return
+		case "unsupported currency":
+			h.respondError(w, http.StatusBadRequest, "Unsupported currency", err)
+			return
+		case "currency mismatch":
+			h.respondError(w, http.StatusBadRequest, "Currency does not match both accounts", err)
+			return
+		}
+		h.logger.Error("Failed to create transfer", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to create transfer", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, transfer)
+}
+
+// GetTransfer handles GET /v1/transfers/:id
+func (h *TransferHandler) GetTransfer(w http.ResponseWriter, r *http.Request) {
+	transferID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid transfer ID", err)
+		return
+	}
+
+	transfer, err := h.transferService.GetTransfer(r.Context(), transferID)
+	if err != nil {
+		if err.Error() == "transfer not found" {
+			h.respondError(w, http.StatusNotFound, "Transfer not found", err)
+			return
+		}
+		h.logger.Error("Failed to get transfer", zap.Error(err))
+		h.respondError(w, http.StatusInternalServerError, "Failed to get transfer", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, transfer)
+}
+
+func (h *TransferHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func (h *TransferHandler) respondError(w http.ResponseWriter, status int, message string, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	response := map[string]string{
+		"error": message,
+	}
+	if err != nil {
+		response["details"] = err.Error()
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -3,15 +3,18 @@ package middleware
 import (
 	"net/http"
 	"strings"
+
+	"github.com/yash/transaction-system/shared/tenant"
 )
 
-// APIKeyAuth middleware validates API key
-func APIKeyAuth(expectedKey string) func(http.Handler) http.Handler {
+// APIKeyAuth middleware validates the API key and puts its tenant in the
+// request context. keys maps each accepted key to its tenant ID.
+func APIKeyAuth(keys map[string]string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if expectedKey == "" {
-				// No auth required
-				next.ServeHTTP(w, r)
+			if len(keys) == 0 {
+				// No auth required; every request belongs to the default tenant
+				next.ServeHTTP(w, r.WithContext(tenant.WithID(r.Context(), tenant.Default)))
 				return
 			}
 
@@ -23,13 +26,32 @@ func APIKeyAuth(expectedKey string) func(http.Handler) http.Handler {
 				}
 			}
 
-			if authHeader != expectedKey {
+			tenantID, ok := keys[authHeader]
+			if authHeader == "" || !ok {
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(http.StatusUnauthorized)
 				_, _ = w.Write([]byte(`{"error": "Unauthorized"}`))
 				return
 			}
 
+			next.ServeHTTP(w, r.WithContext(tenant.WithID(r.Context(), tenantID)))
+		})
+	}
+}
+
+// RequireTenant middleware rejects requests of any tenant but tenantID. It
+// guards the configuration shared by all tenants, which only the operator of
+// the deployment may change.
+func RequireTenant(tenantID string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if tenant.FromContext(r.Context()) != tenantID {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"error": "Forbidden"}`))
+				return
+			}
+
 			next.ServeHTTP(w, r)
 		})
 	}
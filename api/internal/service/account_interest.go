@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/fx"
+	"github.com/yash/transaction-system/shared/interest"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// SetInterest sets or clears an account's annual interest rate and day count
+// convention. Interest starts accruing from the day it is first set; clearing
+// it stops accrual, and interest already accrued is still posted at month
+// end. Every change is written to the audit trail.
+func (s *AccountService) SetInterest(ctx context.Context, accountID uuid.UUID, req types.SetInterestRequest, actor string) (*types.Account, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var rate *string
+	var dayCount *types.DayCountConvention
+	if req.AnnualRate != nil {
+		parsed, err := interest.ParseRate(*req.AnnualRate)
+		if err != nil {
+			return nil, err
+		}
+		formatted := fx.FormatRate(parsed)
+		rate = &formatted
+		convention := req.DayCount
+		if convention == "" {
+			convention = types.DayCountActual365
+		}
+		dayCount = &convention
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	tenantID := tenant.FromContext(ctx)
+	if err := tenant.Scope(ctx, tx, tenantID); err != nil {
+		return nil, err
+	}
+
+	var oldRate *string
+	var oldDayCount *types.DayCountConvention
+	lockQuery := `SELECT interest_rate, interest_day_count FROM accounts WHERE id = $1 AND tenant_id = $2 AND system_code IS NULL FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, lockQuery, accountID, tenantID).Scan(&oldRate, &oldDayCount); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("account not found")
+		}
+		return nil, fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	updateQuery := `
+		UPDATE accounts
+		SET interest_rate = $1::numeric,
+		    interest_day_count = $2,
+		    interest_start_date = CASE
+		        WHEN $1::numeric IS NULL THEN NULL
+		        ELSE COALESCE(interest_start_date, (NOW() AT TIME ZONE 'UTC')::date)
+		    END,
+		    updated_at = NOW()
+		WHERE id = $3 AND tenant_id = $4
+		RETURNING ` + accountColumns + `
+	`
+	var account types.Account
+	if err := scanAccount(tx.QueryRowContext(ctx, updateQuery, rate, dayCount, accountID, tenantID), &account); err != nil {
+		return nil, fmt.Errorf("failed to set interest: %w", err)
+	}
+
+	if oldRate != nil {
+		normalized := normalizeRate(*oldRate)
+		oldRate = &normalized
+	}
+	details := map[string]interface{}{
+		"old_annual_rate": oldRate,
+		"old_day_count":   oldDayCount,
+		"new_annual_rate": rate,
+		"new_day_count":   dayCount,
+	}
+	if err := writeAuditLog(ctx, tx, types.AuditActionInterestUpdated, types.AuditEntityAccount, accountID, actor, details); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Account interest updated",
+		zap.String("account_id", accountID.String()),
+		zap.Stringp("annual_rate", rate),
+		zap.String("actor", actor),
+	)
+
+	return &account, nil
+}
+
+// interestAccrualOrder is the keyset order of an account's interest
+// accruals, newest day first. Accruals have no id; a list only holds one
+// account, where the accrual date is unique, so the account ID breaks ties
+// and the order follows the primary key.
+var interestAccrualOrder = keysetOrder{sort: "-accrual_date", column: "accrual_date", cast: "date", desc: true, idColumn: "account_id"}
+
+// ListInterestAccruals returns the daily interest accruals of an account a
+// page at a time, newest first
+func (s *AccountService) ListInterestAccruals(ctx context.Context, accountID uuid.UUID, cursorToken string, limit int) ([]types.InterestAccrual, types.PageCursors, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	order := interestAccrualOrder.withFilter(map[string]interface{}{"account_id": accountID})
+	after, err := decodeCursor(cursorToken, order)
+	if err != nil {
+		return nil, types.PageCursors{}, err
+	}
+
+	var exists bool
+	existsQuery := `SELECT EXISTS (SELECT 1 FROM accounts WHERE id = $1 AND tenant_id = $2 AND system_code IS NULL)`
+	if err := s.db.QueryRowContext(ctx, existsQuery, accountID, tenant.FromContext(ctx)).Scan(&exists); err != nil {
+		return nil, types.PageCursors{}, fmt.Errorf("failed to check account: %w", err)
+	}
+	if !exists {
+		return nil, types.PageCursors{}, fmt.Errorf("account not found")
+	}
+
+	args := []interface{}{accountID}
+	conditions := []string{"account_id = $1"}
+	keysetCondition, args, orderBy := order.clauses(after, args)
+	if keysetCondition != "" {
+		conditions = append(conditions, keysetCondition)
+	}
+
+	// One row more than the page shows whether another page follows
+	args = append(args, limit+1)
+	query := fmt.Sprintf(`
+		SELECT account_id, accrual_date::text, balance_cents, annual_rate::text, day_count,
+		       amount_cents::text, posted_at, transaction_id, created_at
+		FROM interest_accruals
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), orderBy, len(args))
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, types.PageCursors{}, fmt.Errorf("failed to query interest accruals: %w", err)
+	}
+	defer rows.Close()
+
+	accruals := []types.InterestAccrual{}
+	for rows.Next() {
+		var accrual types.InterestAccrual
+		err := rows.Scan(
+			&accrual.AccountID, &accrual.AccrualDate, &accrual.BalanceCents, &accrual.AnnualRate, &accrual.DayCount,
+			&accrual.AmountCents, &accrual.PostedAt, &accrual.TransactionID, &accrual.CreatedAt,
+		)
+		if err != nil {
+			return nil, types.PageCursors{}, fmt.Errorf("failed to scan interest accrual: %w", err)
+		}
+		accrual.AnnualRate = normalizeRate(accrual.AnnualRate)
+		accruals = append(accruals, accrual)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, types.PageCursors{}, fmt.Errorf("failed to iterate interest accruals: %w", err)
+	}
+
+	hasMore := len(accruals) > limit
+	if hasMore {
+		accruals = accruals[:limit]
+	}
+	if after != nil && after.Backward {
+		for i, j := 0, len(accruals)-1; i < j; i, j = i+1, j-1 {
+			accruals[i], accruals[j] = accruals[j], accruals[i]
+		}
+	}
+
+	var first, last *cursor
+	if len(accruals) > 0 {
+		first = &cursor{ID: accountID, Value: accruals[0].AccrualDate}
+		last = &cursor{ID: accountID, Value: accruals[len(accruals)-1].AccrualDate}
+	}
+	return accruals, order.pageCursors(after, first, last, hasMore), nil
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yash/transaction-system/shared/currency"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/types"
+)
+
+// accountSortOrders maps each sort option to its keyset order
+var accountSortOrders = map[types.AccountSort]keysetOrder{
+	types.AccountSortCreatedAtDesc:    {sort: "-created_at", column: "created_at", cast: "timestamptz", desc: true},
+	types.AccountSortCreatedAtAsc:     {sort: "created_at", column: "created_at", cast: "timestamptz"},
+	types.AccountSortBalanceCentsDesc: {sort: "-balance_cents", column: "balance_cents", cast: "bigint", desc: true},
+	types.AccountSortBalanceCentsAsc:  {sort: "balance_cents", column: "balance_cents", cast: "bigint"},
+}
+
+// accountPosition returns the cursor position of an account in a list sorted
+// by order
+func accountPosition(order keysetOrder, account types.Account) *cursor {
+	position := &cursor{Sort: order.sort, ID: account.ID}
+	if order.column == "balance_cents" {
+		position.Value = strconv.FormatInt(account.BalanceCents, 10)
+	} else {
+		position.Value = account.CreatedAt.Format(time.RFC3339Nano)
+	}
+	return position
+}
+
+// accountFilterConditions returns the WHERE conditions and arguments selecting
+// the customer accounts of the tenant of ctx that match the filter. System
+// accounts of the ledger are never listed.
+func accountFilterConditions(ctx context.Context, filter types.AccountFilter) ([]string, []interface{}) {
+	args := []interface{}{tenant.FromContext(ctx)}
+	conditions := []string{"tenant_id = $1", "system_code IS NULL"}
+	addCondition := func(format string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
+		}
+		addCondition("status = ANY($%d)", pq.Array(statuses))
+	}
+	if filter.Currency != "" {
+		addCondition("currency = $%d", filter.Currency)
+	}
+	if filter.CreatedFrom != nil {
+		addCondition("created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		addCondition("created_at < $%d", *filter.CreatedTo)
+	}
+	if filter.MinBalanceCents != nil {
+		addCondition("balance_cents >= $%d", *filter.MinBalanceCents)
+	}
+	if filter.MaxBalanceCents != nil {
+		addCondition("balance_cents <= $%d", *filter.MaxBalanceCents)
+	}
+	if filter.OwnerID != nil {
+		addCondition("EXISTS (SELECT 1 FROM account_owners o WHERE o.account_id = accounts.id AND o.customer_id = $%d)", *filter.OwnerID)
+	}
+	return conditions, args
+}
+
+// ListAccounts lists the accounts matching the filter a page at a time,
+// newest first unless the filter sets another order. cursorToken is a cursor
+// of a previous page, which must have used the same filter; an empty token
+// starts at the first page.
+func (s *AccountService) ListAccounts(ctx context.Context, filter types.AccountFilter, cursorToken string, limit int) ([]types.Account, types.PageCursors, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	order, ok := accountSortOrders[filter.Sort]
+	if !ok {
+		order = accountSortOrders[types.AccountSortCreatedAtDesc]
+	}
+	order = order.withFilter(filter)
+	after, err := decodeCursor(cursorToken, order)
+	if err != nil {
+		return nil, types.PageCursors{}, err
+	}
+
+	conditions, args := accountFilterConditions(ctx, filter)
+	keysetCondition, args, orderBy := order.clauses(after, args)
+	if keysetCondition != "" {
+		conditions = append(conditions, keysetCondition)
+	}
+
+	// One row more than the page shows whether another page follows
+	args = append(args, limit+1)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM accounts
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d
+	`, accountColumns, strings.Join(conditions, " AND "), orderBy, len(args))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, types.PageCursors{}, fmt.Errorf("failed to query accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []types.Account
+	for rows.Next() {
+		var account types.Account
+		if err := scanAccount(rows, &account); err != nil {
+			return nil, types.PageCursors{}, fmt.Errorf("failed to scan account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, types.PageCursors{}, fmt.Errorf("failed to iterate accounts: %w", err)
+	}
+
+	hasMore := len(accounts) > limit
+	if hasMore {
+		accounts = accounts[:limit]
+	}
+	if after != nil && after.Backward {
+		for i, j := 0, len(accounts)-1; i < j; i, j = i+1, j-1 {
+			accounts[i], accounts[j] = accounts[j], accounts[i]
+		}
+	}
+
+	var first, last *cursor
+	if len(accounts) > 0 {
+		first = accountPosition(order, accounts[0])
+		last = accountPosition(order, accounts[len(accounts)-1])
+	}
+	return accounts, order.pageCursors(after, first, last, hasMore), nil
+}
+
+// AccountTotals totals the balances of every account matching the filter per
+// currency, across all pages
+func (s *AccountService) AccountTotals(ctx context.Context, filter types.AccountFilter) ([]types.AccountTotal, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	conditions, args := accountFilterConditions(ctx, filter)
+	query := `
+		SELECT currency, COUNT(*), COALESCE(SUM(balance_cents), 0), COALESCE(SUM(held_cents), 0)
+		FROM accounts
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		GROUP BY currency
+		ORDER BY currency
+	`
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total accounts: %w", err)
+	}
+	defer rows.Close()
+
+	totals := []types.AccountTotal{}
+	for rows.Next() {
+		var total types.AccountTotal
+		if err := rows.Scan(&total.Currency, &total.AccountCount, &total.BalanceCents, &total.HeldCents); err != nil {
+			return nil, fmt.Errorf("failed to scan account total: %w", err)
+		}
+		total.AvailableBalanceCents = total.BalanceCents - total.HeldCents
+		total.BalanceDecimal = currency.Format(total.BalanceCents, total.Currency)
+		total.AvailableBalanceDecimal = currency.Format(total.AvailableBalanceCents, total.Currency)
+		totals = append(totals, total)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate account totals: %w", err)
+	}
+	return totals, nil
+}
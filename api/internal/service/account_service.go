@@ -8,6 +8,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/currency"
+	"github.com/yash/transaction-system/shared/outbox"
+	"github.com/yash/transaction-system/shared/tenant"
 	"github.com/yash/transaction-system/shared/types"
 	"go.uber.org/zap"
 )
@@ -28,54 +31,311 @@ func NewAccountService(db *sql.DB, logger *zap.Logger) *AccountService {
 	}
 }
 
-// CreateAccount creates a new account
-func (s *AccountService) CreateAccount(ctx context.Context, req types.CreateAccountRequest) (*types.Account, error) {
+// CreateAccount creates a new account, owned by CustomerID and any joint
+// owners if given. A non-zero overdraft limit is recorded in the audit trail
+// under the given actor.
+func (s *AccountService) CreateAccount(ctx context.Context, req types.CreateAccountRequest, actor string) (*types.Account, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	id := uuid.New()
 	now := time.Now()
 
+	if !currency.Valid(req.Currency) {
+		return nil, fmt.Errorf("unsupported currency")
+	}
+	if req.Kind == "" {
+		req.Kind = types.AccountKindStandard
+	}
+	if req.Tier == "" {
+		req.Tier = types.DefaultAccountTier
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	tenantID := tenant.FromContext(ctx)
+	if err := tenant.Scope(ctx, tx, tenantID); err != nil {
+		return nil, err
+	}
+
 	query := `
-		INSERT INTO accounts (id, currency, balance_cents, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, created_at, updated_at, currency, balance_cents, status
+		INSERT INTO accounts (id, tenant_id, currency, balance_cents, status, kind, tier, overdraft_limit_cents, customer_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING ` + accountColumns + `
 	`
 
+	// Owners are checked first so a missing customer is not a foreign key error
+	if req.CustomerID != nil {
+		if err := checkCustomersExist(ctx, tx, append([]uuid.UUID{*req.CustomerID}, req.JointOwnerIDs...)); err != nil {
+			return nil, err
+		}
+	}
+
 	var account types.Account
-	err := s.db.QueryRowContext(ctx, query,
-		id, req.Currency, 0, types.AccountStatusActive, now, now,
-	).Scan(
-		&account.ID, &account.CreatedAt, &account.UpdatedAt,
-		&account.Currency, &account.BalanceCents, &account.Status,
-	)
+	err = scanAccount(tx.QueryRowContext(ctx, query,
+		id, tenantID, req.Currency, 0, types.AccountStatusActive, req.Kind, req.Tier, req.OverdraftLimitCents, req.CustomerID, now, now,
+	), &account)
 
 	if err != nil {
 		s.logger.Error("Failed to create account", zap.Error(err))
 		return nil, fmt.Errorf("failed to create account: %w", err)
 	}
 
+	if req.CustomerID != nil {
+		if err := addAccountOwners(ctx, tx, account.ID, *req.CustomerID, req.JointOwnerIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if account.OverdraftLimitCents > 0 {
+		details := map[string]interface{}{
+			"old_overdraft_limit_cents": 0,
+			"new_overdraft_limit_cents": account.OverdraftLimitCents,
+			"reason":                    "account created",
+		}
+		if err := writeAuditLog(ctx, tx, types.AuditActionOverdraftLimitUpdated, types.AuditEntityAccount, account.ID, actor, details); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	UpdateAccountBalanceMetric(account.ID.String(), account.Currency, account.BalanceCents)
 	s.logger.Info("Account created", zap.String("account_id", account.ID.String()))
 	return &account, nil
 }
 
+// UpdateOverdraftLimit sets how far debits may take the account balance below
+// zero. Lowering the limit below the amount currently drawn is allowed; it only
+// blocks further debits. Every change is written to the audit trail.
+func (s *AccountService) UpdateOverdraftLimit(ctx context.Context, accountID uuid.UUID, req types.UpdateOverdraftLimitRequest, actor string) (*types.Account, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	tenantID := tenant.FromContext(ctx)
+	if err := tenant.Scope(ctx, tx, tenantID); err != nil {
+		return nil, err
+	}
+
+	var oldLimit int64
+	lockQuery := `SELECT overdraft_limit_cents FROM accounts WHERE id = $1 AND tenant_id = $2 AND system_code IS NULL FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, lockQuery, accountID, tenantID).Scan(&oldLimit); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("account not found")
+		}
+		return nil, fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	updateQuery := `
+		UPDATE accounts
+		SET overdraft_limit_cents = $1, updated_at = NOW()
+		WHERE id = $2 AND tenant_id = $3
+		RETURNING ` + accountColumns + `
+	`
+	var account types.Account
+	if err := scanAccount(tx.QueryRowContext(ctx, updateQuery, req.OverdraftLimitCents, accountID, tenantID), &account); err != nil {
+		return nil, fmt.Errorf("failed to update overdraft limit: %w", err)
+	}
+
+	if oldLimit != req.OverdraftLimitCents {
+		details := map[string]interface{}{
+			"old_overdraft_limit_cents": oldLimit,
+			"new_overdraft_limit_cents": req.OverdraftLimitCents,
+			"reason":                    req.Reason,
+		}
+		if err := writeAuditLog(ctx, tx, types.AuditActionOverdraftLimitUpdated, types.AuditEntityAccount, accountID, actor, details); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Overdraft limit updated",
+		zap.String("account_id", accountID.String()),
+		zap.Int64("old_overdraft_limit_cents", oldLimit),
+		zap.Int64("new_overdraft_limit_cents", req.OverdraftLimitCents),
+		zap.String("actor", actor),
+	)
+
+	return &account, nil
+}
+
+// accountTransition is the event and audit action recorded for a status change
+type accountTransition struct {
+	eventType   string
+	auditAction string
+}
+
+// accountTransitions lists the statuses an account may move to from each status
+var accountTransitions = map[types.AccountStatus]map[types.AccountStatus]accountTransition{
+	types.AccountStatusActive: {
+		types.AccountStatusSuspended: {types.EventTypeAccountSuspended, types.AuditActionAccountSuspended},
+		types.AccountStatusClosed:    {types.EventTypeAccountClosed, types.AuditActionAccountClosed},
+	},
+	types.AccountStatusSuspended: {
+		types.AccountStatusActive: {types.EventTypeAccountReactivated, types.AuditActionAccountReactivated},
+		types.AccountStatusClosed: {types.EventTypeAccountClosed, types.AuditActionAccountClosed},
+	},
+}
+
+// ChangeStatus suspends, reactivates or closes an account. Closing requires a
+// zero balance, no held funds and no scheduled or in-flight transactions;
+// CLOSED is terminal. Every change is audited and emitted as an account event.
+func (s *AccountService) ChangeStatus(ctx context.Context, accountID uuid.UUID, newStatus types.AccountStatus, req types.ChangeAccountStatusRequest, actor string) (*types.Account, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	tenantID := tenant.FromContext(ctx)
+	if err := tenant.Scope(ctx, tx, tenantID); err != nil {
+		return nil, err
+	}
+
+	// The lock serializes with the worker, which locks the account before
+	// applying a transaction
+	var oldStatus types.AccountStatus
+	var balanceCents, heldCents int64
+	lockQuery := `SELECT status, balance_cents, held_cents FROM accounts WHERE id = $1 AND tenant_id = $2 AND system_code IS NULL FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, lockQuery, accountID, tenantID).Scan(&oldStatus, &balanceCents, &heldCents); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("account not found")
+		}
+		return nil, fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	transition, ok := accountTransitions[oldStatus][newStatus]
+	if !ok {
+		return nil, fmt.Errorf("invalid status transition")
+	}
+
+	if newStatus == types.AccountStatusClosed {
+		if balanceCents != 0 {
+			return nil, fmt.Errorf("account balance is not zero")
+		}
+		if heldCents != 0 {
+			return nil, fmt.Errorf("account has held funds")
+		}
+		var inFlight bool
+		inFlightQuery := `SELECT EXISTS (SELECT 1 FROM transactions WHERE account_id = $1 AND tenant_id = $2 AND status IN ('SCHEDULED', 'PENDING', 'IN_REVIEW', 'PROCESSING'))`
+		if err := tx.QueryRowContext(ctx, inFlightQuery, accountID, tenantID).Scan(&inFlight); err != nil {
+			return nil, fmt.Errorf("failed to check pending transactions: %w", err)
+		}
+		if inFlight {
+			return nil, fmt.Errorf("account has pending transactions")
+		}
+	}
+
+	updateQuery := `
+		UPDATE accounts
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2 AND tenant_id = $3
+		RETURNING ` + accountColumns + `
+	`
+	var account types.Account
+	if err := scanAccount(tx.QueryRowContext(ctx, updateQuery, newStatus, accountID, tenantID), &account); err != nil {
+		return nil, fmt.Errorf("failed to update account status: %w", err)
+	}
+
+	details := map[string]interface{}{
+		"old_status":  oldStatus,
+		"new_status":  newStatus,
+		"reason_code": req.ReasonCode,
+		"note":        req.Note,
+	}
+	if err := writeAuditLog(ctx, tx, transition.auditAction, types.AuditEntityAccount, accountID, actor, details); err != nil {
+		return nil, err
+	}
+
+	payload := types.AccountStatusChangedPayload{
+		AccountID:  accountID,
+		OldStatus:  oldStatus,
+		NewStatus:  newStatus,
+		ReasonCode: req.ReasonCode,
+		Note:       req.Note,
+		Actor:      actor,
+		ChangedAt:  account.UpdatedAt,
+	}
+	if err := outbox.Write(ctx, tx, "account", accountID, transition.eventType, payload); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Account status changed",
+		zap.String("account_id", accountID.String()),
+		zap.String("old_status", string(oldStatus)),
+		zap.String("new_status", string(newStatus)),
+		zap.String("reason_code", string(req.ReasonCode)),
+		zap.String("actor", actor),
+	)
+
+	return &account, nil
+}
+
+// ListAuditLogs returns the audit trail of an account a page at a time,
+// newest first
+func (s *AccountService) ListAuditLogs(ctx context.Context, accountID uuid.UUID, cursorToken string, limit int) ([]types.AuditLog, types.PageCursors, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var exists bool
+	existsQuery := `SELECT EXISTS (SELECT 1 FROM accounts WHERE id = $1 AND tenant_id = $2 AND system_code IS NULL)`
+	if err := s.db.QueryRowContext(ctx, existsQuery, accountID, tenant.FromContext(ctx)).Scan(&exists); err != nil {
+		return nil, types.PageCursors{}, fmt.Errorf("failed to check account: %w", err)
+	}
+	if !exists {
+		return nil, types.PageCursors{}, fmt.Errorf("account not found")
+	}
+
+	return listAuditLogs(ctx, s.db, types.AuditEntityAccount, accountID, cursorToken, limit)
+}
+
 // GetAccount retrieves an account by ID
 func (s *AccountService) GetAccount(ctx context.Context, accountID uuid.UUID) (*types.Account, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	query := `
-		SELECT id, created_at, updated_at, currency, balance_cents, status
+		SELECT ` + accountColumns + `
 		FROM accounts
-		WHERE id = $1
+		WHERE id = $1 AND tenant_id = $2 AND system_code IS NULL
 	`
 
 	var account types.Account
-	err := s.db.QueryRowContext(ctx, query, accountID).Scan(
-		&account.ID, &account.CreatedAt, &account.UpdatedAt,
-		&account.Currency, &account.BalanceCents, &account.Status,
-	)
+	err := scanAccount(s.db.QueryRowContext(ctx, query, accountID, tenant.FromContext(ctx)), &account)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -88,3 +348,28 @@ func (s *AccountService) GetAccount(ctx context.Context, accountID uuid.UUID) (*
 	UpdateAccountBalanceMetric(account.ID.String(), account.Currency, account.BalanceCents)
 	return &account, nil
 }
+
+// accountColumns is the column list read by scanAccount
+const accountColumns = `id, tenant_id, created_at, updated_at, currency, balance_cents, held_cents,
+		       overdraft_limit_cents, kind, tier, customer_id, interest_rate, interest_day_count, status`
+
+// scanAccount scans a row selected with accountColumns
+func scanAccount(row rowScanner, account *types.Account) error {
+	err := row.Scan(
+		&account.ID, &account.TenantID, &account.CreatedAt, &account.UpdatedAt,
+		&account.Currency, &account.BalanceCents, &account.HeldCents,
+		&account.OverdraftLimitCents, &account.Kind, &account.Tier, &account.CustomerID,
+		&account.InterestRate, &account.InterestDayCount, &account.Status,
+	)
+	if err != nil {
+		return err
+	}
+	if account.InterestRate != nil {
+		rate := normalizeRate(*account.InterestRate)
+		account.InterestRate = &rate
+	}
+	account.AvailableBalanceCents = account.BalanceCents - account.HeldCents
+	account.BalanceDecimal = currency.Format(account.BalanceCents, account.Currency)
+	account.AvailableBalanceDecimal = currency.Format(account.AvailableBalanceCents, account.Currency)
+	return nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/types"
+)
+
+// writeAuditLog records an administrative change inside the caller's DB
+// transaction, under the tenant of ctx
+func writeAuditLog(ctx context.Context, tx *sql.Tx, action, entityType string, entityID uuid.UUID, actor string, details interface{}) error {
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit details: %w", err)
+	}
+
+	insertQuery := `
+		INSERT INTO audit_logs (id, tenant_id, action, entity_type, entity_id, details, created_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW(), $7)
+	`
+	if _, err := tx.ExecContext(ctx, insertQuery, uuid.New(), tenant.FromContext(ctx), action, entityType, entityID, detailsJSON, actor); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// auditLogOrder is the keyset order of audit trails, newest first
+var auditLogOrder = keysetOrder{sort: "-created_at", column: "created_at", cast: "timestamptz", desc: true}
+
+// listAuditLogs returns the audit trail of an entity recorded under the
+// tenant of ctx a page at a time, newest first. cursorToken is a cursor of a
+// previous page of the same entity; an empty token starts at the first page.
+func listAuditLogs(ctx context.Context, q queryer, entityType string, entityID uuid.UUID, cursorToken string, limit int) ([]types.AuditLog, types.PageCursors, error) {
+	order := auditLogOrder.withFilter(map[string]interface{}{"entity_type": entityType, "entity_id": entityID})
+	after, err := decodeCursor(cursorToken, order)
+	if err != nil {
+		return nil, types.PageCursors{}, err
+	}
+
+	args := []interface{}{tenant.FromContext(ctx), entityType, entityID}
+	conditions := []string{"tenant_id = $1", "entity_type = $2", "entity_id = $3"}
+	keysetCondition, args, orderBy := order.clauses(after, args)
+	if keysetCondition != "" {
+		conditions = append(conditions, keysetCondition)
+	}
+
+	// One row more than the page shows whether another page follows
+	args = append(args, limit+1)
+	query := fmt.Sprintf(`
+		SELECT id, action, entity_type, entity_id, details, created_at, created_by
+		FROM audit_logs
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), orderBy, len(args))
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, types.PageCursors{}, fmt.Errorf("failed to query audit logs: %w", err)
+	}
+	defer rows.Close()
+
+	logs := []types.AuditLog{}
+	for rows.Next() {
+		var log types.AuditLog
+		var details []byte
+		if err := rows.Scan(&log.ID, &log.Action, &log.EntityType, &log.EntityID, &details, &log.CreatedAt, &log.CreatedBy); err != nil {
+			return nil, types.PageCursors{}, fmt.Errorf("failed to scan audit log: %w", err)
+		}
+		log.Details = details
+		logs = append(logs, log)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, types.PageCursors{}, fmt.Errorf("failed to iterate audit logs: %w", err)
+	}
+
+	hasMore := len(logs) > limit
+	if hasMore {
+		logs = logs[:limit]
+	}
+	if after != nil && after.Backward {
+		for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+			logs[i], logs[j] = logs[j], logs[i]
+		}
+	}
+
+	var first, last *cursor
+	if len(logs) > 0 {
+		first = &cursor{ID: logs[0].ID, Value: logs[0].CreatedAt.Format(time.RFC3339Nano)}
+		last = &cursor{ID: logs[len(logs)-1].ID, Value: logs[len(logs)-1].CreatedAt.Format(time.RFC3339Nano)}
+	}
+	return logs, order.pageCursors(after, first, last, hasMore), nil
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/currency"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// maxHistoryPoints bounds the number of buckets a balance history may return
+const maxHistoryPoints = 1000
+
+// BalanceService answers point-in-time balance questions from the ledger.
+// Balances are the latest end-of-day snapshot plus the postings after it.
+type BalanceService struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewBalanceService creates a new balance service
+func NewBalanceService(db *sql.DB, logger *zap.Logger) *BalanceService {
+	return &BalanceService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetBalance returns the ledger balance of an account including every posting up to asOf
+func (s *BalanceService) GetBalance(ctx context.Context, accountID uuid.UUID, asOf time.Time) (*types.AccountBalance, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	code, err := accountCurrency(ctx, s.db, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := ledgerBalanceAt(ctx, s.db, accountID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.AccountBalance{
+		AccountID:      accountID,
+		Currency:       code,
+		AsOf:           asOf,
+		BalanceCents:   balance,
+		BalanceDecimal: currency.Format(balance, code),
+	}, nil
+}
+
+// GetBalanceHistory returns the opening and closing balance of every interval
+// between from and to. Intervals are aligned to UTC boundaries; the first and
+// last are clipped to the requested range.
+func (s *BalanceService) GetBalanceHistory(ctx context.Context, accountID uuid.UUID, from, to time.Time, interval types.BalanceInterval) (*types.BalanceHistory, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	from, to = from.UTC(), to.UTC()
+	var starts []time.Time
+	for start := truncateToInterval(from, interval); start.Before(to); start = nextInterval(start, interval) {
+		if len(starts) == maxHistoryPoints {
+			return nil, fmt.Errorf("too many intervals")
+		}
+		starts = append(starts, start)
+	}
+
+	currency, err := accountCurrency(ctx, s.db, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opening, err := ledgerBalanceAt(ctx, s.db, accountID, from.Add(-time.Microsecond))
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT date_trunc($2, created_at, 'UTC') AS bucket,
+		       COALESCE(SUM(amount_cents) FILTER (WHERE direction = 'CREDIT'), 0),
+		       COALESCE(SUM(amount_cents) FILTER (WHERE direction = 'DEBIT'), 0)
+		FROM postings
+		WHERE account_id = $1 AND created_at >= $3 AND created_at < $4
+		GROUP BY bucket
+	`
+	rows, err := s.db.QueryContext(ctx, query, accountID, string(interval), from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query postings: %w", err)
+	}
+	defer rows.Close()
+
+	type totals struct{ credits, debits int64 }
+	buckets := make(map[int64]totals)
+	for rows.Next() {
+		var bucket time.Time
+		var t totals
+		if err := rows.Scan(&bucket, &t.credits, &t.debits); err != nil {
+			return nil, fmt.Errorf("failed to scan postings: %w", err)
+		}
+		buckets[bucket.Unix()] = t
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query postings: %w", err)
+	}
+
+	points := make([]types.BalanceHistoryPoint, 0, len(starts))
+	balance := opening
+	for _, start := range starts {
+		t := buckets[start.Unix()]
+		point := types.BalanceHistoryPoint{
+			PeriodStart:         maxTime(start, from),
+			PeriodEnd:           minTime(nextInterval(start, interval), to),
+			OpeningBalanceCents: balance,
+			CreditsCents:        t.credits,
+			DebitsCents:         t.debits,
+		}
+		balance += t.credits - t.debits
+		point.ClosingBalanceCents = balance
+		points = append(points, point)
+	}
+
+	return &types.BalanceHistory{
+		AccountID: accountID,
+		Currency:  currency,
+		From:      from,
+		To:        to,
+		Interval:  interval,
+		Points:    points,
+	}, nil
+}
+
+// accountCurrency returns the currency of a customer account of the tenant of
+// ctx. System accounts are not found.
+func accountCurrency(ctx context.Context, q queryer, accountID uuid.UUID) (string, error) {
+	var currency string
+	query := `SELECT currency FROM accounts WHERE id = $1 AND tenant_id = $2 AND system_code IS NULL`
+	err := q.QueryRowContext(ctx, query, accountID, tenant.FromContext(ctx)).Scan(&currency)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("account not found")
+		}
+		return "", fmt.Errorf("failed to load account: %w", err)
+	}
+	return currency, nil
+}
+
+// ledgerBalanceAt sums the postings of an account created up to and including
+// asOf, starting from the latest snapshot taken at or before asOf
+func ledgerBalanceAt(ctx context.Context, q queryer, accountID uuid.UUID, asOf time.Time) (int64, error) {
+	query := `
+		WITH snapshot AS (
+			SELECT snapshot_at, balance_cents
+			FROM account_balance_snapshots
+			WHERE account_id = $1 AND snapshot_at <= $2
+			ORDER BY snapshot_at DESC
+			LIMIT 1
+		)
+		SELECT COALESCE((SELECT balance_cents FROM snapshot), 0) + COALESCE(SUM(
+			CASE p.direction WHEN 'CREDIT' THEN p.amount_cents ELSE -p.amount_cents END
+		), 0)
+		FROM postings p
+		WHERE p.account_id = $1 AND p.created_at <= $2
+		  AND p.created_at >= COALESCE((SELECT snapshot_at FROM snapshot), '-infinity')
+	`
+	var balance int64
+	if err := q.QueryRowContext(ctx, query, accountID, asOf).Scan(&balance); err != nil {
+		return 0, fmt.Errorf("failed to compute balance: %w", err)
+	}
+	return balance, nil
+}
+
+// truncateToInterval returns the start of the UTC interval containing t.
+// Weeks start on Monday, matching date_trunc.
+func truncateToInterval(t time.Time, interval types.BalanceInterval) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case types.BalanceIntervalHour:
+		return t.Truncate(time.Hour)
+	case types.BalanceIntervalWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case types.BalanceIntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// nextInterval returns the start of the interval after the one starting at start
+func nextInterval(start time.Time, interval types.BalanceInterval) time.Time {
+	switch interval {
+	case types.BalanceIntervalHour:
+		return start.Add(time.Hour)
+	case types.BalanceIntervalWeek:
+		return start.AddDate(0, 0, 7)
+	case types.BalanceIntervalMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,517 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yash/transaction-system/shared/currency"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// CustomerService manages customers and the accounts they own
+type CustomerService struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewCustomerService creates a new customer service
+func NewCustomerService(db *sql.DB, logger *zap.Logger) *CustomerService {
+	return &CustomerService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// customerColumns is the column list read by scanCustomer
+const customerColumns = `id, full_name, email, phone, date_of_birth::text, address, created_at, updated_at`
+
+// scanCustomer scans a row selected with customerColumns
+func scanCustomer(row rowScanner, customer *types.Customer) error {
+	var address []byte
+	err := row.Scan(
+		&customer.ID, &customer.FullName, &customer.Email, &customer.Phone,
+		&customer.DateOfBirth, &address, &customer.CreatedAt, &customer.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if len(address) > 0 {
+		customer.Address = &types.Address{}
+		if err := json.Unmarshal(address, customer.Address); err != nil {
+			return fmt.Errorf("failed to unmarshal address: %w", err)
+		}
+	}
+	return nil
+}
+
+// CreateCustomer creates a customer of the tenant of ctx. The creation is
+// audited.
+func (s *CustomerService) CreateCustomer(ctx context.Context, req types.CreateCustomerRequest, actor string) (*types.Customer, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	address, err := addressValue(req.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	tenantID := tenant.FromContext(ctx)
+	if err := tenant.Scope(ctx, tx, tenantID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO customers (id, tenant_id, full_name, email, phone, date_of_birth, address)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + customerColumns + `
+	`
+	var customer types.Customer
+	err = scanCustomer(tx.QueryRowContext(ctx, query,
+		uuid.New(), tenantID, req.FullName, nullIfEmpty(req.Email), nullIfEmpty(req.Phone), nullIfEmpty(req.DateOfBirth), address,
+	), &customer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create customer: %w", err)
+	}
+
+	if err := writeAuditLog(ctx, tx, types.AuditActionCustomerCreated, types.AuditEntityCustomer, customer.ID, actor, customer); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Customer created", zap.String("customer_id", customer.ID.String()))
+	return &customer, nil
+}
+
+// GetCustomer retrieves a customer of the tenant of ctx by ID
+func (s *CustomerService) GetCustomer(ctx context.Context, customerID uuid.UUID) (*types.Customer, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var customer types.Customer
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE id = $1 AND tenant_id = $2`
+	if err := scanCustomer(s.db.QueryRowContext(ctx, query, customerID, tenant.FromContext(ctx)), &customer); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("customer not found")
+		}
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+	return &customer, nil
+}
+
+// UpdateCustomer applies a partial update to a customer's profile. An empty
+// email, phone or date of birth clears it. The change is audited.
+func (s *CustomerService) UpdateCustomer(ctx context.Context, customerID uuid.UUID, req types.UpdateCustomerRequest, actor string) (*types.Customer, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	tenantID := tenant.FromContext(ctx)
+	if err := tenant.Scope(ctx, tx, tenantID); err != nil {
+		return nil, err
+	}
+
+	var customer types.Customer
+	lockQuery := `SELECT ` + customerColumns + ` FROM customers WHERE id = $1 AND tenant_id = $2 FOR UPDATE`
+	if err := scanCustomer(tx.QueryRowContext(ctx, lockQuery, customerID, tenantID), &customer); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("customer not found")
+		}
+		return nil, fmt.Errorf("failed to lock customer: %w", err)
+	}
+
+	if req.FullName != nil {
+		customer.FullName = *req.FullName
+	}
+	if req.Email != nil {
+		customer.Email = nullIfEmpty(req.Email)
+	}
+	if req.Phone != nil {
+		customer.Phone = nullIfEmpty(req.Phone)
+	}
+	if req.DateOfBirth != nil {
+		customer.DateOfBirth = nullIfEmpty(req.DateOfBirth)
+	}
+	if req.Address != nil {
+		customer.Address = req.Address
+	}
+	address, err := addressValue(customer.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	updateQuery := `
+		UPDATE customers
+		SET full_name = $1, email = $2, phone = $3, date_of_birth = $4, address = $5
+		WHERE id = $6
+		RETURNING ` + customerColumns + `
+	`
+	err = scanCustomer(tx.QueryRowContext(ctx, updateQuery,
+		customer.FullName, customer.Email, customer.Phone, customer.DateOfBirth, address, customerID,
+	), &customer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update customer: %w", err)
+	}
+
+	if err := writeAuditLog(ctx, tx, types.AuditActionCustomerUpdated, types.AuditEntityCustomer, customerID, actor, req); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Customer updated",
+		zap.String("customer_id", customerID.String()),
+		zap.String("actor", actor),
+	)
+	return &customer, nil
+}
+
+// ListCustomerAccounts returns every account a customer owns, alone or
+// jointly, with their balances totalled per currency. Closed accounts are
+// listed but left out of the totals.
+func (s *CustomerService) ListCustomerAccounts(ctx context.Context, customerID uuid.UUID) (*types.CustomerAccounts, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var exists bool
+	existsQuery := `SELECT EXISTS (SELECT 1 FROM customers WHERE id = $1 AND tenant_id = $2)`
+	if err := s.db.QueryRowContext(ctx, existsQuery, customerID, tenant.FromContext(ctx)).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check customer: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("customer not found")
+	}
+
+	query := `
+		SELECT ` + accountColumns + `, owners.role
+		FROM accounts
+		JOIN (SELECT account_id, role FROM account_owners WHERE customer_id = $1) owners
+		  ON owners.account_id = accounts.id
+		WHERE accounts.tenant_id = $2
+		ORDER BY created_at, id
+	`
+	rows, err := s.db.QueryContext(ctx, query, customerID, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query customer accounts: %w", err)
+	}
+	defer rows.Close()
+
+	result := &types.CustomerAccounts{
+		CustomerID: customerID,
+		Accounts:   []types.CustomerAccount{},
+		Balances:   []types.CustomerBalance{},
+	}
+	balances := make(map[string]*types.CustomerBalance)
+	for rows.Next() {
+		var account types.CustomerAccount
+		if err := scanAccount(roleScanner{rows, &account.Role}, &account.Account); err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		result.Accounts = append(result.Accounts, account)
+
+		if account.Status == types.AccountStatusClosed {
+			continue
+		}
+		balance, ok := balances[account.Currency]
+		if !ok {
+			balance = &types.CustomerBalance{Currency: account.Currency}
+			balances[account.Currency] = balance
+		}
+		balance.AccountCount++
+		balance.BalanceCents += account.BalanceCents
+		balance.HeldCents += account.HeldCents
+		balance.AvailableBalanceCents += account.AvailableBalanceCents
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, balance := range balances {
+		balance.BalanceDecimal = currency.Format(balance.BalanceCents, balance.Currency)
+		balance.AvailableBalanceDecimal = currency.Format(balance.AvailableBalanceCents, balance.Currency)
+		result.Balances = append(result.Balances, *balance)
+	}
+	sort.Slice(result.Balances, func(i, j int) bool {
+		return result.Balances[i].Currency < result.Balances[j].Currency
+	})
+
+	return result, nil
+}
+
+// roleScanner appends the owner role selected after accountColumns to the
+// destinations of scanAccount
+type roleScanner struct {
+	row  rowScanner
+	role *types.AccountOwnerRole
+}
+
+func (r roleScanner) Scan(dest ...interface{}) error {
+	return r.row.Scan(append(dest, r.role)...)
+}
+
+// ListAccountOwners returns the owners of an account, primary first
+func (s *CustomerService) ListAccountOwners(ctx context.Context, accountID uuid.UUID) ([]types.AccountOwner, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var exists bool
+	existsQuery := `SELECT EXISTS (SELECT 1 FROM accounts WHERE id = $1 AND tenant_id = $2 AND system_code IS NULL)`
+	if err := s.db.QueryRowContext(ctx, existsQuery, accountID, tenant.FromContext(ctx)).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check account: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("account not found")
+	}
+
+	return listAccountOwners(ctx, s.db, accountID)
+}
+
+// listAccountOwners returns the owners of an account, primary first
+func listAccountOwners(ctx context.Context, q queryer, accountID uuid.UUID) ([]types.AccountOwner, error) {
+	query := `
+		SELECT customer_id, role, created_at
+		FROM account_owners
+		WHERE account_id = $1
+		ORDER BY role = 'PRIMARY' DESC, created_at, customer_id
+	`
+	rows, err := q.QueryContext(ctx, query, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query account owners: %w", err)
+	}
+	defer rows.Close()
+
+	owners := []types.AccountOwner{}
+	for rows.Next() {
+		var owner types.AccountOwner
+		if err := rows.Scan(&owner.CustomerID, &owner.Role, &owner.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan account owner: %w", err)
+		}
+		owners = append(owners, owner)
+	}
+
+	return owners, rows.Err()
+}
+
+// AddAccountOwner makes a customer a joint owner of an account that already
+// has a primary owner. The change is audited on the account.
+func (s *CustomerService) AddAccountOwner(ctx context.Context, accountID, customerID uuid.UUID, actor string) ([]types.AccountOwner, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	tenantID := tenant.FromContext(ctx)
+	if err := tenant.Scope(ctx, tx, tenantID); err != nil {
+		return nil, err
+	}
+
+	var primary *uuid.UUID
+	var status types.AccountStatus
+	lockQuery := `SELECT customer_id, status FROM accounts WHERE id = $1 AND tenant_id = $2 AND system_code IS NULL FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, lockQuery, accountID, tenantID).Scan(&primary, &status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("account not found")
+		}
+		return nil, fmt.Errorf("failed to lock account: %w", err)
+	}
+	if status == types.AccountStatusClosed {
+		return nil, fmt.Errorf("account is closed")
+	}
+	if primary == nil {
+		return nil, fmt.Errorf("account has no primary owner")
+	}
+	if err := checkCustomersExist(ctx, tx, []uuid.UUID{customerID}); err != nil {
+		return nil, err
+	}
+
+	insertQuery := `
+		INSERT INTO account_owners (account_id, customer_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (account_id, customer_id) DO NOTHING
+	`
+	result, err := tx.ExecContext(ctx, insertQuery, accountID, customerID, types.AccountOwnerRoleJoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add account owner: %w", err)
+	}
+	if added, err := result.RowsAffected(); err != nil || added == 0 {
+		return nil, fmt.Errorf("customer already owns the account")
+	}
+
+	details := map[string]string{
+		"customer_id": customerID.String(),
+		"role":        string(types.AccountOwnerRoleJoint),
+	}
+	if err := writeAuditLog(ctx, tx, types.AuditActionAccountOwnerAdded, types.AuditEntityAccount, accountID, actor, details); err != nil {
+		return nil, err
+	}
+
+	owners, err := listAccountOwners(ctx, tx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Account owner added",
+		zap.String("account_id", accountID.String()),
+		zap.String("customer_id", customerID.String()),
+		zap.String("actor", actor),
+	)
+	return owners, nil
+}
+
+// RemoveAccountOwner removes a joint owner from an account. The primary owner
+// cannot be removed. The change is audited on the account.
+func (s *CustomerService) RemoveAccountOwner(ctx context.Context, accountID, customerID uuid.UUID, actor string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	var role types.AccountOwnerRole
+	lockQuery := `
+		SELECT o.role
+		FROM account_owners o
+		JOIN accounts a ON a.id = o.account_id
+		WHERE o.account_id = $1 AND o.customer_id = $2 AND a.tenant_id = $3
+		FOR UPDATE OF o
+	`
+	if err := tx.QueryRowContext(ctx, lockQuery, accountID, customerID, tenant.FromContext(ctx)).Scan(&role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("account owner not found")
+		}
+		return fmt.Errorf("failed to lock account owner: %w", err)
+	}
+	if role == types.AccountOwnerRolePrimary {
+		return fmt.Errorf("cannot remove the primary owner")
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM account_owners WHERE account_id = $1 AND customer_id = $2`, accountID, customerID); err != nil {
+		return fmt.Errorf("failed to remove account owner: %w", err)
+	}
+
+	details := map[string]string{
+		"customer_id": customerID.String(),
+		"role":        string(role),
+	}
+	if err := writeAuditLog(ctx, tx, types.AuditActionAccountOwnerRemoved, types.AuditEntityAccount, accountID, actor, details); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Account owner removed",
+		zap.String("account_id", accountID.String()),
+		zap.String("customer_id", customerID.String()),
+		zap.String("actor", actor),
+	)
+	return nil
+}
+
+// addAccountOwners records the owners of a new account: the primary owner
+// and any joint owners
+func addAccountOwners(ctx context.Context, tx *sql.Tx, accountID, primaryID uuid.UUID, jointIDs []uuid.UUID) error {
+	insertQuery := `INSERT INTO account_owners (account_id, customer_id, role) VALUES ($1, $2, $3)`
+	if _, err := tx.ExecContext(ctx, insertQuery, accountID, primaryID, types.AccountOwnerRolePrimary); err != nil {
+		return fmt.Errorf("failed to add account owner: %w", err)
+	}
+	for _, jointID := range jointIDs {
+		if _, err := tx.ExecContext(ctx, insertQuery, accountID, jointID, types.AccountOwnerRoleJoint); err != nil {
+			return fmt.Errorf("failed to add account owner: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkCustomersExist returns "customer not found" unless every customer
+// exists in the tenant of ctx
+func checkCustomersExist(ctx context.Context, q queryer, customerIDs []uuid.UUID) error {
+	ids := make(pq.StringArray, len(customerIDs))
+	distinct := make(map[uuid.UUID]bool)
+	for i, id := range customerIDs {
+		ids[i] = id.String()
+		distinct[id] = true
+	}
+
+	var found int
+	query := `SELECT COUNT(*) FROM customers WHERE id = ANY($1::uuid[]) AND tenant_id = $2`
+	if err := q.QueryRowContext(ctx, query, ids, tenant.FromContext(ctx)).Scan(&found); err != nil {
+		return fmt.Errorf("failed to check customers: %w", err)
+	}
+	if found != len(distinct) {
+		return fmt.Errorf("customer not found")
+	}
+	return nil
+}
+
+// addressValue returns the JSONB value of an address, or nil
+func addressValue(address *types.Address) (interface{}, error) {
+	if address == nil {
+		return nil, nil
+	}
+	value, err := json.Marshal(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal address: %w", err)
+	}
+	return value, nil
+}
+
+// nullIfEmpty maps an empty optional string to NULL
+func nullIfEmpty(s *string) *string {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return s
+}
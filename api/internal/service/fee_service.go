@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/fees"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// FeeService manages the fee schedules the worker charges transactions with
+type FeeService struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewFeeService creates a new fee service
+func NewFeeService(db *sql.DB, logger *zap.Logger) *FeeService {
+	return &FeeService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListFeeSchedules returns every fee schedule
+func (s *FeeService) ListFeeSchedules(ctx context.Context) ([]types.FeeSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT ` + fees.ScheduleColumns + `
+		FROM fee_schedules
+		ORDER BY transaction_type, currency
+	`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fee schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := []types.FeeSchedule{}
+	for rows.Next() {
+		schedule, err := fees.ScanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan fee schedule: %w", err)
+		}
+		schedules = append(schedules, *schedule)
+	}
+
+	return schedules, rows.Err()
+}
+
+// SetFeeSchedule creates or replaces the schedule of a transaction type and
+// currency. The change is audited.
+func (s *FeeService) SetFeeSchedule(ctx context.Context, transactionType types.TransactionType, currency string, req types.SetFeeScheduleRequest, actor string) (*types.FeeSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tiers := req.Tiers
+	if tiers == nil {
+		tiers = []types.FeeTier{}
+	}
+	tiersJSON, err := json.Marshal(tiers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tiers: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	upsertQuery := `
+		INSERT INTO fee_schedules (transaction_type, currency, flat_cents, rate_bps, min_cents, max_cents, tiers)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (transaction_type, currency) DO UPDATE
+		SET flat_cents = EXCLUDED.flat_cents,
+		    rate_bps = EXCLUDED.rate_bps,
+		    min_cents = EXCLUDED.min_cents,
+		    max_cents = EXCLUDED.max_cents,
+		    tiers = EXCLUDED.tiers
+		RETURNING ` + fees.ScheduleColumns + `
+	`
+	schedule, err := fees.ScanSchedule(tx.QueryRowContext(ctx, upsertQuery,
+		transactionType, currency, req.FlatCents, req.RateBps, req.MinCents, req.MaxCents, tiersJSON,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to set fee schedule: %w", err)
+	}
+
+	if err := writeAuditLog(ctx, tx, types.AuditActionFeeScheduleUpdated, types.AuditEntityFeeSchedule, schedule.ID, actor, schedule); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Fee schedule updated",
+		zap.String("type", string(transactionType)),
+		zap.String("currency", currency),
+		zap.String("actor", actor),
+	)
+
+	return schedule, nil
+}
+
+// DeleteFeeSchedule removes the schedule of a transaction type and currency.
+// The removal is audited.
+func (s *FeeService) DeleteFeeSchedule(ctx context.Context, transactionType types.TransactionType, currency, actor string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	var id uuid.UUID
+	deleteQuery := `DELETE FROM fee_schedules WHERE transaction_type = $1 AND currency = $2 RETURNING id`
+	if err := tx.QueryRowContext(ctx, deleteQuery, transactionType, currency).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("fee schedule not found")
+		}
+		return fmt.Errorf("failed to delete fee schedule: %w", err)
+	}
+
+	details := map[string]string{
+		"transaction_type": string(transactionType),
+		"currency":         currency,
+	}
+	if err := writeAuditLog(ctx, tx, types.AuditActionFeeScheduleDeleted, types.AuditEntityFeeSchedule, id, actor, details); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Fee schedule deleted",
+		zap.String("type", string(transactionType)),
+		zap.String("currency", currency),
+		zap.String("actor", actor),
+	)
+	return nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/currency"
+	"github.com/yash/transaction-system/shared/fx"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// FXService handles FX rate ingestion and quotes
+type FXService struct {
+	db       *sql.DB
+	quoteTTL time.Duration
+	logger   *zap.Logger
+}
+
+// NewFXService creates a new FX service
+func NewFXService(db *sql.DB, quoteTTL time.Duration, logger *zap.Logger) *FXService {
+	return &FXService{
+		db:       db,
+		quoteTTL: quoteTTL,
+		logger:   logger,
+	}
+}
+
+// CreateRate publishes a rate for a currency pair. Rates are append-only; the
+// latest effective one is current.
+func (s *FXService) CreateRate(ctx context.Context, req types.CreateFXRateRequest) (*types.FXRate, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if !currency.Valid(req.BaseCurrency) || !currency.Valid(req.QuoteCurrency) {
+		return nil, fmt.Errorf("unsupported currency")
+	}
+	rate, err := fx.ParseRate(req.Rate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate")
+	}
+
+	effectiveAt := time.Now()
+	if req.EffectiveAt != nil {
+		effectiveAt = *req.EffectiveAt
+	}
+
+	query := `
+		INSERT INTO fx_rates (id, base_currency, quote_currency, rate, spread_bps, effective_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING ` + fxRateColumns + `
+	`
+	var fxRate types.FXRate
+	err = scanFXRate(s.db.QueryRowContext(ctx, query,
+		uuid.New(), req.BaseCurrency, req.QuoteCurrency, fx.FormatRate(rate), req.SpreadBps, effectiveAt,
+	), &fxRate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fx rate: %w", err)
+	}
+
+	s.logger.Info("FX rate published",
+		zap.String("base_currency", fxRate.BaseCurrency),
+		zap.String("quote_currency", fxRate.QuoteCurrency),
+		zap.String("rate", fxRate.Rate),
+		zap.Int("spread_bps", fxRate.SpreadBps),
+	)
+
+	return &fxRate, nil
+}
+
+// ListRates returns the current rate of every pair, optionally filtered by currency
+func (s *FXService) ListRates(ctx context.Context, currency string) ([]types.FXRate, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT DISTINCT ON (base_currency, quote_currency) ` + fxRateColumns + `
+		FROM fx_rates
+		WHERE effective_at <= NOW() AND ($1 = '' OR base_currency = $1 OR quote_currency = $1)
+		ORDER BY base_currency, quote_currency, effective_at DESC, created_at DESC
+	`
+	rows, err := s.db.QueryContext(ctx, query, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fx rates: %w", err)
+	}
+	defer rows.Close()
+
+	rates := []types.FXRate{}
+	for rows.Next() {
+		var fxRate types.FXRate
+		if err := scanFXRate(rows, &fxRate); err != nil {
+			return nil, fmt.Errorf("failed to scan fx rate: %w", err)
+		}
+		rates = append(rates, fxRate)
+	}
+
+	return rates, rows.Err()
+}
+
+// CreateQuote locks the current rate for a pair for the configured quote TTL
+func (s *FXService) CreateQuote(ctx context.Context, req types.CreateFXQuoteRequest) (*types.FXQuote, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rate, err := fx.LatestRate(ctx, s.db, req.BaseCurrency, req.QuoteCurrency)
+	if err != nil {
+		if errors.Is(err, fx.ErrRateNotFound) {
+			return nil, fmt.Errorf("fx rate not found")
+		}
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO fx_quotes (id, base_currency, quote_currency, rate, spread_bps, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING ` + fxQuoteColumns + `
+	`
+	var quote types.FXQuote
+	err = scanFXQuote(s.db.QueryRowContext(ctx, query,
+		uuid.New(), req.BaseCurrency, req.QuoteCurrency, fx.FormatRate(rate.Value), rate.SpreadBps, time.Now().Add(s.quoteTTL),
+	), &quote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fx quote: %w", err)
+	}
+
+	return &quote, nil
+}
+
+// GetQuote retrieves a quote by ID
+func (s *FXService) GetQuote(ctx context.Context, quoteID uuid.UUID) (*types.FXQuote, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var quote types.FXQuote
+	err := findFXQuote(ctx, s.db, quoteID, &quote)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("fx quote not found")
+		}
+		return nil, fmt.Errorf("failed to get fx quote: %w", err)
+	}
+
+	return &quote, nil
+}
+
+// fxRateColumns is the column list read by scanFXRate
+const fxRateColumns = `id, base_currency, quote_currency, rate, spread_bps, effective_at, created_at`
+
+// fxQuoteColumns is the column list read by scanFXQuote
+const fxQuoteColumns = `id, base_currency, quote_currency, rate, spread_bps, expires_at, created_at`
+
+// scanFXRate scans a row selected with fxRateColumns
+func scanFXRate(row rowScanner, fxRate *types.FXRate) error {
+	err := row.Scan(
+		&fxRate.ID, &fxRate.BaseCurrency, &fxRate.QuoteCurrency, &fxRate.Rate,
+		&fxRate.SpreadBps, &fxRate.EffectiveAt, &fxRate.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	fxRate.Rate = normalizeRate(fxRate.Rate)
+	return nil
+}
+
+// scanFXQuote scans a row selected with fxQuoteColumns
+func scanFXQuote(row rowScanner, quote *types.FXQuote) error {
+	err := row.Scan(
+		&quote.ID, &quote.BaseCurrency, &quote.QuoteCurrency, &quote.Rate,
+		&quote.SpreadBps, &quote.ExpiresAt, &quote.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	quote.Rate = normalizeRate(quote.Rate)
+	return nil
+}
+
+// findFXQuote loads a quote by ID
+func findFXQuote(ctx context.Context, q queryer, quoteID uuid.UUID, quote *types.FXQuote) error {
+	query := `SELECT ` + fxQuoteColumns + ` FROM fx_quotes WHERE id = $1`
+	return scanFXQuote(q.QueryRowContext(ctx, query, quoteID), quote)
+}
+
+// normalizeRate strips the trailing zeros of a NUMERIC column
+func normalizeRate(rate string) string {
+	if parsed, err := fx.ParseRate(rate); err == nil {
+		return fx.FormatRate(parsed)
+	}
+	return rate
+}
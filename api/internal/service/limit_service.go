@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/limits"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// LimitService manages per-tier and per-account debit limits and reports the
+// headroom an account has left
+type LimitService struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewLimitService creates a new limit service
+func NewLimitService(db *sql.DB, logger *zap.Logger) *LimitService {
+	return &LimitService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetAccountLimits returns an account's effective limits, their sources, and
+// the headroom left in the current day and month
+func (s *LimitService) GetAccountLimits(ctx context.Context, accountID uuid.UUID) (*types.AccountLimits, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return s.accountLimits(ctx, s.db, accountID)
+}
+
+// SetAccountLimits replaces an account's limit overrides and, if given, its
+// tier. Clearing every override removes them. The change is audited.
+func (s *LimitService) SetAccountLimits(ctx context.Context, accountID uuid.UUID, req types.SetAccountLimitsRequest, actor string) (*types.AccountLimits, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	// Serializes limit changes with each other; debits only read the limits
+	lockQuery := `SELECT tier FROM accounts WHERE id = $1 AND tenant_id = $2 AND system_code IS NULL FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, lockQuery, accountID, tenant.FromContext(ctx)).Scan(new(string)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("account not found")
+		}
+		return nil, fmt.Errorf("failed to lock account: %w", err)
+	}
+	before, err := s.accountLimits(ctx, tx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Tier != "" && req.Tier != before.Tier {
+		if _, err := tx.ExecContext(ctx, `UPDATE accounts SET tier = $1, updated_at = NOW() WHERE id = $2`, req.Tier, accountID); err != nil {
+			return nil, fmt.Errorf("failed to update tier: %w", err)
+		}
+	}
+
+	if req.Overrides == (types.TransactionLimits{}) {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM transaction_limits WHERE account_id = $1`, accountID); err != nil {
+			return nil, fmt.Errorf("failed to clear limits: %w", err)
+		}
+	} else {
+		upsertQuery := `
+			INSERT INTO transaction_limits (account_id, max_single_debit_cents, max_daily_debit_cents,
+			                                max_monthly_debit_cents, max_daily_debit_count, max_monthly_debit_count)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (account_id) DO UPDATE
+			SET max_single_debit_cents = EXCLUDED.max_single_debit_cents,
+			    max_daily_debit_cents = EXCLUDED.max_daily_debit_cents,
+			    max_monthly_debit_cents = EXCLUDED.max_monthly_debit_cents,
+			    max_daily_debit_count = EXCLUDED.max_daily_debit_count,
+			    max_monthly_debit_count = EXCLUDED.max_monthly_debit_count
+		`
+		o := req.Overrides
+		_, err := tx.ExecContext(ctx, upsertQuery, accountID,
+			o.MaxSingleDebitCents, o.MaxDailyDebitCents, o.MaxMonthlyDebitCents, o.MaxDailyDebitCount, o.MaxMonthlyDebitCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set limits: %w", err)
+		}
+	}
+
+	after, err := s.accountLimits(ctx, tx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	details := map[string]interface{}{
+		"old_tier":      before.Tier,
+		"new_tier":      after.Tier,
+		"old_overrides": before.Overrides,
+		"new_overrides": after.Overrides,
+	}
+	if err := writeAuditLog(ctx, tx, types.AuditActionLimitsUpdated, types.AuditEntityAccount, accountID, actor, details); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Account limits updated",
+		zap.String("account_id", accountID.String()),
+		zap.String("tier", after.Tier),
+		zap.String("actor", actor),
+	)
+
+	return after, nil
+}
+
+// ListTierLimits returns the limits of every tier that has them
+func (s *LimitService) ListTierLimits(ctx context.Context) ([]types.TierLimits, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT tier, ` + limitColumns + `, updated_at
+		FROM transaction_limits
+		WHERE tier IS NOT NULL
+		ORDER BY tier
+	`
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tier limits: %w", err)
+	}
+	defer rows.Close()
+
+	tiers := []types.TierLimits{}
+	for rows.Next() {
+		var tier types.TierLimits
+		l := &tier.Limits
+		err := rows.Scan(&tier.Tier,
+			&l.MaxSingleDebitCents, &l.MaxDailyDebitCents, &l.MaxMonthlyDebitCents, &l.MaxDailyDebitCount, &l.MaxMonthlyDebitCount,
+			&tier.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tier limits: %w", err)
+		}
+		tiers = append(tiers, tier)
+	}
+
+	return tiers, rows.Err()
+}
+
+// SetTierLimits replaces the limits of a tier. The change is audited.
+func (s *LimitService) SetTierLimits(ctx context.Context, tier string, limitsReq types.TransactionLimits, actor string) (*types.TierLimits, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	upsertQuery := `
+		INSERT INTO transaction_limits (tier, max_single_debit_cents, max_daily_debit_cents,
+		                                max_monthly_debit_cents, max_daily_debit_count, max_monthly_debit_count)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (tier) DO UPDATE
+		SET max_single_debit_cents = EXCLUDED.max_single_debit_cents,
+		    max_daily_debit_cents = EXCLUDED.max_daily_debit_cents,
+		    max_monthly_debit_cents = EXCLUDED.max_monthly_debit_cents,
+		    max_daily_debit_count = EXCLUDED.max_daily_debit_count,
+		    max_monthly_debit_count = EXCLUDED.max_monthly_debit_count
+		RETURNING id, updated_at
+	`
+	result := types.TierLimits{Tier: tier, Limits: limitsReq}
+	var id uuid.UUID
+	l := limitsReq
+	err = tx.QueryRowContext(ctx, upsertQuery, tier,
+		l.MaxSingleDebitCents, l.MaxDailyDebitCents, l.MaxMonthlyDebitCents, l.MaxDailyDebitCount, l.MaxMonthlyDebitCount,
+	).Scan(&id, &result.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set tier limits: %w", err)
+	}
+
+	details := map[string]interface{}{
+		"tier":   tier,
+		"limits": limitsReq,
+	}
+	if err := writeAuditLog(ctx, tx, types.AuditActionLimitsUpdated, types.AuditEntityLimitTier, id, actor, details); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Tier limits updated", zap.String("tier", tier), zap.String("actor", actor))
+
+	return &result, nil
+}
+
+// limitColumns is the list of limit columns of transaction_limits
+const limitColumns = `max_single_debit_cents, max_daily_debit_cents, max_monthly_debit_cents,
+		       max_daily_debit_count, max_monthly_debit_count`
+
+// accountLimits loads an account's tier and override limits and computes its
+// effective limits and headroom. Usage counts every accepted debit.
+func (s *LimitService) accountLimits(ctx context.Context, q queryer, accountID uuid.UUID) (*types.AccountLimits, error) {
+	query := `
+		SELECT a.tier, a.currency,
+		       o.max_single_debit_cents, o.max_daily_debit_cents, o.max_monthly_debit_cents,
+		       o.max_daily_debit_count, o.max_monthly_debit_count,
+		       t.max_single_debit_cents, t.max_daily_debit_cents, t.max_monthly_debit_cents,
+		       t.max_daily_debit_count, t.max_monthly_debit_count
+		FROM accounts a
+		LEFT JOIN transaction_limits o ON o.account_id = a.id
+		LEFT JOIN transaction_limits t ON t.tier = a.tier
+		WHERE a.id = $1 AND a.tenant_id = $2 AND a.system_code IS NULL
+	`
+	result := types.AccountLimits{AccountID: accountID}
+	o, t := &result.Overrides, &result.TierLimits
+	err := q.QueryRowContext(ctx, query, accountID, tenant.FromContext(ctx)).Scan(&result.Tier, &result.Currency,
+		&o.MaxSingleDebitCents, &o.MaxDailyDebitCents, &o.MaxMonthlyDebitCents, &o.MaxDailyDebitCount, &o.MaxMonthlyDebitCount,
+		&t.MaxSingleDebitCents, &t.MaxDailyDebitCents, &t.MaxMonthlyDebitCents, &t.MaxDailyDebitCount, &t.MaxMonthlyDebitCount,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("account not found")
+		}
+		return nil, fmt.Errorf("failed to load limits: %w", err)
+	}
+
+	result.Limits = limits.Merge(result.Overrides, result.TierLimits)
+
+	now := time.Now().UTC()
+	result.Usage, err = limits.Usage(ctx, q, accountID, now, limits.AcceptedStatuses, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+	result.Headroom = limits.Headroom(result.Limits, result.Usage)
+	day, month := limits.Windows(now)
+	result.DayResetsAt = day.AddDate(0, 0, 1)
+	result.MonthResetsAt = month.AddDate(0, 1, 0)
+
+	return &result, nil
+}
@@ -21,6 +21,14 @@ var (
 		},
 		[]string{"transaction_id", "type", "status"},
 	)
+
+	recurringOccurrencesTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "recurring_occurrences_total",
+			Help: "Total number of recurring schedule occurrences run, by result",
+		},
+		[]string{"result"},
+	)
 )
 
 // UpdateAccountBalanceMetric updates the account balance metric
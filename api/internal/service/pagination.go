@@ -0,0 +1,129 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/types"
+)
+
+// keysetOrder is a sort order of a keyset-paginated list. Rows are ordered by
+// column and then by id, which breaks ties, both in the same direction.
+type keysetOrder struct {
+	sort     string // the sort option, recorded in cursors
+	column   string
+	cast     string // the SQL type cursor values are cast to
+	desc     bool
+	filter   string // the filterHash of the list, recorded in cursors
+	idColumn string // the UUID column breaking ties, id if empty
+}
+
+// tieBreaker returns the column that orders rows with equal column values
+func (o keysetOrder) tieBreaker() string {
+	if o.idColumn == "" {
+		return "id"
+	}
+	return o.idColumn
+}
+
+// withFilter returns the order of a list selected by filter. Its cursors are
+// only accepted by lists with the same filter.
+func (o keysetOrder) withFilter(filter interface{}) keysetOrder {
+	o.filter = filterHash(filter)
+	return o
+}
+
+// filterHash returns a short digest of a list filter
+func filterHash(filter interface{}) string {
+	data, _ := json.Marshal(filter)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// cursor is the position of a row in a keyset-paginated list: its sort column
+// value and id. A backward cursor pages towards the start of the list. An
+// inclusive cursor also selects the row at its position.
+type cursor struct {
+	Sort      string    `json:"s"`
+	Filter    string    `json:"f"`
+	Value     string    `json:"v"`
+	ID        uuid.UUID `json:"id"`
+	Backward  bool      `json:"b,omitempty"`
+	Inclusive bool      `json:"i,omitempty"`
+}
+
+// encode returns the opaque token clients pass back as ?cursor=
+func (c cursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor token issued for the given order and filter.
+// An empty token is the first page and returns nil.
+func decodeCursor(token string, order keysetOrder) (*cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Sort != order.sort || c.Filter != order.filter || c.Value == "" || c.ID == uuid.Nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &c, nil
+}
+
+// clauses returns the condition selecting the rows beyond c, with its
+// arguments appended to args, and the ORDER BY clause that fetches them
+// nearest first. The condition is empty for the first page.
+func (o keysetOrder) clauses(c *cursor, args []interface{}) (string, []interface{}, string) {
+	// A backward page is fetched in reverse and flipped afterwards
+	desc := o.desc
+	if c != nil && c.Backward {
+		desc = !desc
+	}
+	comparison, direction := ">", "ASC"
+	if desc {
+		comparison, direction = "<", "DESC"
+	}
+	orderBy := fmt.Sprintf("%s %s, %s %s", o.column, direction, o.tieBreaker(), direction)
+	if c == nil {
+		return "", args, orderBy
+	}
+	if c.Inclusive {
+		comparison += "="
+	}
+
+	args = append(args, c.Value, c.ID)
+	condition := fmt.Sprintf("(%s, %s) %s ($%d::%s, $%d)", o.column, o.tieBreaker(), comparison, len(args)-1, o.cast, len(args))
+	return condition, args, orderBy
+}
+
+// pageCursors returns the cursors of the pages next to one fetched after c.
+// first and last are the positions of its first and last rows in list order
+// (nil if it is empty) and hasMore reports whether more rows were found
+// beyond it in the direction it was fetched.
+func (o keysetOrder) pageCursors(c *cursor, first, last *cursor, hasMore bool) types.PageCursors {
+	var page types.PageCursors
+	backward := c != nil && c.Backward
+	if last != nil && (hasMore || backward) {
+		next := cursor{Sort: o.sort, Filter: o.filter, Value: last.Value, ID: last.ID}.encode()
+		page.NextCursor = &next
+	}
+	if last == nil && backward {
+		// Nothing precedes the page c was issued on, for instance because
+		// its rows no longer match; next pages forward from that page again
+		next := cursor{Sort: o.sort, Filter: o.filter, Value: c.Value, ID: c.ID, Inclusive: true}.encode()
+		page.NextCursor = &next
+	}
+	if first != nil && c != nil && (hasMore || !backward) {
+		prev := cursor{Sort: o.sort, Filter: o.filter, Value: first.Value, ID: first.ID, Backward: true}.encode()
+		page.PrevCursor = &prev
+	}
+	return page
+}
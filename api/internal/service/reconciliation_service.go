@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// ReconciliationService reads the results of the worker's balance reconciliation job
+type ReconciliationService struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewReconciliationService creates a new reconciliation service
+func NewReconciliationService(db *sql.DB, logger *zap.Logger) *ReconciliationService {
+	return &ReconciliationService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// reconciliationOrder is the keyset order of reconciliation runs, newest first
+var reconciliationOrder = keysetOrder{sort: "-started_at", column: "started_at", cast: "timestamptz", desc: true}
+
+// ListReconciliations returns reconciliation runs a page at a time, newest
+// first, each with its drift findings. With driftOnly set, clean runs are
+// skipped. cursorToken is a cursor of a previous page with the same
+// driftOnly; an empty token starts at the first page.
+func (s *ReconciliationService) ListReconciliations(ctx context.Context, driftOnly bool, cursorToken string, limit int) ([]types.ReconciliationRun, types.PageCursors, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	order := reconciliationOrder.withFilter(map[string]interface{}{"drift_only": driftOnly})
+	after, err := decodeCursor(cursorToken, order)
+	if err != nil {
+		return nil, types.PageCursors{}, err
+	}
+
+	args := []interface{}{driftOnly}
+	conditions := []string{"($1 = FALSE OR drift_accounts > 0)"}
+	keysetCondition, args, orderBy := order.clauses(after, args)
+	if keysetCondition != "" {
+		conditions = append(conditions, keysetCondition)
+	}
+
+	// One row more than the page shows whether another page follows
+	args = append(args, limit+1)
+	query := fmt.Sprintf(`
+		SELECT id, status, auto_repair, accounts_scanned, drift_accounts, repaired_accounts, error, started_at, completed_at
+		FROM reconciliation_runs
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), orderBy, len(args))
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, types.PageCursors{}, fmt.Errorf("failed to query reconciliation runs: %w", err)
+	}
+
+	runs := []types.ReconciliationRun{}
+	for rows.Next() {
+		var run types.ReconciliationRun
+		if err := rows.Scan(
+			&run.ID, &run.Status, &run.AutoRepair, &run.AccountsScanned, &run.DriftAccounts,
+			&run.RepairedAccounts, &run.Error, &run.StartedAt, &run.CompletedAt,
+		); err != nil {
+			rows.Close()
+			return nil, types.PageCursors{}, fmt.Errorf("failed to scan reconciliation run: %w", err)
+		}
+		run.Findings = []types.ReconciliationFinding{}
+		runs = append(runs, run)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, types.PageCursors{}, fmt.Errorf("failed to query reconciliation runs: %w", err)
+	}
+
+	hasMore := len(runs) > limit
+	if hasMore {
+		runs = runs[:limit]
+	}
+	if after != nil && after.Backward {
+		for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+			runs[i], runs[j] = runs[j], runs[i]
+		}
+	}
+
+	var first, last *cursor
+	if len(runs) > 0 {
+		first = &cursor{ID: runs[0].ID, Value: runs[0].StartedAt.Format(time.RFC3339Nano)}
+		last = &cursor{ID: runs[len(runs)-1].ID, Value: runs[len(runs)-1].StartedAt.Format(time.RFC3339Nano)}
+	}
+	page := order.pageCursors(after, first, last, hasMore)
+
+	runIndex := make(map[uuid.UUID]int)
+	runIDs := []string{}
+	for i, run := range runs {
+		runIndex[run.ID] = i
+		if run.DriftAccounts > 0 {
+			runIDs = append(runIDs, run.ID.String())
+		}
+	}
+	if len(runIDs) == 0 {
+		return runs, page, nil
+	}
+
+	findingsQuery := `
+		SELECT id, run_id, account_id, currency, balance_cents, expected_balance_cents, ledger_balance_cents, drift_cents, repaired, created_at
+		FROM reconciliation_findings
+		WHERE run_id = ANY($1::uuid[])
+		ORDER BY created_at, id
+	`
+	rows, err = s.db.QueryContext(ctx, findingsQuery, pq.StringArray(runIDs))
+	if err != nil {
+		return nil, types.PageCursors{}, fmt.Errorf("failed to query reconciliation findings: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var f types.ReconciliationFinding
+		if err := rows.Scan(
+			&f.ID, &f.RunID, &f.AccountID, &f.Currency, &f.BalanceCents, &f.ExpectedBalanceCents,
+			&f.LedgerBalanceCents, &f.DriftCents, &f.Repaired, &f.CreatedAt,
+		); err != nil {
+			return nil, types.PageCursors{}, fmt.Errorf("failed to scan reconciliation finding: %w", err)
+		}
+		i := runIndex[f.RunID]
+		runs[i].Findings = append(runs[i].Findings, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, types.PageCursors{}, fmt.Errorf("failed to query reconciliation findings: %w", err)
+	}
+	return runs, page, nil
+}
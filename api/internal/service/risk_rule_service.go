@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/risk"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// RiskRuleService manages the risk rules the worker screens transactions
+// with. Workers pick up changes on their next refresh.
+type RiskRuleService struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewRiskRuleService creates a new risk rule service
+func NewRiskRuleService(db *sql.DB, logger *zap.Logger) *RiskRuleService {
+	return &RiskRuleService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListRiskRules returns every rule, enabled or not, in evaluation order
+func (s *RiskRuleService) ListRiskRules(ctx context.Context) ([]types.RiskRule, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return risk.LoadRules(ctx, s.db, false)
+}
+
+// SetRiskRule creates or replaces the rule with the rule's name. The change
+// is audited.
+func (s *RiskRuleService) SetRiskRule(ctx context.Context, rule types.RiskRule, actor string) (*types.RiskRule, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	conditions, err := json.Marshal(rule.Conditions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal conditions: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	upsertQuery := `
+		INSERT INTO risk_rules (name, description, action, priority, conditions, enabled)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6)
+		ON CONFLICT (name) DO UPDATE
+		SET description = EXCLUDED.description,
+		    action = EXCLUDED.action,
+		    priority = EXCLUDED.priority,
+		    conditions = EXCLUDED.conditions,
+		    enabled = EXCLUDED.enabled
+		RETURNING id, updated_at
+	`
+	var id uuid.UUID
+	var updatedAt time.Time
+	err = tx.QueryRowContext(ctx, upsertQuery,
+		rule.Name, rule.Description, rule.Action, rule.Priority, conditions, rule.Enabled,
+	).Scan(&id, &updatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set risk rule: %w", err)
+	}
+	rule.UpdatedAt = &updatedAt
+
+	if err := writeAuditLog(ctx, tx, types.AuditActionRiskRuleUpdated, types.AuditEntityRiskRule, id, actor, rule); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Risk rule updated",
+		zap.String("rule", rule.Name),
+		zap.String("action", string(rule.Action)),
+		zap.Bool("enabled", rule.Enabled),
+		zap.String("actor", actor),
+	)
+
+	return &rule, nil
+}
+
+// DeleteRiskRule removes a rule. The removal is audited.
+func (s *RiskRuleService) DeleteRiskRule(ctx context.Context, name, actor string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	var id uuid.UUID
+	if err := tx.QueryRowContext(ctx, `DELETE FROM risk_rules WHERE name = $1 RETURNING id`, name).Scan(&id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("risk rule not found")
+		}
+		return fmt.Errorf("failed to delete risk rule: %w", err)
+	}
+
+	if err := writeAuditLog(ctx, tx, types.AuditActionRiskRuleDeleted, types.AuditEntityRiskRule, id, actor, map[string]string{"name": name}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Risk rule deleted", zap.String("rule", name), zap.String("actor", actor))
+	return nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/recurrence"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// ScheduleRunner materializes due occurrences of recurring schedules through
+// TransactionService.CreateTransaction. Each occurrence uses a deterministic
+// idempotency key, so an occurrence retried after a crash or restart
+// returns the transaction already created instead of charging twice.
+type ScheduleRunner struct {
+	db                 *sql.DB
+	transactionService *TransactionService
+	logger             *zap.Logger
+	interval           time.Duration
+}
+
+// NewScheduleRunner creates a new recurring schedule runner
+func NewScheduleRunner(db *sql.DB, transactionService *TransactionService, interval time.Duration, logger *zap.Logger) *ScheduleRunner {
+	return &ScheduleRunner{
+		db:                 db,
+		transactionService: transactionService,
+		logger:             logger,
+		interval:           interval,
+	}
+}
+
+// Start runs the runner loop until the context is cancelled
+func (r *ScheduleRunner) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.logger.Info("Schedule runner started", zap.Duration("interval", r.interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Schedule runner stopping...")
+			return
+		case <-ticker.C:
+			// Catch up on every due occurrence before waiting for the next tick
+			for {
+				ran, err := r.runNext(ctx)
+				if err != nil {
+					r.logger.Error("Failed to run recurring schedule", zap.Error(err))
+					break
+				}
+				if !ran {
+					break
+				}
+			}
+		}
+	}
+}
+
+// OccurrenceIdempotencyKey returns the idempotency key of one occurrence of a schedule
+func OccurrenceIdempotencyKey(scheduleID uuid.UUID, occurrence time.Time) string {
+	return fmt.Sprintf("schedule:%s:%s", scheduleID, occurrence.UTC().Format(time.RFC3339))
+}
+
+// runNext materializes the oldest due occurrence of one schedule and advances
+// the schedule. The schedule row stays locked meanwhile so updates and other
+// runners wait. Returns false when nothing is due.
+func (r *ScheduleRunner) runNext(ctx context.Context) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			r.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	query := `
+		SELECT ` + scheduleColumns + `
+		FROM recurring_schedules
+		WHERE status = $1 AND next_run_at <= NOW()
+		ORDER BY next_run_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+	var schedule types.RecurringSchedule
+	if err := scanSchedule(tx.QueryRowContext(ctx, query, types.ScheduleStatusActive), &schedule); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to select due schedule: %w", err)
+	}
+	occurrence := *schedule.NextRunAt
+
+	// Occurrences belong to the tenant of the schedule's account. A missing
+	// account keeps the default tenant and fails as "account not found".
+	var tenantID string
+	err = tx.QueryRowContext(ctx, `SELECT tenant_id FROM accounts WHERE id = $1`, schedule.Template.AccountID).Scan(&tenantID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("failed to look up schedule tenant: %w", err)
+	}
+
+	req := schedule.Template.Request(OccurrenceIdempotencyKey(schedule.ID, occurrence))
+	transaction, err := r.transactionService.CreateTransaction(tenant.WithID(ctx, tenantID), req)
+	var transactionID *uuid.UUID
+	var lastError *string
+	result := "created"
+	if err != nil {
+		switch err.Error() {
+		case "account not found", "account is not active", "amount must be positive":
+			// The occurrence can never succeed; record it and move on
+			message := err.Error()
+			lastError = &message
+			result = "failed"
+		default:
+			// Leave the schedule due so the next tick retries the same occurrence
+			return false, fmt.Errorf("schedule %s: %w", schedule.ID, err)
+		}
+	} else {
+		transactionID = &transaction.ID
+	}
+
+	count := schedule.OccurrenceCount + 1
+	status := types.ScheduleStatusActive
+	next, ok := recurrence.Next(schedule.Rule, schedule.StartAt, occurrence)
+	nextRunAt := &next
+	if !ok || (schedule.Rule.Count > 0 && count >= schedule.Rule.Count) {
+		nextRunAt = nil
+		status = types.ScheduleStatusCompleted
+	}
+
+	updateQuery := `
+		UPDATE recurring_schedules
+		SET occurrence_count = $1, last_run_at = $2, next_run_at = $3, status = $4,
+		    last_transaction_id = COALESCE($5, last_transaction_id), last_error = $6
+		WHERE id = $7
+	`
+	_, err = tx.ExecContext(ctx, updateQuery, count, occurrence, nextRunAt, status, transactionID, lastError, schedule.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to advance schedule: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	recurringOccurrencesTotal.WithLabelValues(result).Inc()
+	fields := []zap.Field{
+		zap.String("schedule_id", schedule.ID.String()),
+		zap.Time("occurrence", occurrence),
+		zap.String("status", string(status)),
+	}
+	if lastError != nil {
+		r.logger.Warn("Recurring occurrence failed", append(fields, zap.String("error", *lastError))...)
+	} else {
+		r.logger.Info("Recurring occurrence created", append(fields, zap.String("transaction_id", transactionID.String()))...)
+	}
+
+	return true, nil
+}
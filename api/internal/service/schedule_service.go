@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/currency"
+	"github.com/yash/transaction-system/shared/recurrence"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// ScheduleService manages recurring transaction schedules. Occurrences are
+// created by the ScheduleRunner.
+type ScheduleService struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewScheduleService creates a new schedule service
+func NewScheduleService(db *sql.DB, logger *zap.Logger) *ScheduleService {
+	return &ScheduleService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateSchedule creates an ACTIVE schedule whose first occurrence is the
+// first one of its rule at or after start_at (default now). req.Rule must be
+// set and valid.
+func (s *ScheduleService) CreateSchedule(ctx context.Context, req types.CreateScheduleRequest) (*types.RecurringSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if !currency.Valid(req.Template.Currency) {
+		return nil, fmt.Errorf("unsupported currency")
+	}
+
+	var accountStatus string
+	accountQuery := `SELECT status FROM accounts WHERE id = $1 AND tenant_id = $2 AND system_code IS NULL`
+	err := s.db.QueryRowContext(ctx, accountQuery, req.Template.AccountID, tenant.FromContext(ctx)).Scan(&accountStatus)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("account not found")
+		}
+		return nil, fmt.Errorf("failed to validate account: %w", err)
+	}
+	if accountStatus != string(types.AccountStatusActive) {
+		return nil, fmt.Errorf("account is not active")
+	}
+
+	startAt := time.Now().UTC()
+	if req.StartAt != nil {
+		startAt = req.StartAt.UTC()
+	}
+	nextRunAt, ok := recurrence.Next(*req.Rule, startAt, startAt.Add(-time.Nanosecond))
+	if !ok {
+		return nil, fmt.Errorf("rule has no occurrences")
+	}
+
+	ruleJSON, err := json.Marshal(req.Rule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rule: %w", err)
+	}
+	var metadataValue interface{}
+	if len(req.Template.Metadata) > 0 {
+		metadataValue = req.Template.Metadata
+	}
+
+	query := `
+		INSERT INTO recurring_schedules (id, account_id, amount_cents, currency, type, metadata, rule,
+		                                 start_at, next_run_at, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+		RETURNING ` + scheduleColumns + `
+	`
+	var schedule types.RecurringSchedule
+	err = scanSchedule(s.db.QueryRowContext(ctx, query,
+		uuid.New(), req.Template.AccountID, req.Template.AmountCents, req.Template.Currency, req.Template.Type,
+		metadataValue, ruleJSON, startAt, nextRunAt, types.ScheduleStatusActive,
+	), &schedule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schedule: %w", err)
+	}
+
+	s.logger.Info("Recurring schedule created",
+		zap.String("schedule_id", schedule.ID.String()),
+		zap.String("account_id", schedule.Template.AccountID.String()),
+		zap.String("rrule", schedule.RRule),
+	)
+
+	return &schedule, nil
+}
+
+// GetSchedule retrieves a schedule on an account of the tenant of ctx by ID
+func (s *ScheduleService) GetSchedule(ctx context.Context, scheduleID uuid.UUID) (*types.RecurringSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT ` + scheduleColumns + `
+		FROM recurring_schedules
+		WHERE id = $1 AND account_id IN (SELECT id FROM accounts WHERE tenant_id = $2)
+	`
+	var schedule types.RecurringSchedule
+	if err := scanSchedule(s.db.QueryRowContext(ctx, query, scheduleID, tenant.FromContext(ctx)), &schedule); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("schedule not found")
+		}
+		return nil, fmt.Errorf("failed to get schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+// scheduleOrder is the keyset order of schedule lists, newest first
+var scheduleOrder = keysetOrder{sort: "-created_at", column: "created_at", cast: "timestamptz", desc: true}
+
+// ListSchedules lists the schedules of the tenant of ctx a page at a time,
+// newest first, optionally filtered by account and status. cursorToken is a
+// cursor of a previous page with the same filters; an empty token starts at
+// the first page.
+func (s *ScheduleService) ListSchedules(ctx context.Context, accountID *uuid.UUID, status types.ScheduleStatus, cursorToken string, limit int) ([]types.RecurringSchedule, types.PageCursors, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	order := scheduleOrder.withFilter(map[string]interface{}{"account_id": accountID, "status": status})
+	after, err := decodeCursor(cursorToken, order)
+	if err != nil {
+		return nil, types.PageCursors{}, err
+	}
+
+	args := []interface{}{accountID, string(status), tenant.FromContext(ctx)}
+	conditions := []string{"($1::uuid IS NULL OR account_id = $1)", "($2 = '' OR status = $2)", "account_id IN (SELECT id FROM accounts WHERE tenant_id = $3)"}
+	keysetCondition, args, orderBy := order.clauses(after, args)
+	if keysetCondition != "" {
+		conditions = append(conditions, keysetCondition)
+	}
+
+	// One row more than the page shows whether another page follows
+	args = append(args, limit+1)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM recurring_schedules
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d
+	`, scheduleColumns, strings.Join(conditions, " AND "), orderBy, len(args))
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, types.PageCursors{}, fmt.Errorf("failed to query schedules: %w", err)
+	}
+	defer rows.Close()
+
+	schedules := []types.RecurringSchedule{}
+	for rows.Next() {
+		var schedule types.RecurringSchedule
+		if err := scanSchedule(rows, &schedule); err != nil {
+			return nil, types.PageCursors{}, fmt.Errorf("failed to scan schedule: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, types.PageCursors{}, fmt.Errorf("failed to iterate schedules: %w", err)
+	}
+
+	hasMore := len(schedules) > limit
+	if hasMore {
+		schedules = schedules[:limit]
+	}
+	if after != nil && after.Backward {
+		for i, j := 0, len(schedules)-1; i < j; i, j = i+1, j-1 {
+			schedules[i], schedules[j] = schedules[j], schedules[i]
+		}
+	}
+
+	var first, last *cursor
+	if len(schedules) > 0 {
+		first = &cursor{ID: schedules[0].ID, Value: schedules[0].CreatedAt.Format(time.RFC3339Nano)}
+		last = &cursor{ID: schedules[len(schedules)-1].ID, Value: schedules[len(schedules)-1].CreatedAt.Format(time.RFC3339Nano)}
+	}
+	return schedules, order.pageCursors(after, first, last, hasMore), nil
+}
+
+// UpdateSchedule changes the amount, metadata or rule of a schedule, or
+// pauses and resumes it. Resuming or changing the rule moves the next
+// occurrence to the first one after now; occurrences missed while paused
+// are skipped.
+func (s *ScheduleService) UpdateSchedule(ctx context.Context, scheduleID uuid.UUID, req types.UpdateScheduleRequest) (*types.RecurringSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	// Waits for the runner if it is materializing an occurrence right now
+	lockQuery := `
+		SELECT ` + scheduleColumns + `
+		FROM recurring_schedules
+		WHERE id = $1 AND account_id IN (SELECT id FROM accounts WHERE tenant_id = $2)
+		FOR UPDATE
+	`
+	var schedule types.RecurringSchedule
+	if err := scanSchedule(tx.QueryRowContext(ctx, lockQuery, scheduleID, tenant.FromContext(ctx)), &schedule); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("schedule not found")
+		}
+		return nil, fmt.Errorf("failed to lock schedule: %w", err)
+	}
+	if schedule.Status != types.ScheduleStatusActive && schedule.Status != types.ScheduleStatusPaused {
+		return nil, fmt.Errorf("schedule has ended")
+	}
+
+	reschedule := false
+	if req.AmountCents != nil {
+		schedule.Template.AmountCents = *req.AmountCents
+	}
+	if len(req.Metadata) > 0 {
+		schedule.Template.Metadata = req.Metadata
+	}
+	if req.Rule != nil {
+		schedule.Rule = *req.Rule
+		reschedule = true
+	}
+	if req.Status != nil && *req.Status != schedule.Status {
+		schedule.Status = *req.Status
+		reschedule = reschedule || schedule.Status == types.ScheduleStatusActive
+	}
+
+	if reschedule {
+		after := time.Now().UTC()
+		if schedule.LastRunAt != nil && schedule.LastRunAt.After(after) {
+			after = *schedule.LastRunAt
+		}
+		next, ok := recurrence.Next(schedule.Rule, schedule.StartAt, after)
+		if ok && (schedule.Rule.Count == 0 || schedule.OccurrenceCount < schedule.Rule.Count) {
+			schedule.NextRunAt = &next
+		} else {
+			schedule.NextRunAt = nil
+			schedule.Status = types.ScheduleStatusCompleted
+		}
+	}
+
+	ruleJSON, err := json.Marshal(schedule.Rule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rule: %w", err)
+	}
+	var metadataValue interface{}
+	if len(schedule.Template.Metadata) > 0 {
+		metadataValue = schedule.Template.Metadata
+	}
+
+	updateQuery := `
+		UPDATE recurring_schedules
+		SET amount_cents = $1, metadata = $2, rule = $3, next_run_at = $4, status = $5
+		WHERE id = $6
+		RETURNING ` + scheduleColumns + `
+	`
+	err = scanSchedule(tx.QueryRowContext(ctx, updateQuery,
+		schedule.Template.AmountCents, metadataValue, ruleJSON, schedule.NextRunAt, schedule.Status, scheduleID,
+	), &schedule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update schedule: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Recurring schedule updated",
+		zap.String("schedule_id", scheduleID.String()),
+		zap.String("status", string(schedule.Status)),
+	)
+
+	return &schedule, nil
+}
+
+// CancelSchedule stops a schedule for good. It is kept for its history.
+func (s *ScheduleService) CancelSchedule(ctx context.Context, scheduleID uuid.UUID) (*types.RecurringSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	query := `
+		UPDATE recurring_schedules
+		SET status = $1, next_run_at = NULL
+		WHERE id = $2 AND status IN ('ACTIVE', 'PAUSED') AND account_id IN (SELECT id FROM accounts WHERE tenant_id = $3)
+		RETURNING ` + scheduleColumns + `
+	`
+	var schedule types.RecurringSchedule
+	err := scanSchedule(s.db.QueryRowContext(ctx, query, types.ScheduleStatusCancelled, scheduleID, tenant.FromContext(ctx)), &schedule)
+	if err == nil {
+		s.logger.Info("Recurring schedule cancelled", zap.String("schedule_id", scheduleID.String()))
+		return &schedule, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to cancel schedule: %w", err)
+	}
+
+	if _, err := s.GetSchedule(ctx, scheduleID); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("schedule has ended")
+}
+
+// scheduleColumns is the column list read by scanSchedule
+const scheduleColumns = `id, account_id, amount_cents, currency, type, metadata, rule, start_at, next_run_at,
+		       last_run_at, occurrence_count, last_transaction_id, last_error, status, created_at, updated_at`
+
+// scanSchedule scans a row selected with scheduleColumns
+func scanSchedule(row rowScanner, schedule *types.RecurringSchedule) error {
+	var metadata, rule []byte
+	err := row.Scan(
+		&schedule.ID, &schedule.Template.AccountID, &schedule.Template.AmountCents,
+		&schedule.Template.Currency, &schedule.Template.Type, &metadata, &rule,
+		&schedule.StartAt, &schedule.NextRunAt, &schedule.LastRunAt, &schedule.OccurrenceCount,
+		&schedule.LastTransactionID, &schedule.LastError, &schedule.Status, &schedule.CreatedAt, &schedule.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	schedule.Template.Metadata = metadata
+	if err := json.Unmarshal(rule, &schedule.Rule); err != nil {
+		return fmt.Errorf("failed to decode rule: %w", err)
+	}
+	schedule.RRule = recurrence.Format(schedule.Rule)
+	return nil
+}
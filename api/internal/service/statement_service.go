@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// StatementService builds per-account statements from the ledger and stores
+// month-end statements once their period has closed
+type StatementService struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewStatementService creates a new statement service
+func NewStatementService(db *sql.DB, logger *zap.Logger) *StatementService {
+	return &StatementService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ParseStatementPeriod parses a YYYY-MM period into its UTC start and end
+func ParseStatementPeriod(period string) (time.Time, time.Time, error) {
+	start, err := time.Parse("2006-01", period)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period")
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
+
+// GetStatement returns the stored statement for a period if one was
+// generated, otherwise builds it from the ledger. The current period is
+// built up to now.
+func (s *StatementService) GetStatement(ctx context.Context, accountID uuid.UUID, period string) (*types.Statement, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	start, end, err := ParseStatementPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+	if start.After(time.Now()) {
+		return nil, fmt.Errorf("period has not started")
+	}
+	if _, err := accountCurrency(ctx, s.db, accountID); err != nil {
+		return nil, err
+	}
+
+	var statementJSON []byte
+	storedQuery := `SELECT statement FROM account_statements WHERE account_id = $1 AND period = $2`
+	err = s.db.QueryRowContext(ctx, storedQuery, accountID, period).Scan(&statementJSON)
+	if err == nil {
+		var statement types.Statement
+		if err := json.Unmarshal(statementJSON, &statement); err != nil {
+			return nil, fmt.Errorf("failed to decode stored statement: %w", err)
+		}
+		return &statement, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to load stored statement: %w", err)
+	}
+
+	return s.buildStatement(ctx, accountID, period, start, end)
+}
+
+// GenerateStatements stores the statement of a closed period for every
+// ACTIVE account of every tenant. Statements already generated are left
+// untouched, so the batch can be re-run safely. Returns the number of
+// statements created.
+func (s *StatementService) GenerateStatements(ctx context.Context, period string) (int, error) {
+	start, end, err := ParseStatementPeriod(period)
+	if err != nil {
+		return 0, err
+	}
+	if end.After(time.Now()) {
+		return 0, fmt.Errorf("period has not ended")
+	}
+
+	accountsQuery := `
+		SELECT id, tenant_id
+		FROM accounts
+		WHERE status = $1 AND system_code IS NULL AND created_at < $2
+		ORDER BY id
+	`
+	rows, err := s.db.QueryContext(ctx, accountsQuery, types.AccountStatusActive, end)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query accounts: %w", err)
+	}
+	var accountIDs []uuid.UUID
+	accountTenants := make(map[uuid.UUID]string)
+	for rows.Next() {
+		var accountID uuid.UUID
+		var tenantID string
+		if err := rows.Scan(&accountID, &tenantID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan account: %w", err)
+		}
+		accountIDs = append(accountIDs, accountID)
+		accountTenants[accountID] = tenantID
+	}
+	rows.Close()
+
+	insertQuery := `
+		INSERT INTO account_statements (account_id, period, opening_balance_cents, closing_balance_cents, statement, generated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (account_id, period) DO NOTHING
+	`
+	created := 0
+	for _, accountID := range accountIDs {
+		// Each statement is built as its account's tenant would see it
+		statement, err := s.buildStatement(tenant.WithID(ctx, accountTenants[accountID]), accountID, period, start, end)
+		if err != nil {
+			return created, fmt.Errorf("account %s: %w", accountID, err)
+		}
+		statementJSON, err := json.Marshal(statement)
+		if err != nil {
+			return created, fmt.Errorf("failed to marshal statement: %w", err)
+		}
+
+		result, err := s.db.ExecContext(ctx, insertQuery,
+			accountID, period, statement.OpeningBalanceCents, statement.ClosingBalanceCents, statementJSON, statement.GeneratedAt,
+		)
+		if err != nil {
+			return created, fmt.Errorf("failed to store statement: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n > 0 {
+			created++
+		}
+	}
+
+	s.logger.Info("Statements generated",
+		zap.String("period", period),
+		zap.Int("accounts", len(accountIDs)),
+		zap.Int("created", created),
+	)
+
+	return created, nil
+}
+
+// buildStatement lists the account's postings in [start, end) with a running
+// balance starting from the ledger balance just before start
+func (s *StatementService) buildStatement(ctx context.Context, accountID uuid.UUID, period string, start, end time.Time) (*types.Statement, error) {
+	currency, err := accountCurrency(ctx, s.db, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	opening, err := ledgerBalanceAt(ctx, s.db, accountID, start.Add(-time.Microsecond))
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT p.created_at, p.transaction_id, COALESCE(t.type, 'ADJUSTMENT'), p.direction, p.amount_cents, je.description
+		FROM postings p
+		JOIN journal_entries je ON je.id = p.journal_entry_id
+		LEFT JOIN transactions t ON t.id = p.transaction_id
+		WHERE p.account_id = $1 AND p.created_at >= $2 AND p.created_at < $3
+		ORDER BY p.created_at, p.id
+	`
+	rows, err := s.db.QueryContext(ctx, query, accountID, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query postings: %w", err)
+	}
+	defer rows.Close()
+
+	statement := &types.Statement{
+		AccountID:           accountID,
+		Currency:            currency,
+		Period:              period,
+		PeriodStart:         start,
+		PeriodEnd:           end,
+		OpeningBalanceCents: opening,
+		Lines:               []types.StatementLine{},
+		GeneratedAt:         time.Now().UTC(),
+	}
+
+	balance := opening
+	totals := make(map[string]*types.StatementTotal)
+	for rows.Next() {
+		var line types.StatementLine
+		if err := rows.Scan(&line.PostedAt, &line.TransactionID, &line.Type, &line.Direction, &line.AmountCents, &line.Description); err != nil {
+			return nil, fmt.Errorf("failed to scan posting: %w", err)
+		}
+
+		total, ok := totals[line.Type]
+		if !ok {
+			total = &types.StatementTotal{Type: line.Type}
+			totals[line.Type] = total
+		}
+		total.Count++
+		if line.Direction == types.PostingDirectionCredit {
+			balance += line.AmountCents
+			total.CreditsCents += line.AmountCents
+		} else {
+			balance -= line.AmountCents
+			total.DebitsCents += line.AmountCents
+		}
+
+		line.RunningBalanceCents = balance
+		statement.Lines = append(statement.Lines, line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query postings: %w", err)
+	}
+
+	statement.ClosingBalanceCents = balance
+	statement.Totals = make([]types.StatementTotal, 0, len(totals))
+	for _, total := range totals {
+		statement.Totals = append(statement.Totals, *total)
+	}
+	sort.Slice(statement.Totals, func(i, j int) bool {
+		return statement.Totals[i].Type < statement.Totals[j].Type
+	})
+
+	return statement, nil
+}
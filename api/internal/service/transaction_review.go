@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/outbox"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/txstatus"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// ReviewTransaction resolves a transaction a risk rule held for review.
+// Approving returns it to PENDING with a new transaction.created event, which
+// the worker applies without screening it again; rejecting fails it with
+// REVIEW_REJECTED. The decision is audited.
+func (s *TransactionService) ReviewTransaction(ctx context.Context, transactionID uuid.UUID, decision types.ReviewDecision, note, actor string) (*types.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	tenantID := tenant.FromContext(ctx)
+	if err := tenant.Scope(ctx, tx, tenantID); err != nil {
+		return nil, err
+	}
+
+	status, action := types.TransactionStatusPending, types.AuditActionTransactionApproved
+	var failureReason, failureCode string
+	if decision == types.ReviewDecisionRejected {
+		status, action = types.TransactionStatusFailed, types.AuditActionTransactionRejected
+		failureReason, failureCode = "rejected in review", string(types.FailureCodeReviewRejected)
+		if note != "" {
+			failureReason += ": " + note
+		}
+	}
+
+	// The lock makes concurrent decisions on the same transaction safe
+	var current types.TransactionStatus
+	lockQuery := `SELECT status FROM transactions WHERE id = $1 AND tenant_id = $2 FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, lockQuery, transactionID, tenantID).Scan(&current); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("transaction not found")
+		}
+		return nil, fmt.Errorf("failed to lock transaction: %w", err)
+	}
+	if current != types.TransactionStatusInReview {
+		return nil, fmt.Errorf("transaction is not in review")
+	}
+
+	if _, err := txstatus.Transition(ctx, tx, transactionID, status, failureReason, actor); err != nil {
+		return nil, fmt.Errorf("failed to review transaction: %w", err)
+	}
+	query := `
+		UPDATE transactions
+		SET review_decision = $1, reviewed_by = $2, reviewed_at = NOW(),
+		    failure_reason = NULLIF($3, ''), failure_code = NULLIF($4, ''), updated_at = NOW()
+		WHERE id = $5
+		RETURNING ` + transactionColumns + `
+	`
+	var transaction types.Transaction
+	err = scanTransaction(tx.QueryRowContext(ctx, query, decision, actor, failureReason, failureCode, transactionID), &transaction)
+	if err != nil {
+		return nil, fmt.Errorf("failed to review transaction: %w", err)
+	}
+
+	if decision == types.ReviewDecisionApproved {
+		if err := outbox.Write(ctx, tx, "transaction", transaction.ID, types.EventTypeTransactionCreated, transaction.CreatedPayload()); err != nil {
+			return nil, err
+		}
+	}
+
+	details := map[string]interface{}{
+		"review_reason": transaction.ReviewReason,
+		"note":          note,
+	}
+	if err := writeAuditLog(ctx, tx, action, types.AuditEntityTransaction, transactionID, actor, details); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Transaction reviewed",
+		zap.String("transaction_id", transactionID.String()),
+		zap.String("decision", string(decision)),
+		zap.String("actor", actor),
+	)
+
+	return &transaction, nil
+}
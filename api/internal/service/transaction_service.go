@@ -6,9 +6,18 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yash/transaction-system/shared/currency"
+	"github.com/yash/transaction-system/shared/limits"
+	"github.com/yash/transaction-system/shared/outbox"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/txstatus"
 	"github.com/yash/transaction-system/shared/types"
 	"go.uber.org/zap"
 )
@@ -45,64 +54,386 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, req types.Cr
 		}
 	}()
 
-	// Check idempotency: if same (account_id, idempotency_key) exists, return it
-	var existingTx types.Transaction
-	var existingMetadata []byte
-	checkQuery := `
-		SELECT id, account_id, amount_cents, currency, type, status, idempotency_key, 
-		       failure_reason, metadata, created_at, updated_at
-		FROM transactions
-		WHERE account_id = $1 AND idempotency_key = $2
-		LIMIT 1
-	`
-	err = tx.QueryRowContext(ctx, checkQuery, req.AccountID, req.IdempotencyKey).Scan(
-		&existingTx.ID, &existingTx.AccountID, &existingTx.AmountCents,
-		&existingTx.Currency, &existingTx.Type, &existingTx.Status,
-		&existingTx.IdempotencyKey, &existingTx.FailureReason,
-		&existingMetadata, &existingTx.CreatedAt, &existingTx.UpdatedAt,
-	)
-	if err == nil {
-		existingTx.Metadata = existingMetadata
+	if err := tenant.Scope(ctx, tx, tenant.FromContext(ctx)); err != nil {
+		return nil, err
 	}
 
-	if err == nil {
+	transaction, existing, err := s.createTransactionInTx(ctx, tx, req)
+	if err != nil {
+		// Check if it's a unique constraint violation (race condition)
+		if isIdempotencyConflict(err) {
+			// Another request created it - fetch and return. The failed
+			// insert aborted tx, so the lookup runs outside it.
+			tx.Rollback()
+			existingTx, err := findByIdempotencyKey(ctx, s.db, req.AccountID, req.IdempotencyKey)
+			if err == nil {
+				return existingTx, nil
+			}
+			return nil, fmt.Errorf("failed to create transaction: %w", err)
+		}
+		return nil, err
+	}
+	if existing {
 		// Idempotent request - return existing transaction
 		tx.Commit()
 		s.logger.Info("Idempotent transaction request",
-			zap.String("transaction_id", existingTx.ID.String()),
+			zap.String("transaction_id", transaction.ID.String()),
 			zap.String("idempotency_key", req.IdempotencyKey),
 		)
-		return &existingTx, nil
+		return transaction, nil
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Transaction created with outbox event",
+		zap.String("transaction_id", transaction.ID.String()),
+		zap.String("idempotency_key", req.IdempotencyKey),
+	)
+
+	return transaction, nil
+}
+
+// createTransactionInTx validates a transaction request and inserts it
+// inside the caller's DB transaction. If the idempotency key was already used
+// on the account, the existing transaction is returned with existing=true.
+// A concurrent insert of the same key surfaces as an idempotency conflict.
+func (s *TransactionService) createTransactionInTx(ctx context.Context, tx *sql.Tx, req types.CreateTransactionRequest) (*types.Transaction, bool, error) {
+	// Check idempotency: if same (account_id, idempotency_key) exists, return it
+	existingTx, err := findByIdempotencyKey(ctx, tx, req.AccountID, req.IdempotencyKey)
+	if err == nil {
+		return existingTx, true, nil
 	}
 
 	if !errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf("failed to check idempotency: %w", err)
+		return nil, false, fmt.Errorf("failed to check idempotency: %w", err)
+	}
+
+	if !currency.Valid(req.Currency) {
+		return nil, false, fmt.Errorf("unsupported currency")
 	}
 
 	// Validate account exists
-	var accountStatus string
-	accountQuery := `SELECT status FROM accounts WHERE id = $1`
-	err = tx.QueryRowContext(ctx, accountQuery, req.AccountID).Scan(&accountStatus)
+	var accountStatus, accountCurrency string
+	accountQuery := `SELECT status, currency FROM accounts WHERE id = $1 AND tenant_id = $2 AND system_code IS NULL`
+	err = tx.QueryRowContext(ctx, accountQuery, req.AccountID, tenant.FromContext(ctx)).Scan(&accountStatus, &accountCurrency)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("account not found")
+			return nil, false, fmt.Errorf("account not found")
 		}
-		return nil, fmt.Errorf("failed to validate account: %w", err)
+		return nil, false, fmt.Errorf("failed to validate account: %w", err)
 	}
 
 	if accountStatus != string(types.AccountStatusActive) {
-		return nil, fmt.Errorf("account is not active")
+		return nil, false, fmt.Errorf("account is not active")
+	}
+
+	// CAPTURE and VOID settle an existing authorization on the same account
+	if req.Type == types.TransactionTypeCapture || req.Type == types.TransactionTypeVoid {
+		if err := s.validateAuthorizationReference(ctx, tx, &req); err != nil {
+			return nil, false, err
+		}
+	}
+
+	// A locked quote must convert the transaction currency into the account currency
+	if req.FXQuoteID != nil {
+		var quote types.FXQuote
+		if err := findFXQuote(ctx, tx, *req.FXQuoteID, &quote); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, false, fmt.Errorf("fx quote not found")
+			}
+			return nil, false, fmt.Errorf("failed to validate fx quote: %w", err)
+		}
+		if quote.BaseCurrency != req.Currency || quote.QuoteCurrency != accountCurrency {
+			return nil, false, fmt.Errorf("fx quote does not match currency pair")
+		}
+		if quote.ExpiresAt.Before(time.Now()) {
+			return nil, false, fmt.Errorf("fx quote has expired")
+		}
 	}
 
 	// Validate amount
 	if req.AmountCents <= 0 {
-		return nil, fmt.Errorf("amount must be positive")
+		return nil, false, fmt.Errorf("amount must be positive")
+	}
+
+	// Check debit limits against the debits already accepted. A card
+	// authorization counts as a debit; its capture does not count again.
+	// Cross-currency debits are checked by the worker once their settled
+	// amount is known.
+	var violation *types.LimitViolation
+	isDebit := req.Type == types.TransactionTypeDebit || req.Type == types.TransactionTypeAuthorize
+	if isDebit && req.Currency == accountCurrency {
+		at := time.Now()
+		if req.ExecuteAt != nil && req.ExecuteAt.After(at) {
+			at = *req.ExecuteAt
+		}
+		violation, err = limits.Check(ctx, tx, req.AccountID, req.AmountCents, at, limits.AcceptedStatuses, uuid.Nil)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	transaction, err := s.insertTransaction(ctx, tx, req, nil, violation)
+	if err != nil {
+		if isIdempotencyConflict(err) {
+			return nil, false, err
+		}
+		return nil, false, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	return transaction, false, nil
+}
+
+// CreateTransactionBatch creates many transactions in one DB transaction.
+// In ATOMIC mode the first failing item rolls back the whole batch: every
+// item is reported uncommitted, the failing one with its error, alongside a
+// "batch rolled back" error. In BEST_EFFORT mode each item runs under its own
+// savepoint, so a failing item is rolled back alone and reported with its
+// error while the others are committed. The batch runs serializable like
+// single transactions, so the debit limit checks of concurrent batches see
+// each other; a serialization failure rolls the batch back with a "batch
+// conflicted" error, and the client retries it with the same idempotency keys.
+func (s *TransactionService) CreateTransactionBatch(ctx context.Context, mode types.BatchMode, reqs []types.CreateTransactionRequest) (*types.TransactionBatchResult, error) {
+	// Leaves time to respond within the server's 15 second write timeout
+	ctx, cancel := context.WithTimeout(ctx, 12*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	if err := tenant.Scope(ctx, tx, tenant.FromContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	result := &types.TransactionBatchResult{
+		Mode:  mode,
+		Items: make([]types.TransactionBatchItem, 0, len(reqs)),
+	}
+	for i, req := range reqs {
+		if mode == types.BatchModeAtomic {
+			transaction, _, err := s.createTransactionInTx(ctx, tx, req)
+			if isSerializationFailure(err) {
+				return nil, fmt.Errorf("batch conflicted with a concurrent request")
+			}
+			if err != nil {
+				result.FailedCount = 1
+				result.Items = make([]types.TransactionBatchItem, len(reqs))
+				for j := range result.Items {
+					result.Items[j].Index = j
+				}
+				result.Items[i].Err = err
+				return result, fmt.Errorf("batch rolled back")
+			}
+			result.Items = append(result.Items, types.TransactionBatchItem{Index: i, Transaction: transaction})
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT batch_item`); err != nil {
+			return nil, fmt.Errorf("failed to create savepoint: %w", err)
+		}
+		transaction, _, err := s.createTransactionInTx(ctx, tx, req)
+		// A serialization failure dooms the whole DB transaction, not just the item
+		if isSerializationFailure(err) {
+			return nil, fmt.Errorf("batch conflicted with a concurrent request")
+		}
+		if err != nil {
+			if _, rollbackErr := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT batch_item`); rollbackErr != nil {
+				return nil, fmt.Errorf("failed to roll back item: %w", rollbackErr)
+			}
+			// The key was taken by a concurrent request since the check
+			if isIdempotencyConflict(err) {
+				transaction, err = findByIdempotencyKey(ctx, tx, req.AccountID, req.IdempotencyKey)
+			}
+		}
+		if _, releaseErr := tx.ExecContext(ctx, `RELEASE SAVEPOINT batch_item`); releaseErr != nil {
+			return nil, fmt.Errorf("failed to release savepoint: %w", releaseErr)
+		}
+		if err != nil {
+			result.FailedCount++
+			result.Items = append(result.Items, types.TransactionBatchItem{Index: i, Err: err})
+			continue
+		}
+		result.Items = append(result.Items, types.TransactionBatchItem{Index: i, Transaction: transaction})
+	}
+
+	if err := tx.Commit(); err != nil {
+		if isSerializationFailure(err) {
+			return nil, fmt.Errorf("batch conflicted with a concurrent request")
+		}
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	result.Committed = true
+	result.SucceededCount = len(reqs) - result.FailedCount
+	for i := range result.Items {
+		result.Items[i].Committed = result.Items[i].Err == nil
+	}
+
+	s.logger.Info("Transaction batch created",
+		zap.String("mode", string(mode)),
+		zap.Int("succeeded", result.SucceededCount),
+		zap.Int("failed", result.FailedCount),
+	)
+
+	return result, nil
+}
+
+// ReverseTransaction creates a reversal of a processed transaction. The
+// reversal is an opposite-typed transaction on the same account linked via
+// reverses_transaction_id; the worker re-validates the refunded total.
+func (s *TransactionService) ReverseTransaction(ctx context.Context, transactionID uuid.UUID, req types.ReverseTransactionRequest) (*types.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	tenantID := tenant.FromContext(ctx)
+	if err := tenant.Scope(ctx, tx, tenantID); err != nil {
+		return nil, err
+	}
+
+	// Lock the original so concurrent reversals see each other's totals
+	var original types.Transaction
+	lockQuery := `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE id = $1 AND tenant_id = $2
+		FOR UPDATE
+	`
+	if err := scanTransaction(tx.QueryRowContext(ctx, lockQuery, transactionID, tenantID), &original); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("transaction not found")
+		}
+		return nil, fmt.Errorf("failed to lock transaction: %w", err)
+	}
+
+	existingTx, err := findByIdempotencyKey(ctx, tx, original.AccountID, req.IdempotencyKey)
+	if err == nil {
+		if existingTx.ReversesTransactionID == nil || *existingTx.ReversesTransactionID != original.ID {
+			return nil, fmt.Errorf("idempotency key already used")
+		}
+		tx.Commit()
+		s.logger.Info("Idempotent reversal request",
+			zap.String("transaction_id", existingTx.ID.String()),
+			zap.String("idempotency_key", req.IdempotencyKey),
+		)
+		return existingTx, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to check idempotency: %w", err)
+	}
+
+	reversalType, ok := original.Type.ReversalType()
+	if !ok || original.TransferID != nil || original.ReversesTransactionID != nil {
+		return nil, fmt.Errorf("transaction cannot be reversed")
+	}
+	if original.Status != types.TransactionStatusProcessed {
+		return nil, fmt.Errorf("transaction is not processed")
+	}
+
+	// Reversals still in flight count against the remaining amount
+	var reversedCents int64
+	reversedQuery := `
+		SELECT COALESCE(SUM(amount_cents), 0)
+		FROM transactions
+		WHERE reverses_transaction_id = $1 AND tenant_id = $2 AND status NOT IN ('FAILED', 'CANCELLED')
+	`
+	if err := tx.QueryRowContext(ctx, reversedQuery, original.ID, tenantID).Scan(&reversedCents); err != nil {
+		return nil, fmt.Errorf("failed to sum reversals: %w", err)
 	}
 
-	// Create transaction
+	remainingCents := original.AmountCents - reversedCents
+	if remainingCents <= 0 {
+		return nil, fmt.Errorf("transaction already fully reversed")
+	}
+	if req.AmountCents == 0 {
+		req.AmountCents = remainingCents
+	}
+	if req.AmountCents > remainingCents {
+		return nil, fmt.Errorf("reversal exceeds remaining amount")
+	}
+
+	// Refunds may still reach a suspended account, never a closed one
+	var accountStatus string
+	accountQuery := `SELECT status FROM accounts WHERE id = $1 AND tenant_id = $2`
+	if err := tx.QueryRowContext(ctx, accountQuery, original.AccountID, tenantID).Scan(&accountStatus); err != nil {
+		return nil, fmt.Errorf("failed to validate account: %w", err)
+	}
+	if accountStatus == string(types.AccountStatusClosed) {
+		return nil, fmt.Errorf("account is closed")
+	}
+
+	createReq := types.CreateTransactionRequest{
+		AccountID:      original.AccountID,
+		AmountCents:    req.AmountCents,
+		Currency:       original.Currency,
+		Type:           reversalType,
+		IdempotencyKey: req.IdempotencyKey,
+		Metadata:       req.Metadata,
+	}
+	transaction, err := s.insertTransaction(ctx, tx, createReq, &original.ID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reversal: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Reversal created with outbox event",
+		zap.String("transaction_id", transaction.ID.String()),
+		zap.String("reverses_transaction_id", original.ID.String()),
+		zap.Int64("amount_cents", transaction.AmountCents),
+	)
+
+	return transaction, nil
+}
+
+// insertTransaction inserts a transaction and its transaction.created outbox
+// event inside the caller's DB transaction. A transaction with execute_at in
+// the future is inserted as SCHEDULED without an event; the scheduler writes
+// the event when it falls due. A transaction that violates a limit is
+// inserted as FAILED without an event. The transaction belongs to the tenant
+// of ctx.
+func (s *TransactionService) insertTransaction(ctx context.Context, tx *sql.Tx, req types.CreateTransactionRequest, reversesTransactionID *uuid.UUID, violation *types.LimitViolation) (*types.Transaction, error) {
 	txID := uuid.New()
 	now := time.Now()
 
+	status := types.TransactionStatusPending
+	var executeAt *time.Time
+	if req.ExecuteAt != nil && req.ExecuteAt.After(now) {
+		status = types.TransactionStatusScheduled
+		executeAt = req.ExecuteAt
+	}
+	var failureReason *string
+	var failureCode *types.FailureCode
+	if violation != nil {
+		status = types.TransactionStatusFailed
+		failureReason = &violation.Reason
+		failureCode = &violation.Code
+	}
+
 	// Handle metadata - convert empty to nil for JSONB
 	var metadataValue interface{}
 	if len(req.Metadata) == 0 {
@@ -112,115 +443,235 @@ func (s *TransactionService) CreateTransaction(ctx context.Context, req types.Cr
 	}
 
 	insertTxQuery := `
-		INSERT INTO transactions (id, account_id, amount_cents, currency, type, status, 
-		                          idempotency_key, metadata, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-		RETURNING id, account_id, amount_cents, currency, type, status, idempotency_key,
-		          failure_reason, metadata, created_at, updated_at
+		INSERT INTO transactions (id, tenant_id, account_id, amount_cents, currency, type, status, idempotency_key,
+		                          metadata, authorization_id, reverses_transaction_id, fx_quote_id, execute_at,
+		                          failure_reason, failure_code, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		RETURNING ` + transactionColumns + `
 	`
 
 	var transaction types.Transaction
-	var metadataBytes []byte
-	err = tx.QueryRowContext(ctx, insertTxQuery,
-		txID, req.AccountID, req.AmountCents, req.Currency, req.Type,
-		types.TransactionStatusPending, req.IdempotencyKey, metadataValue, now, now,
-	).Scan(
-		&transaction.ID, &transaction.AccountID, &transaction.AmountCents,
-		&transaction.Currency, &transaction.Type, &transaction.Status,
-		&transaction.IdempotencyKey, &transaction.FailureReason,
-		&metadataBytes, &transaction.CreatedAt, &transaction.UpdatedAt,
-	)
-	if err == nil {
-		transaction.Metadata = metadataBytes
+	err := scanTransaction(tx.QueryRowContext(ctx, insertTxQuery,
+		txID, tenant.FromContext(ctx), req.AccountID, req.AmountCents, req.Currency, req.Type, status,
+		req.IdempotencyKey, metadataValue, req.AuthorizationID, reversesTransactionID, req.FXQuoteID, executeAt,
+		failureReason, failureCode, now, now,
+	), &transaction)
+	if err != nil {
+		return nil, err
+	}
+	var statusReason string
+	if failureReason != nil {
+		statusReason = *failureReason
+	}
+	if err := txstatus.RecordCreated(ctx, tx, transaction.ID, status, statusReason, txstatus.ActorAPI); err != nil {
+		return nil, err
 	}
 
-	if err != nil {
-		// Check if it's a unique constraint violation (race condition)
-		if err.Error() == "pq: duplicate key value violates unique constraint \"transactions_account_id_idempotency_key_key\"" {
-			// Another request created it - fetch and return
-			var raceMetadata []byte
-			err = tx.QueryRowContext(ctx, checkQuery, req.AccountID, req.IdempotencyKey).Scan(
-				&transaction.ID, &transaction.AccountID, &transaction.AmountCents,
-				&transaction.Currency, &transaction.Type, &transaction.Status,
-				&transaction.IdempotencyKey, &transaction.FailureReason,
-				&raceMetadata, &transaction.CreatedAt, &transaction.UpdatedAt,
-			)
-			if err == nil {
-				transaction.Metadata = raceMetadata
-				tx.Commit()
-				return &transaction, nil
-			}
-		}
-		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	if status == types.TransactionStatusScheduled || status == types.TransactionStatusFailed {
+		return &transaction, nil
 	}
 
 	// Create outbox event
-	payload := types.TransactionCreatedPayload{
-		TransactionID:  transaction.ID,
-		AccountID:      transaction.AccountID,
-		AmountCents:    transaction.AmountCents,
-		Currency:       transaction.Currency,
-		Type:           transaction.Type,
-		IdempotencyKey: transaction.IdempotencyKey,
-		Metadata:       transaction.Metadata,
+	if err := outbox.Write(ctx, tx, "transaction", transaction.ID, types.EventTypeTransactionCreated, transaction.CreatedPayload()); err != nil {
+		return nil, err
 	}
 
-	payloadBytes, err := json.Marshal(payload)
+	return &transaction, nil
+}
+
+// CancelScheduledTransaction cancels a transaction that is still waiting for
+// its execute_at. Once released to the worker it can no longer be cancelled.
+func (s *TransactionService) CancelScheduledTransaction(ctx context.Context, transactionID uuid.UUID, actor string) (*types.Transaction, error) {
+	return s.cancel(ctx, transactionID, types.TransactionStatusScheduled, actor)
+}
+
+// CancelTransaction cancels a PENDING transaction before the worker applies
+// it. An unpublished transaction.created event is superseded so it is never
+// published; the worker skips a published one once it sees the CANCELLED
+// status. Transfer legs and interest postings cannot be cancelled.
+func (s *TransactionService) CancelTransaction(ctx context.Context, transactionID uuid.UUID, actor string) (*types.Transaction, error) {
+	return s.cancel(ctx, transactionID, types.TransactionStatusPending, actor)
+}
+
+// cancel moves a transaction in the given status to CANCELLED
+func (s *TransactionService) cancel(ctx context.Context, transactionID uuid.UUID, expected types.TransactionStatus, actor string) (*types.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	tenantID := tenant.FromContext(ctx)
+	if err := tenant.Scope(ctx, tx, tenantID); err != nil {
+		return nil, err
 	}
 
-	outboxID := uuid.New()
-	outboxQuery := `
-		INSERT INTO outbox_events (id, aggregate_type, aggregate_id, event_type, payload, status, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	// The lock serializes with the scheduler, which releases SCHEDULED rows
+	// it can lock, and the worker, which locks the transaction before moving
+	// it to PROCESSING
+	var transaction types.Transaction
+	lockQuery := `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE id = $1 AND tenant_id = $2
+		FOR UPDATE
 	`
+	if err := scanTransaction(tx.QueryRowContext(ctx, lockQuery, transactionID, tenantID), &transaction); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("transaction not found")
+		}
+		return nil, fmt.Errorf("failed to lock transaction: %w", err)
+	}
+	if expected == types.TransactionStatusPending && (transaction.TransferID != nil || transaction.InterestPeriod != nil) {
+		return nil, fmt.Errorf("transaction cannot be cancelled")
+	}
+	if transaction.Status != expected {
+		if expected == types.TransactionStatusScheduled {
+			return nil, fmt.Errorf("transaction is not scheduled")
+		}
+		return nil, fmt.Errorf("transaction is not pending")
+	}
 
-	_, err = tx.ExecContext(ctx, outboxQuery,
-		outboxID, "transaction", transaction.ID, "transaction.created",
-		payloadBytes, "PENDING", now,
-	)
+	if _, err := txstatus.Transition(ctx, tx, transactionID, types.TransactionStatusCancelled, "cancelled through the API", actor); err != nil {
+		return nil, fmt.Errorf("failed to cancel transaction: %w", err)
+	}
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to create outbox event: %w", err)
+	// A SCHEDULED transaction has no event yet
+	var superseded int64
+	if expected == types.TransactionStatusPending {
+		supersedeQuery := `
+			UPDATE outbox_events
+			SET status = 'SUPERSEDED'
+			WHERE aggregate_type = 'transaction' AND aggregate_id = $1 AND event_type = $2 AND status = 'PENDING'
+		`
+		result, err := tx.ExecContext(ctx, supersedeQuery, transactionID, types.EventTypeTransactionCreated)
+		if err != nil {
+			return nil, fmt.Errorf("failed to supersede outbox event: %w", err)
+		}
+		superseded, _ = result.RowsAffected()
+	}
+
+	getQuery := `SELECT ` + transactionColumns + ` FROM transactions WHERE id = $1`
+	if err := scanTransaction(tx.QueryRowContext(ctx, getQuery, transactionID), &transaction); err != nil {
+		return nil, fmt.Errorf("failed to load transaction: %w", err)
 	}
 
-	// Commit transaction
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	s.logger.Info("Transaction created with outbox event",
-		zap.String("transaction_id", transaction.ID.String()),
-		zap.String("idempotency_key", req.IdempotencyKey),
-	)
+	if expected == types.TransactionStatusScheduled {
+		s.logger.Info("Scheduled transaction cancelled", zap.String("transaction_id", transactionID.String()))
+	} else {
+		s.logger.Info("Pending transaction cancelled",
+			zap.String("transaction_id", transactionID.String()),
+			zap.Bool("event_superseded", superseded > 0),
+		)
+	}
 
 	return &transaction, nil
 }
 
+// findByIdempotencyKey returns the transaction of the tenant of ctx with the
+// given (account_id, idempotency_key)
+func findByIdempotencyKey(ctx context.Context, q queryer, accountID uuid.UUID, idempotencyKey string) (*types.Transaction, error) {
+	checkQuery := `
+		SELECT ` + transactionColumns + `
+		FROM transactions
+		WHERE account_id = $1 AND idempotency_key = $2 AND tenant_id = $3
+		LIMIT 1
+	`
+	var transaction types.Transaction
+	if err := scanTransaction(q.QueryRowContext(ctx, checkQuery, accountID, idempotencyKey, tenant.FromContext(ctx)), &transaction); err != nil {
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// isIdempotencyConflict reports whether an insert lost a race on (account_id, idempotency_key)
+func isIdempotencyConflict(err error) bool {
+	return isUniqueViolation(err, "transactions_account_id_idempotency_key_key")
+}
+
+// isSerializationFailure reports whether err is a serialization failure of a
+// serializable DB transaction, which succeeds if the transaction is retried
+func isSerializationFailure(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "40001"
+}
+
+// isUniqueViolation reports whether err is a violation of the named unique
+// constraint
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
+
+// validateAuthorizationReference checks that a CAPTURE or VOID references an
+// open authorization on the same account. A VOID without an amount releases
+// the full authorized amount.
+func (s *TransactionService) validateAuthorizationReference(ctx context.Context, tx *sql.Tx, req *types.CreateTransactionRequest) error {
+	if req.AuthorizationID == nil {
+		return fmt.Errorf("authorization_id is required")
+	}
+
+	var authAccountID uuid.UUID
+	var authAmount int64
+	var authCurrency string
+	var authType types.TransactionType
+	var holdStatus *types.HoldStatus
+	authQuery := `
+		SELECT account_id, amount_cents, currency, type, hold_status
+		FROM transactions
+		WHERE id = $1 AND tenant_id = $2
+	`
+	err := tx.QueryRowContext(ctx, authQuery, req.AuthorizationID, tenant.FromContext(ctx)).Scan(
+		&authAccountID, &authAmount, &authCurrency, &authType, &holdStatus,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("authorization not found")
+		}
+		return fmt.Errorf("failed to validate authorization: %w", err)
+	}
+
+	if authType != types.TransactionTypeAuthorize || authAccountID != req.AccountID || authCurrency != req.Currency {
+		return fmt.Errorf("authorization not found")
+	}
+	if holdStatus != nil && *holdStatus != types.HoldStatusHeld {
+		return fmt.Errorf("authorization is not active")
+	}
+
+	if req.Type == types.TransactionTypeVoid && req.AmountCents == 0 {
+		req.AmountCents = authAmount
+	}
+	if req.AmountCents > authAmount {
+		return fmt.Errorf("amount exceeds authorized amount")
+	}
+
+	return nil
+}
+
 // GetTransaction retrieves a transaction by ID
 func (s *TransactionService) GetTransaction(ctx context.Context, transactionID uuid.UUID) (*types.Transaction, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	query := `
-		SELECT id, account_id, amount_cents, currency, type, status, idempotency_key,
-		       failure_reason, metadata, created_at, updated_at
+		SELECT ` + transactionColumns + `
 		FROM transactions
-		WHERE id = $1
+		WHERE id = $1 AND tenant_id = $2
 	`
 
 	var transaction types.Transaction
-	var metadataBytes []byte
-	err := s.db.QueryRowContext(ctx, query, transactionID).Scan(
-		&transaction.ID, &transaction.AccountID, &transaction.AmountCents,
-		&transaction.Currency, &transaction.Type, &transaction.Status,
-		&transaction.IdempotencyKey, &transaction.FailureReason,
-		&metadataBytes, &transaction.CreatedAt, &transaction.UpdatedAt,
-	)
-	if err == nil {
-		transaction.Metadata = metadataBytes
-	}
+	err := scanTransaction(s.db.QueryRowContext(ctx, query, transactionID, tenant.FromContext(ctx)), &transaction)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -233,57 +684,227 @@ func (s *TransactionService) GetTransaction(ctx context.Context, transactionID u
 	return &transaction, nil
 }
 
-// ListTransactions lists transactions with pagination
-func (s *TransactionService) ListTransactions(ctx context.Context, accountID *uuid.UUID, limit, offset int) ([]types.Transaction, error) {
+// GetTransactionHistory returns every status a transaction has taken, oldest first
+func (s *TransactionService) GetTransactionHistory(ctx context.Context, transactionID uuid.UUID) ([]types.TransactionStatusChange, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	var query string
-	var args []interface{}
-
-	if accountID != nil {
-		query = `
-			SELECT id, account_id, amount_cents, currency, type, status, idempotency_key,
-			       failure_reason, metadata, created_at, updated_at
-			FROM transactions
-			WHERE account_id = $1
-			ORDER BY created_at DESC
-			LIMIT $2 OFFSET $3
-		`
-		args = []interface{}{accountID, limit, offset}
-	} else {
-		query = `
-			SELECT id, account_id, amount_cents, currency, type, status, idempotency_key,
-			       failure_reason, metadata, created_at, updated_at
-			FROM transactions
-			ORDER BY created_at DESC
-			LIMIT $1 OFFSET $2
-		`
-		args = []interface{}{limit, offset}
+	var exists bool
+	existsQuery := `SELECT EXISTS (SELECT 1 FROM transactions WHERE id = $1 AND tenant_id = $2)`
+	if err := s.db.QueryRowContext(ctx, existsQuery, transactionID, tenant.FromContext(ctx)).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to check transaction: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("transaction not found")
+	}
+
+	return txstatus.History(ctx, s.db, transactionID)
+}
+
+// transactionSortOrders maps each sort option to its keyset order
+var transactionSortOrders = map[types.TransactionSort]keysetOrder{
+	types.TransactionSortCreatedAtDesc:   {sort: "-created_at", column: "created_at", cast: "timestamptz", desc: true},
+	types.TransactionSortCreatedAtAsc:    {sort: "created_at", column: "created_at", cast: "timestamptz"},
+	types.TransactionSortUpdatedAtDesc:   {sort: "-updated_at", column: "updated_at", cast: "timestamptz", desc: true},
+	types.TransactionSortUpdatedAtAsc:    {sort: "updated_at", column: "updated_at", cast: "timestamptz"},
+	types.TransactionSortAmountCentsDesc: {sort: "-amount_cents", column: "amount_cents", cast: "bigint", desc: true},
+	types.TransactionSortAmountCentsAsc:  {sort: "amount_cents", column: "amount_cents", cast: "bigint"},
+}
+
+// transactionPosition returns the cursor position of a transaction in a list
+// sorted by order
+func transactionPosition(order keysetOrder, transaction types.Transaction) *cursor {
+	position := &cursor{Sort: order.sort, ID: transaction.ID}
+	switch order.column {
+	case "updated_at":
+		position.Value = transaction.UpdatedAt.Format(time.RFC3339Nano)
+	case "amount_cents":
+		position.Value = strconv.FormatInt(transaction.AmountCents, 10)
+	default:
+		position.Value = transaction.CreatedAt.Format(time.RFC3339Nano)
+	}
+	return position
+}
+
+// ListTransactions lists the transactions matching the filter a page at a
+// time, newest first unless the filter sets another order. cursorToken is a
+// cursor of a previous page, which must have used the same filter; an empty
+// token starts at the first page.
+func (s *TransactionService) ListTransactions(ctx context.Context, filter types.TransactionFilter, cursorToken string, limit int) ([]types.Transaction, types.PageCursors, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	order, ok := transactionSortOrders[filter.Sort]
+	if !ok {
+		order = transactionSortOrders[types.TransactionSortCreatedAtDesc]
+	}
+	order = order.withFilter(filter)
+	after, err := decodeCursor(cursorToken, order)
+	if err != nil {
+		return nil, types.PageCursors{}, err
+	}
+
+	args := []interface{}{tenant.FromContext(ctx)}
+	conditions := []string{"tenant_id = $1"}
+	addCondition := func(format string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if filter.AccountID != nil {
+		addCondition("account_id = $%d", *filter.AccountID)
 	}
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
+		}
+		addCondition("status = ANY($%d)", pq.Array(statuses))
+	}
+	if len(filter.Types) > 0 {
+		txTypes := make([]string, len(filter.Types))
+		for i, txType := range filter.Types {
+			txTypes[i] = string(txType)
+		}
+		addCondition("type = ANY($%d)", pq.Array(txTypes))
+	}
+	if filter.Currency != "" {
+		addCondition("currency = $%d", filter.Currency)
+	}
+	if filter.CreatedFrom != nil {
+		addCondition("created_at >= $%d", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		addCondition("created_at < $%d", *filter.CreatedTo)
+	}
+	if filter.MinAmountCents != nil {
+		addCondition("amount_cents >= $%d", *filter.MinAmountCents)
+	}
+	if filter.MaxAmountCents != nil {
+		addCondition("amount_cents <= $%d", *filter.MaxAmountCents)
+	}
+
+	// Query values are strings; one that is also a JSON number or boolean
+	// matches either form, so order_id=123 finds {"order_id": 123} too
+	keys := make([]string, 0, len(filter.Metadata))
+	for key := range filter.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := filter.Metadata[key]
+		candidates := []interface{}{value}
+		var scalar interface{}
+		if err := json.Unmarshal([]byte(value), &scalar); err == nil {
+			switch scalar.(type) {
+			case float64, bool:
+				candidates = append(candidates, json.RawMessage(value))
+			}
+		}
+		var matches []string
+		for _, candidate := range candidates {
+			document, err := json.Marshal(map[string]interface{}{key: candidate})
+			if err != nil {
+				return nil, types.PageCursors{}, fmt.Errorf("failed to encode metadata filter: %w", err)
+			}
+			args = append(args, string(document))
+			matches = append(matches, fmt.Sprintf("metadata @> $%d::jsonb", len(args)))
+		}
+		conditions = append(conditions, "("+strings.Join(matches, " OR ")+")")
+	}
+
+	keysetCondition, args, orderBy := order.clauses(after, args)
+	if keysetCondition != "" {
+		conditions = append(conditions, keysetCondition)
+	}
+
+	// One row more than the page shows whether another page follows
+	args = append(args, limit+1)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM transactions
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d
+	`, transactionColumns, strings.Join(conditions, " AND "), orderBy, len(args))
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query transactions: %w", err)
+		return nil, types.PageCursors{}, fmt.Errorf("failed to query transactions: %w", err)
 	}
 	defer rows.Close()
 
 	var transactions []types.Transaction
 	for rows.Next() {
 		var tx types.Transaction
-		var metadataBytes []byte
-		err := rows.Scan(
-			&tx.ID, &tx.AccountID, &tx.AmountCents,
-			&tx.Currency, &tx.Type, &tx.Status,
-			&tx.IdempotencyKey, &tx.FailureReason,
-			&metadataBytes, &tx.CreatedAt, &tx.UpdatedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		if err := scanTransaction(rows, &tx); err != nil {
+			return nil, types.PageCursors{}, fmt.Errorf("failed to scan transaction: %w", err)
 		}
-		tx.Metadata = metadataBytes
 		transactions = append(transactions, tx)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, types.PageCursors{}, fmt.Errorf("failed to iterate transactions: %w", err)
+	}
+
+	hasMore := len(transactions) > limit
+	if hasMore {
+		transactions = transactions[:limit]
+	}
+	if after != nil && after.Backward {
+		for i, j := 0, len(transactions)-1; i < j; i, j = i+1, j-1 {
+			transactions[i], transactions[j] = transactions[j], transactions[i]
+		}
+	}
+
+	var first, last *cursor
+	if len(transactions) > 0 {
+		first = transactionPosition(order, transactions[0])
+		last = transactionPosition(order, transactions[len(transactions)-1])
+	}
+	return transactions, order.pageCursors(after, first, last, hasMore), nil
+}
 
-	return transactions, nil
+// transactionColumns is the column list read by scanTransaction
+const transactionColumns = `id, tenant_id, account_id, amount_cents, currency, type, status, idempotency_key,
+		       failure_reason, failure_code, metadata, transfer_id, authorization_id, hold_status, hold_expires_at,
+		       captured_amount_cents, reverses_transaction_id, fx_quote_id, fx_rate, settled_amount_cents,
+		       settled_currency, fx_spread_cents, execute_at, fee_cents, fee_transaction_id, fee_for_transaction_id,
+		       interest_period, review_reason, review_decision, reviewed_by, reviewed_at, created_at, updated_at`
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTransaction scans a row selected with transactionColumns
+func scanTransaction(row rowScanner, transaction *types.Transaction) error {
+	var metadataBytes []byte
+	err := row.Scan(
+		&transaction.ID, &transaction.TenantID, &transaction.AccountID, &transaction.AmountCents,
+		&transaction.Currency, &transaction.Type, &transaction.Status,
+		&transaction.IdempotencyKey, &transaction.FailureReason, &transaction.FailureCode,
+		&metadataBytes, &transaction.TransferID, &transaction.AuthorizationID, &transaction.HoldStatus,
+		&transaction.HoldExpiresAt, &transaction.CapturedAmountCents, &transaction.ReversesTransactionID,
+		&transaction.FXQuoteID, &transaction.FXRate, &transaction.SettledAmountCents,
+		&transaction.SettledCurrency, &transaction.FXSpreadCents, &transaction.ExecuteAt,
+		&transaction.FeeCents, &transaction.FeeTransactionID, &transaction.FeeForTransactionID, &transaction.InterestPeriod,
+		&transaction.ReviewReason, &transaction.ReviewDecision, &transaction.ReviewedBy, &transaction.ReviewedAt,
+		&transaction.CreatedAt, &transaction.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	transaction.Metadata = metadataBytes
+	transaction.AmountDecimal = currency.Format(transaction.AmountCents, transaction.Currency)
+	if transaction.FXRate != nil {
+		rate := normalizeRate(*transaction.FXRate)
+		transaction.FXRate = &rate
+	}
+	return nil
 }
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/currency"
+	"github.com/yash/transaction-system/shared/limits"
+	"github.com/yash/transaction-system/shared/outbox"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/txstatus"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// TransferService handles account-to-account transfers
+type TransferService struct {
+	db     *sql.DB
+	logger *zap.Logger
+}
+
+// NewTransferService creates a new transfer service
+func NewTransferService(db *sql.DB, logger *zap.Logger) *TransferService {
+	return &TransferService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateTransfer creates a transfer and its two transaction legs with an
+// idempotency check and a single transfer.created outbox event
+func (s *TransferService) CreateTransfer(ctx context.Context, req types.CreateTransferRequest) (*types.Transfer, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if !currency.Valid(req.Currency) {
+		return nil, fmt.Errorf("unsupported currency")
+	}
+
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	tenantID := tenant.FromContext(ctx)
+	if err := tenant.Scope(ctx, tx, tenantID); err != nil {
+		return nil, err
+	}
+
+	// Check idempotency: if same (from_account_id, idempotency_key) exists, return it
+	existing, err := s.findTransfer(ctx, tx, "t.from_account_id = $1 AND t.idempotency_key = $2", req.FromAccountID, req.IdempotencyKey)
+	if err == nil {
+		tx.Commit()
+		s.logger.Info("Idempotent transfer request",
+			zap.String("transfer_id", existing.ID.String()),
+			zap.String("idempotency_key", req.IdempotencyKey),
+		)
+		return existing, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to check idempotency: %w", err)
+	}
+
+	// Validate both accounts; both must belong to the caller's tenant
+	for _, accountID := range []uuid.UUID{req.FromAccountID, req.ToAccountID} {
+		var accountStatus, accountCurrency string
+		accountQuery := `SELECT status, currency FROM accounts WHERE id = $1 AND tenant_id = $2 AND system_code IS NULL`
+		err = tx.QueryRowContext(ctx, accountQuery, accountID, tenantID).Scan(&accountStatus, &accountCurrency)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("account not found")
+			}
+			return nil, fmt.Errorf("failed to validate account: %w", err)
+		}
+		if accountStatus != string(types.AccountStatusActive) {
+			return nil, fmt.Errorf("account is not active")
+		}
+		if accountCurrency != req.Currency {
+			return nil, fmt.Errorf("currency mismatch")
+		}
+	}
+
+	// A transfer that would exceed the source account's debit limits is
+	// recorded as FAILED and never reaches the worker
+	violation, err := limits.Check(ctx, tx, req.FromAccountID, req.AmountCents, time.Now(), limits.AcceptedStatuses, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+	status := types.TransactionStatusPending
+	var failureReason *string
+	var failureCode *types.FailureCode
+	var statusReason string
+	if violation != nil {
+		status = types.TransactionStatusFailed
+		failureReason = &violation.Reason
+		failureCode = &violation.Code
+		statusReason = violation.Reason
+	}
+
+	transferID := uuid.New()
+	now := time.Now()
+
+	var metadataValue interface{}
+	if len(req.Metadata) > 0 {
+		metadataValue = req.Metadata
+	}
+
+	insertTransferQuery := `
+		INSERT INTO transfers (id, from_account_id, to_account_id, amount_cents, currency, status,
+		                       idempotency_key, metadata, failure_reason, failure_code, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	_, err = tx.ExecContext(ctx, insertTransferQuery,
+		transferID, req.FromAccountID, req.ToAccountID, req.AmountCents, req.Currency,
+		status, req.IdempotencyKey, metadataValue, failureReason, failureCode, now, now,
+	)
+	if err != nil {
+		if isUniqueViolation(err, "transfers_from_account_id_idempotency_key_key") {
+			// Another request created it - fetch and return. The failed
+			// insert aborted tx, so the lookup runs outside it.
+			tx.Rollback()
+			existing, err = s.findTransfer(ctx, s.db, "t.from_account_id = $1 AND t.idempotency_key = $2", req.FromAccountID, req.IdempotencyKey)
+			if err == nil {
+				return existing, nil
+			}
+		}
+		return nil, fmt.Errorf("failed to create transfer: %w", err)
+	}
+
+	// Insert the two legs
+	debitTxID := uuid.New()
+	creditTxID := uuid.New()
+	insertLegQuery := `
+		INSERT INTO transactions (id, tenant_id, account_id, amount_cents, currency, type, status,
+		                          idempotency_key, metadata, transfer_id, failure_reason, failure_code,
+		                          created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`
+	legs := []struct {
+		id        uuid.UUID
+		accountID uuid.UUID
+		txType    types.TransactionType
+	}{
+		{debitTxID, req.FromAccountID, types.TransactionTypeDebit},
+		{creditTxID, req.ToAccountID, types.TransactionTypeCredit},
+	}
+	for _, leg := range legs {
+		legKey := fmt.Sprintf("transfer:%s:%s", transferID, leg.txType)
+		_, err = tx.ExecContext(ctx, insertLegQuery,
+			leg.id, tenantID, leg.accountID, req.AmountCents, req.Currency, leg.txType,
+			status, legKey, metadataValue, transferID, failureReason, failureCode, now, now,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create transfer leg: %w", err)
+		}
+		if err := txstatus.RecordCreated(ctx, tx, leg.id, status, statusReason, txstatus.ActorAPI); err != nil {
+			return nil, err
+		}
+	}
+
+	if violation != nil {
+		transfer, err := s.findTransfer(ctx, tx, "t.id = $1", transferID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load transfer: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		s.logger.Info("Transfer rejected by debit limit",
+			zap.String("transfer_id", transferID.String()),
+			zap.String("failure_code", string(violation.Code)),
+		)
+		return transfer, nil
+	}
+
+	// Create outbox event
+	payload := types.TransferCreatedPayload{
+		TransferID:          transferID,
+		FromAccountID:       req.FromAccountID,
+		ToAccountID:         req.ToAccountID,
+		DebitTransactionID:  debitTxID,
+		CreditTransactionID: creditTxID,
+		AmountCents:         req.AmountCents,
+		Currency:            req.Currency,
+		IdempotencyKey:      req.IdempotencyKey,
+		Metadata:            req.Metadata,
+	}
+	if err := outbox.Write(ctx, tx, "transfer", transferID, types.EventTypeTransferCreated, payload); err != nil {
+		return nil, err
+	}
+
+	transfer, err := s.findTransfer(ctx, tx, "t.id = $1", transferID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transfer: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	s.logger.Info("Transfer created with outbox event",
+		zap.String("transfer_id", transferID.String()),
+		zap.String("from_account_id", req.FromAccountID.String()),
+		zap.String("to_account_id", req.ToAccountID.String()),
+		zap.String("idempotency_key", req.IdempotencyKey),
+	)
+
+	return transfer, nil
+}
+
+// GetTransfer retrieves a transfer by ID
+func (s *TransferService) GetTransfer(ctx context.Context, transferID uuid.UUID) (*types.Transfer, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	transfer, err := s.findTransfer(ctx, s.db, "t.id = $1", transferID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("transfer not found")
+		}
+		s.logger.Error("Failed to get transfer", zap.Error(err), zap.String("transfer_id", transferID.String()))
+		return nil, fmt.Errorf("failed to get transfer: %w", err)
+	}
+
+	return transfer, nil
+}
+
+// findTransfer loads a transfer of the tenant of ctx together with the IDs
+// of its legs
+func (s *TransferService) findTransfer(ctx context.Context, q queryer, where string, args ...interface{}) (*types.Transfer, error) {
+	args = append(args, tenant.FromContext(ctx))
+	query := `
+		SELECT t.id, t.from_account_id, t.to_account_id, t.amount_cents, t.currency, t.status,
+		       t.idempotency_key, t.failure_reason, t.failure_code, t.metadata, t.created_at, t.updated_at,
+		       d.id, c.id
+		FROM transfers t
+		JOIN transactions d ON d.transfer_id = t.id AND d.type = 'DEBIT' AND d.tenant_id = $` + strconv.Itoa(len(args)) + `
+		JOIN transactions c ON c.transfer_id = t.id AND c.type = 'CREDIT'
+		WHERE ` + where + `
+		LIMIT 1
+	`
+
+	var transfer types.Transfer
+	var metadataBytes []byte
+	err := q.QueryRowContext(ctx, query, args...).Scan(
+		&transfer.ID, &transfer.FromAccountID, &transfer.ToAccountID, &transfer.AmountCents,
+		&transfer.Currency, &transfer.Status, &transfer.IdempotencyKey, &transfer.FailureReason, &transfer.FailureCode,
+		&metadataBytes, &transfer.CreatedAt, &transfer.UpdatedAt,
+		&transfer.DebitTransactionID, &transfer.CreditTransactionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	transfer.Metadata = metadataBytes
+	transfer.AmountDecimal = currency.Format(transfer.AmountCents, transfer.Currency)
+
+	return &transfer, nil
+}
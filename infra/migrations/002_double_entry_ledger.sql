@@ -0,0 +1,89 @@
+-- System accounts (e.g. the external settlement account) are regular account
+-- rows tagged with a system code. There is at most one per code and currency.
+ALTER TABLE accounts ADD COLUMN system_code TEXT;
+
+CREATE UNIQUE INDEX idx_accounts_system_code_currency ON accounts(system_code, currency)
+    WHERE system_code IS NOT NULL;
+
+-- Journal entries group the postings of a single ledger movement
+CREATE TABLE journal_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transaction_id UUID REFERENCES transactions(id),
+    description TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_journal_entries_transaction_id ON journal_entries(transaction_id);
+CREATE INDEX idx_journal_entries_created_at ON journal_entries(created_at);
+
+-- Postings are the individual debit and credit lines of a journal entry
+CREATE TABLE postings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    journal_entry_id UUID NOT NULL REFERENCES journal_entries(id),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    direction TEXT NOT NULL CHECK (direction IN ('DEBIT', 'CREDIT')),
+    amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+    currency TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_postings_journal_entry_id ON postings(journal_entry_id);
+CREATE INDEX idx_postings_account_id ON postings(account_id, created_at);
+
+-- Backstop for the balanced-postings invariant enforced by the worker:
+-- debits and credits of a journal entry must net to zero per currency.
+CREATE OR REPLACE FUNCTION check_journal_entry_balanced()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF EXISTS (
+        SELECT 1
+        FROM postings
+        WHERE journal_entry_id = NEW.journal_entry_id
+        GROUP BY currency
+        HAVING SUM(CASE WHEN direction = 'CREDIT' THEN amount_cents ELSE -amount_cents END) <> 0
+    ) THEN
+        RAISE EXCEPTION 'journal entry % is not balanced', NEW.journal_entry_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+
+CREATE CONSTRAINT TRIGGER postings_balanced AFTER INSERT ON postings
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION check_journal_entry_balanced();
+
+-- Account balances derived from postings
+CREATE VIEW account_ledger_balances AS
+SELECT a.id AS account_id,
+       a.currency,
+       COALESCE(SUM(CASE WHEN p.direction = 'CREDIT' THEN p.amount_cents ELSE -p.amount_cents END), 0) AS balance_cents
+FROM accounts a
+LEFT JOIN postings p ON p.account_id = a.id
+GROUP BY a.id, a.currency;
+
+-- Backfill: one settlement account per currency in use, and an opening
+-- journal entry for every account that already carries a balance.
+INSERT INTO accounts (currency, balance_cents, status, system_code)
+SELECT DISTINCT currency, 0, 'ACTIVE', 'SETTLEMENT'
+FROM accounts
+WHERE system_code IS NULL;
+
+WITH opening AS (
+    SELECT id AS account_id, currency, balance_cents, uuid_generate_v4() AS entry_id
+    FROM accounts
+    WHERE system_code IS NULL AND balance_cents <> 0
+), entries AS (
+    INSERT INTO journal_entries (id, description)
+    SELECT entry_id, 'Opening balance' FROM opening
+)
+INSERT INTO postings (journal_entry_id, account_id, direction, amount_cents, currency)
+SELECT o.entry_id, o.account_id,
+       CASE WHEN o.balance_cents > 0 THEN 'CREDIT' ELSE 'DEBIT' END,
+       ABS(o.balance_cents), o.currency
+FROM opening o
+UNION ALL
+SELECT o.entry_id, s.id,
+       CASE WHEN o.balance_cents > 0 THEN 'DEBIT' ELSE 'CREDIT' END,
+       ABS(o.balance_cents), o.currency
+FROM opening o
+JOIN accounts s ON s.system_code = 'SETTLEMENT' AND s.currency = o.currency;
@@ -0,0 +1,39 @@
+-- Transfers move funds between two accounts atomically
+CREATE TABLE transfers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    from_account_id UUID NOT NULL REFERENCES accounts(id),
+    to_account_id UUID NOT NULL REFERENCES accounts(id),
+    amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+    currency TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'PROCESSING', 'PROCESSED', 'FAILED')),
+    idempotency_key TEXT NOT NULL,
+    failure_reason TEXT,
+    metadata JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE(from_account_id, idempotency_key),
+    CHECK (from_account_id <> to_account_id)
+);
+
+CREATE INDEX idx_transfers_from_account_id ON transfers(from_account_id);
+CREATE INDEX idx_transfers_to_account_id ON transfers(to_account_id);
+CREATE INDEX idx_transfers_status ON transfers(status);
+
+CREATE TRIGGER update_transfers_updated_at BEFORE UPDATE ON transfers
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Each transfer is backed by a DEBIT and a CREDIT transaction leg
+ALTER TABLE transactions ADD COLUMN transfer_id UUID REFERENCES transfers(id);
+
+CREATE INDEX idx_transactions_transfer_id ON transactions(transfer_id);
+
+-- A transfer posts both legs in one journal entry, so each posting links to
+-- the transaction leg of its own account
+ALTER TABLE postings ADD COLUMN transaction_id UUID REFERENCES transactions(id);
+
+UPDATE postings p
+SET transaction_id = je.transaction_id
+FROM journal_entries je
+WHERE je.id = p.journal_entry_id;
+
+CREATE INDEX idx_postings_transaction_id ON postings(transaction_id);
@@ -0,0 +1,18 @@
+-- Funds reserved by open authorization holds. The available balance is
+-- balance_cents - held_cents.
+ALTER TABLE accounts ADD COLUMN held_cents BIGINT NOT NULL DEFAULT 0 CHECK (held_cents >= 0);
+
+-- Two-phase payments: AUTHORIZE places a hold, CAPTURE or VOID settles or releases it
+ALTER TABLE transactions DROP CONSTRAINT transactions_type_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_type_check
+    CHECK (type IN ('DEBIT', 'CREDIT', 'AUTHORIZE', 'CAPTURE', 'VOID'));
+
+ALTER TABLE transactions ADD COLUMN authorization_id UUID REFERENCES transactions(id);
+ALTER TABLE transactions ADD COLUMN hold_status TEXT
+    CHECK (hold_status IN ('HELD', 'CAPTURED', 'VOIDED', 'EXPIRED'));
+ALTER TABLE transactions ADD COLUMN hold_expires_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE transactions ADD COLUMN captured_amount_cents BIGINT;
+
+CREATE INDEX idx_transactions_authorization_id ON transactions(authorization_id);
+CREATE INDEX idx_transactions_hold_expires_at ON transactions(hold_expires_at)
+    WHERE hold_status = 'HELD';
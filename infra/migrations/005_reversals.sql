@@ -0,0 +1,7 @@
+-- A reversal is an opposite-typed transaction linked to the transaction it
+-- undoes. Partial reversals are allowed as long as their total never exceeds
+-- the original amount.
+ALTER TABLE transactions ADD COLUMN reverses_transaction_id UUID REFERENCES transactions(id);
+
+CREATE INDEX idx_transactions_reverses_transaction_id ON transactions(reverses_transaction_id)
+    WHERE reverses_transaction_id IS NOT NULL;
@@ -0,0 +1,9 @@
+-- Debits may take the balance down to -overdraft_limit_cents. CREDIT_LINE
+-- accounts are lending accounts whose normal balance is negative; their
+-- overdraft limit is the credit limit.
+ALTER TABLE accounts ADD COLUMN kind TEXT NOT NULL DEFAULT 'STANDARD'
+    CHECK (kind IN ('STANDARD', 'CREDIT_LINE'));
+ALTER TABLE accounts ADD COLUMN overdraft_limit_cents BIGINT NOT NULL DEFAULT 0
+    CHECK (overdraft_limit_cents >= 0);
+
+CREATE INDEX idx_audit_logs_entity_created_at ON audit_logs(entity_type, entity_id, created_at DESC);
@@ -0,0 +1,34 @@
+-- FX rates. rate is the mid-market number of quote_currency units per
+-- base_currency unit; spread_bps is charged on top of it. The latest rate
+-- with effective_at <= NOW() is current.
+CREATE TABLE fx_rates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    base_currency TEXT NOT NULL,
+    quote_currency TEXT NOT NULL,
+    rate NUMERIC(24, 12) NOT NULL CHECK (rate > 0),
+    spread_bps INTEGER NOT NULL DEFAULT 0 CHECK (spread_bps >= 0 AND spread_bps < 10000),
+    effective_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (base_currency <> quote_currency)
+);
+
+CREATE INDEX idx_fx_rates_pair_effective_at ON fx_rates(base_currency, quote_currency, effective_at DESC);
+
+-- A quote locks a rate for a short time so the client knows the conversion in advance
+CREATE TABLE fx_quotes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    base_currency TEXT NOT NULL,
+    quote_currency TEXT NOT NULL,
+    rate NUMERIC(24, 12) NOT NULL CHECK (rate > 0),
+    spread_bps INTEGER NOT NULL CHECK (spread_bps >= 0 AND spread_bps < 10000),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- A transaction whose currency differs from its account's is converted by the
+-- worker; the mid rate used and the amount settled on the account are recorded
+ALTER TABLE transactions ADD COLUMN fx_quote_id UUID REFERENCES fx_quotes(id);
+ALTER TABLE transactions ADD COLUMN fx_rate NUMERIC(24, 12);
+ALTER TABLE transactions ADD COLUMN settled_amount_cents BIGINT;
+ALTER TABLE transactions ADD COLUMN settled_currency TEXT;
+ALTER TABLE transactions ADD COLUMN fx_spread_cents BIGINT;
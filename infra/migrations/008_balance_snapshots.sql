@@ -0,0 +1,12 @@
+-- End-of-day ledger balances. A snapshot holds the sum of the account's
+-- postings created before snapshot_at, so a point-in-time balance only has
+-- to add the postings after the latest snapshot.
+CREATE TABLE account_balance_snapshots (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    snapshot_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    balance_cents BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, snapshot_at)
+);
+
+CREATE INDEX idx_account_balance_snapshots_snapshot_at ON account_balance_snapshots(snapshot_at);
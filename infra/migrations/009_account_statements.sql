@@ -0,0 +1,13 @@
+-- Pre-generated month-end statements. period is YYYY-MM; statement holds the
+-- rendered JSON so a closed period is served exactly as it was issued.
+CREATE TABLE account_statements (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    period TEXT NOT NULL,
+    opening_balance_cents BIGINT NOT NULL,
+    closing_balance_cents BIGINT NOT NULL,
+    statement JSONB NOT NULL,
+    generated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, period)
+);
+
+CREATE INDEX idx_account_statements_period ON account_statements(period);
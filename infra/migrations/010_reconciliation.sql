@@ -0,0 +1,33 @@
+-- Reconciliation runs compare each account's stored balance with the signed
+-- sum of its PROCESSED transactions and with its ledger postings
+CREATE TABLE reconciliation_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    status TEXT NOT NULL DEFAULT 'RUNNING' CHECK (status IN ('RUNNING', 'COMPLETED', 'FAILED')),
+    auto_repair BOOLEAN NOT NULL DEFAULT FALSE,
+    accounts_scanned INTEGER NOT NULL DEFAULT 0,
+    drift_accounts INTEGER NOT NULL DEFAULT 0,
+    repaired_accounts INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_reconciliation_runs_started_at ON reconciliation_runs(started_at);
+
+-- One finding per drifted account per run. drift_cents is the stored balance
+-- minus the expected balance.
+CREATE TABLE reconciliation_findings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    run_id UUID NOT NULL REFERENCES reconciliation_runs(id),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    currency TEXT NOT NULL,
+    balance_cents BIGINT NOT NULL,
+    expected_balance_cents BIGINT NOT NULL,
+    ledger_balance_cents BIGINT NOT NULL,
+    drift_cents BIGINT NOT NULL,
+    repaired BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_reconciliation_findings_run_id ON reconciliation_findings(run_id);
+CREATE INDEX idx_reconciliation_findings_account_id ON reconciliation_findings(account_id);
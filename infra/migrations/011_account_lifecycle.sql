@@ -0,0 +1,17 @@
+-- CLOSED is terminal: it requires a zero balance and blocks new transactions
+ALTER TABLE accounts DROP CONSTRAINT accounts_status_check;
+ALTER TABLE accounts ADD CONSTRAINT accounts_status_check
+    CHECK (status IN ('ACTIVE', 'SUSPENDED', 'CLOSED'));
+
+-- Deleting an account must never wipe its history; accounts are closed instead
+ALTER TABLE transactions DROP CONSTRAINT transactions_account_id_fkey;
+ALTER TABLE transactions ADD CONSTRAINT transactions_account_id_fkey
+    FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE RESTRICT;
+
+ALTER TABLE account_balance_snapshots DROP CONSTRAINT account_balance_snapshots_account_id_fkey;
+ALTER TABLE account_balance_snapshots ADD CONSTRAINT account_balance_snapshots_account_id_fkey
+    FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE RESTRICT;
+
+ALTER TABLE account_statements DROP CONSTRAINT account_statements_account_id_fkey;
+ALTER TABLE account_statements ADD CONSTRAINT account_statements_account_id_fkey
+    FOREIGN KEY (account_id) REFERENCES accounts(id) ON DELETE RESTRICT;
@@ -0,0 +1,11 @@
+-- Future-dated transactions wait in SCHEDULED without an outbox event until
+-- execute_at, when the scheduler releases them as PENDING. A scheduled
+-- transaction may be CANCELLED before it is released.
+ALTER TABLE transactions DROP CONSTRAINT transactions_status_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_status_check
+    CHECK (status IN ('SCHEDULED', 'PENDING', 'PROCESSING', 'PROCESSED', 'FAILED', 'CANCELLED'));
+
+ALTER TABLE transactions ADD COLUMN execute_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_transactions_scheduled ON transactions(execute_at)
+    WHERE status = 'SCHEDULED';
@@ -0,0 +1,28 @@
+-- Recurring schedules create a transaction from their template at every
+-- occurrence of their rule. Each occurrence uses the idempotency key
+-- schedule:<id>:<occurrence time>, so a rerun never creates it twice.
+CREATE TABLE recurring_schedules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+    currency TEXT NOT NULL,
+    type TEXT NOT NULL CHECK (type IN ('DEBIT', 'CREDIT')),
+    metadata JSONB,
+    rule JSONB NOT NULL,
+    start_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    next_run_at TIMESTAMP WITH TIME ZONE,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    occurrence_count INTEGER NOT NULL DEFAULT 0,
+    last_transaction_id UUID REFERENCES transactions(id),
+    last_error TEXT,
+    status TEXT NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'PAUSED', 'COMPLETED', 'CANCELLED')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_recurring_schedules_account_id ON recurring_schedules(account_id);
+CREATE INDEX idx_recurring_schedules_due ON recurring_schedules(next_run_at)
+    WHERE status = 'ACTIVE';
+
+CREATE TRIGGER update_recurring_schedules_updated_at BEFORE UPDATE ON recurring_schedules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,28 @@
+-- Debit limits are set per account tier and may be overridden per account,
+-- limit by limit. A NULL limit is unlimited.
+ALTER TABLE accounts ADD COLUMN tier TEXT NOT NULL DEFAULT 'STANDARD';
+
+CREATE TABLE transaction_limits (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    account_id UUID UNIQUE REFERENCES accounts(id) ON DELETE RESTRICT,
+    tier TEXT UNIQUE,
+    max_single_debit_cents BIGINT CHECK (max_single_debit_cents >= 0),
+    max_daily_debit_cents BIGINT CHECK (max_daily_debit_cents >= 0),
+    max_monthly_debit_cents BIGINT CHECK (max_monthly_debit_cents >= 0),
+    max_daily_debit_count INTEGER CHECK (max_daily_debit_count >= 0),
+    max_monthly_debit_count INTEGER CHECK (max_monthly_debit_count >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK ((account_id IS NULL) <> (tier IS NULL))
+);
+
+CREATE TRIGGER update_transaction_limits_updated_at BEFORE UPDATE ON transaction_limits
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Machine-readable reason a transaction or transfer failed, e.g. LIMIT_DAILY_AMOUNT
+ALTER TABLE transactions ADD COLUMN failure_code TEXT;
+ALTER TABLE transfers ADD COLUMN failure_code TEXT;
+
+-- Debit totals per account and window
+CREATE INDEX idx_transactions_account_debits ON transactions(account_id, (COALESCE(execute_at, created_at)))
+    WHERE type = 'DEBIT';
@@ -0,0 +1,32 @@
+-- Declarative fraud and risk rules evaluated by the worker before a
+-- transaction is applied. Rules run in priority order (lowest first); the
+-- first rule whose conditions all match decides. RISK_RULES_FILE replaces
+-- this table with a JSON file of the same shape.
+CREATE TABLE risk_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT NOT NULL UNIQUE,
+    description TEXT,
+    action TEXT NOT NULL CHECK (action IN ('ALLOW', 'DENY', 'REVIEW')),
+    priority INTEGER NOT NULL DEFAULT 100,
+    conditions JSONB NOT NULL DEFAULT '[]',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_risk_rules_updated_at BEFORE UPDATE ON risk_rules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- A transaction a rule sends to review waits in IN_REVIEW until it is
+-- approved (back to PENDING with a new event) or rejected (FAILED)
+ALTER TABLE transactions DROP CONSTRAINT transactions_status_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_status_check
+    CHECK (status IN ('SCHEDULED', 'PENDING', 'IN_REVIEW', 'PROCESSING', 'PROCESSED', 'FAILED', 'CANCELLED'));
+
+ALTER TABLE transactions ADD COLUMN review_reason TEXT;
+ALTER TABLE transactions ADD COLUMN review_decision TEXT CHECK (review_decision IN ('APPROVED', 'REJECTED'));
+ALTER TABLE transactions ADD COLUMN reviewed_by TEXT;
+ALTER TABLE transactions ADD COLUMN reviewed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_transactions_in_review ON transactions(created_at)
+    WHERE status = 'IN_REVIEW';
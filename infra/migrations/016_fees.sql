@@ -0,0 +1,30 @@
+-- Fee schedules price a transaction type in an account currency, or in any
+-- currency ('ANY') when no schedule for the currency exists. The fee is
+-- flat_cents plus rate_bps basis points of the amount, clamped to
+-- [min_cents, max_cents]. Tiers replace flat_cents and rate_bps once the
+-- account's monthly volume of the type reaches min_monthly_volume_cents.
+CREATE TABLE fee_schedules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    transaction_type TEXT NOT NULL CHECK (transaction_type IN ('DEBIT', 'CREDIT', 'CAPTURE')),
+    currency TEXT NOT NULL DEFAULT 'ANY',
+    flat_cents BIGINT NOT NULL DEFAULT 0 CHECK (flat_cents >= 0),
+    rate_bps INTEGER NOT NULL DEFAULT 0 CHECK (rate_bps BETWEEN 0 AND 10000),
+    min_cents BIGINT CHECK (min_cents >= 0),
+    max_cents BIGINT CHECK (max_cents >= 0),
+    tiers JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE(transaction_type, currency)
+);
+
+CREATE TRIGGER update_fee_schedules_updated_at BEFORE UPDATE ON fee_schedules
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- A fee is charged as its own PROCESSED DEBIT linked both ways to the
+-- transaction it was charged for
+ALTER TABLE transactions ADD COLUMN fee_cents BIGINT;
+ALTER TABLE transactions ADD COLUMN fee_transaction_id UUID REFERENCES transactions(id);
+ALTER TABLE transactions ADD COLUMN fee_for_transaction_id UUID REFERENCES transactions(id);
+
+CREATE INDEX idx_transactions_fee_for ON transactions(fee_for_transaction_id)
+    WHERE fee_for_transaction_id IS NOT NULL;
@@ -0,0 +1,31 @@
+-- Interest-bearing accounts have an annual rate (0.0425 = 4.25%) and a day
+-- count convention. Interest accrues daily from interest_start_date on the
+-- end-of-day balance: a positive balance of a STANDARD account earns it, a
+-- drawn CREDIT_LINE is charged it.
+ALTER TABLE accounts ADD COLUMN interest_rate NUMERIC(12, 8) CHECK (interest_rate >= 0);
+ALTER TABLE accounts ADD COLUMN interest_day_count TEXT CHECK (interest_day_count IN ('ACT/365', '30/360'));
+ALTER TABLE accounts ADD COLUMN interest_start_date DATE;
+ALTER TABLE accounts ADD CONSTRAINT accounts_interest_check
+    CHECK ((interest_rate IS NULL) = (interest_day_count IS NULL));
+
+-- One row per account and day keeps the exact fractional-cent accrual and
+-- makes reruns no-ops. At month end the month's accruals are rounded to the
+-- cent and posted as one transaction.
+CREATE TABLE interest_accruals (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    accrual_date DATE NOT NULL,
+    balance_cents BIGINT NOT NULL,
+    annual_rate NUMERIC(12, 8) NOT NULL,
+    day_count TEXT NOT NULL,
+    amount_cents NUMERIC(24, 12) NOT NULL CHECK (amount_cents >= 0),
+    posted_at TIMESTAMP WITH TIME ZONE,
+    transaction_id UUID REFERENCES transactions(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, accrual_date)
+);
+
+CREATE INDEX idx_interest_accruals_unposted ON interest_accruals(accrual_date)
+    WHERE posted_at IS NULL;
+
+-- The month (YYYY-MM) an interest posting covers
+ALTER TABLE transactions ADD COLUMN interest_period TEXT;
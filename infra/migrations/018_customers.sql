@@ -0,0 +1,30 @@
+-- Customers own accounts. An account has one primary owner (customer_id) and
+-- any number of joint owners; every owner, the primary included, has a row in
+-- account_owners. Accounts created before customers existed have no owner.
+CREATE TABLE customers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    full_name TEXT NOT NULL,
+    email TEXT,
+    phone TEXT,
+    date_of_birth DATE,
+    address JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_customers_updated_at BEFORE UPDATE ON customers
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE accounts ADD COLUMN customer_id UUID REFERENCES customers(id);
+
+CREATE INDEX idx_accounts_customer_id ON accounts(customer_id) WHERE customer_id IS NOT NULL;
+
+CREATE TABLE account_owners (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE RESTRICT,
+    customer_id UUID NOT NULL REFERENCES customers(id),
+    role TEXT NOT NULL CHECK (role IN ('PRIMARY', 'JOINT')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, customer_id)
+);
+
+CREATE INDEX idx_account_owners_customer_id ON account_owners(customer_id);
@@ -0,0 +1,57 @@
+-- Tenants isolate business units sharing one deployment. The tenant of a
+-- request comes from its API key; rows created before tenants existed belong
+-- to the 'default' tenant.
+--
+-- The API scopes each DB transaction with set_config('app.tenant_id', ...,
+-- true). Row-level security then hides other tenants' rows and rejects writes
+-- into them. Sessions that never set app.tenant_id (the worker, publisher and
+-- background jobs) see every tenant and scope their queries explicitly.
+-- Superusers and roles with BYPASSRLS skip the policies, so the API should
+-- connect as an ordinary role for them to apply.
+CREATE FUNCTION app_tenant_id() RETURNS TEXT AS $$
+    SELECT NULLIF(current_setting('app.tenant_id', true), '')
+$$ LANGUAGE sql STABLE;
+
+ALTER TABLE accounts ADD COLUMN tenant_id TEXT NOT NULL DEFAULT COALESCE(app_tenant_id(), 'default');
+ALTER TABLE transactions ADD COLUMN tenant_id TEXT NOT NULL DEFAULT COALESCE(app_tenant_id(), 'default');
+ALTER TABLE outbox_events ADD COLUMN tenant_id TEXT NOT NULL DEFAULT COALESCE(app_tenant_id(), 'default');
+ALTER TABLE audit_logs ADD COLUMN tenant_id TEXT NOT NULL DEFAULT COALESCE(app_tenant_id(), 'default');
+ALTER TABLE customers ADD COLUMN tenant_id TEXT NOT NULL DEFAULT COALESCE(app_tenant_id(), 'default');
+
+-- Transactions take the tenant of their account
+UPDATE transactions t SET tenant_id = a.tenant_id FROM accounts a WHERE a.id = t.account_id;
+
+CREATE INDEX idx_accounts_tenant_id ON accounts(tenant_id);
+CREATE INDEX idx_transactions_tenant_created_at ON transactions(tenant_id, created_at DESC);
+CREATE INDEX idx_customers_tenant_id ON customers(tenant_id);
+CREATE INDEX idx_audit_logs_tenant_entity ON audit_logs(tenant_id, entity_type, entity_id, created_at DESC);
+
+ALTER TABLE accounts ENABLE ROW LEVEL SECURITY;
+ALTER TABLE accounts FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON accounts
+    USING (app_tenant_id() IS NULL OR tenant_id = app_tenant_id())
+    WITH CHECK (app_tenant_id() IS NULL OR tenant_id = app_tenant_id());
+
+ALTER TABLE transactions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE transactions FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON transactions
+    USING (app_tenant_id() IS NULL OR tenant_id = app_tenant_id())
+    WITH CHECK (app_tenant_id() IS NULL OR tenant_id = app_tenant_id());
+
+ALTER TABLE outbox_events ENABLE ROW LEVEL SECURITY;
+ALTER TABLE outbox_events FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON outbox_events
+    USING (app_tenant_id() IS NULL OR tenant_id = app_tenant_id())
+    WITH CHECK (app_tenant_id() IS NULL OR tenant_id = app_tenant_id());
+
+ALTER TABLE audit_logs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE audit_logs FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON audit_logs
+    USING (app_tenant_id() IS NULL OR tenant_id = app_tenant_id())
+    WITH CHECK (app_tenant_id() IS NULL OR tenant_id = app_tenant_id());
+
+ALTER TABLE customers ENABLE ROW LEVEL SECURITY;
+ALTER TABLE customers FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON customers
+    USING (app_tenant_id() IS NULL OR tenant_id = app_tenant_id())
+    WITH CHECK (app_tenant_id() IS NULL OR tenant_id = app_tenant_id());
@@ -0,0 +1,6 @@
+-- A PENDING transaction may be cancelled before the worker picks it up. Its
+-- transaction.created event is then SUPERSEDED so the publisher skips it; an
+-- event published already is skipped by the worker instead.
+ALTER TABLE outbox_events DROP CONSTRAINT outbox_events_status_check;
+ALTER TABLE outbox_events ADD CONSTRAINT outbox_events_status_check
+    CHECK (status IN ('PENDING', 'PUBLISHED', 'SUPERSEDED'));
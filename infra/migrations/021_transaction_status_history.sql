@@ -0,0 +1,26 @@
+-- Every status a transaction takes is recorded, starting with the status it
+-- was created with (from_status NULL). Status changes go through the state
+-- machine in shared/types, which rejects moves such as FAILED to PROCESSED.
+CREATE TABLE transaction_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id TEXT NOT NULL DEFAULT COALESCE(app_tenant_id(), 'default'),
+    transaction_id UUID NOT NULL REFERENCES transactions(id) ON DELETE RESTRICT,
+    from_status TEXT,
+    to_status TEXT NOT NULL,
+    reason TEXT,
+    actor TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_transaction_status_history_transaction ON transaction_status_history(transaction_id, created_at);
+
+-- Transactions created before the history existed start at their current status
+INSERT INTO transaction_status_history (tenant_id, transaction_id, from_status, to_status, reason, actor, created_at)
+SELECT tenant_id, id, NULL, status, 'recorded when status history was introduced', 'migration', updated_at
+FROM transactions;
+
+ALTER TABLE transaction_status_history ENABLE ROW LEVEL SECURITY;
+ALTER TABLE transaction_status_history FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON transaction_status_history
+    USING (app_tenant_id() IS NULL OR tenant_id = app_tenant_id())
+    WITH CHECK (app_tenant_id() IS NULL OR tenant_id = app_tenant_id());
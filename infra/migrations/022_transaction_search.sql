@@ -0,0 +1,11 @@
+-- Indexes behind the filters and sort orders of GET /v1/transactions. The
+-- list is always scoped to a tenant, so the B-tree indexes lead with
+-- tenant_id. Type and currency are too unselective to index on their own;
+-- they are applied to rows found through the others.
+CREATE INDEX idx_transactions_tenant_account_created_at ON transactions(tenant_id, account_id, created_at DESC);
+CREATE INDEX idx_transactions_tenant_status_created_at ON transactions(tenant_id, status, created_at DESC);
+CREATE INDEX idx_transactions_tenant_updated_at ON transactions(tenant_id, updated_at DESC);
+CREATE INDEX idx_transactions_tenant_amount_cents ON transactions(tenant_id, amount_cents);
+
+-- metadata[key]=value filters are containment (@>) queries
+CREATE INDEX idx_transactions_metadata ON transactions USING GIN (metadata jsonb_path_ops);
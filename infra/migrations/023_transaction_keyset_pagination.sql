@@ -0,0 +1,14 @@
+-- GET /v1/transactions pages by (sort column, id) instead of OFFSET, so the
+-- list indexes end with id. A B-tree is scanned in either direction, so one
+-- index serves both ascending and descending pages.
+DROP INDEX idx_transactions_tenant_created_at;
+DROP INDEX idx_transactions_tenant_account_created_at;
+DROP INDEX idx_transactions_tenant_status_created_at;
+DROP INDEX idx_transactions_tenant_updated_at;
+DROP INDEX idx_transactions_tenant_amount_cents;
+
+CREATE INDEX idx_transactions_tenant_created_at ON transactions(tenant_id, created_at, id);
+CREATE INDEX idx_transactions_tenant_account_created_at ON transactions(tenant_id, account_id, created_at, id);
+CREATE INDEX idx_transactions_tenant_status_created_at ON transactions(tenant_id, status, created_at, id);
+CREATE INDEX idx_transactions_tenant_updated_at ON transactions(tenant_id, updated_at, id);
+CREATE INDEX idx_transactions_tenant_amount_cents ON transactions(tenant_id, amount_cents, id);
@@ -0,0 +1,4 @@
+-- Keyset indexes behind the sort orders of GET /v1/accounts, which only lists
+-- customer accounts (system accounts of the ledger have a system_code)
+CREATE INDEX idx_accounts_tenant_created_at ON accounts(tenant_id, created_at, id) WHERE system_code IS NULL;
+CREATE INDEX idx_accounts_tenant_balance_cents ON accounts(tenant_id, balance_cents, id) WHERE system_code IS NULL;
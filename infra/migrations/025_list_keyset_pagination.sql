@@ -0,0 +1,12 @@
+-- Audit trails, recurring schedules and reconciliation runs page by
+-- (created_at or started_at, id) instead of OFFSET, so their list indexes end
+-- with id. Interest accruals page by their primary key.
+DROP INDEX idx_audit_logs_tenant_entity;
+CREATE INDEX idx_audit_logs_tenant_entity ON audit_logs(tenant_id, entity_type, entity_id, created_at, id);
+
+DROP INDEX idx_recurring_schedules_account_id;
+CREATE INDEX idx_recurring_schedules_account_created_at ON recurring_schedules(account_id, created_at, id);
+CREATE INDEX idx_recurring_schedules_created_at ON recurring_schedules(created_at, id);
+
+DROP INDEX idx_reconciliation_runs_started_at;
+CREATE INDEX idx_reconciliation_runs_started_at ON reconciliation_runs(started_at, id);
@@ -0,0 +1,73 @@
+-- Currency codes are upper-case ISO 4217 codes from the registry in
+-- shared/currency. Rows written before the API validated codes may hold lower
+-- case or padded codes, which currency.Exponent would silently treat as
+-- two-decimal currencies. Normalize them, then refuse to finish while any code
+-- is still outside the registry so it is fixed by hand rather than guessed.
+--
+-- Runs as one transaction: if a normalized code collides with an existing row
+-- (two system accounts or fee schedules differing only in case) or an unknown
+-- code remains, nothing is changed.
+BEGIN;
+
+UPDATE accounts SET currency = UPPER(TRIM(currency)) WHERE currency <> UPPER(TRIM(currency));
+UPDATE transactions SET currency = UPPER(TRIM(currency)) WHERE currency <> UPPER(TRIM(currency));
+UPDATE transactions SET settled_currency = UPPER(TRIM(settled_currency)) WHERE settled_currency <> UPPER(TRIM(settled_currency));
+UPDATE transfers SET currency = UPPER(TRIM(currency)) WHERE currency <> UPPER(TRIM(currency));
+UPDATE postings SET currency = UPPER(TRIM(currency)) WHERE currency <> UPPER(TRIM(currency));
+UPDATE fx_rates SET base_currency = UPPER(TRIM(base_currency)), quote_currency = UPPER(TRIM(quote_currency))
+    WHERE base_currency <> UPPER(TRIM(base_currency)) OR quote_currency <> UPPER(TRIM(quote_currency));
+UPDATE fx_quotes SET base_currency = UPPER(TRIM(base_currency)), quote_currency = UPPER(TRIM(quote_currency))
+    WHERE base_currency <> UPPER(TRIM(base_currency)) OR quote_currency <> UPPER(TRIM(quote_currency));
+UPDATE fee_schedules SET currency = UPPER(TRIM(currency)) WHERE currency <> UPPER(TRIM(currency));
+UPDATE recurring_schedules SET currency = UPPER(TRIM(currency)) WHERE currency <> UPPER(TRIM(currency));
+UPDATE reconciliation_findings SET currency = UPPER(TRIM(currency)) WHERE currency <> UPPER(TRIM(currency));
+
+-- Keep in sync with the registry in shared/currency/currency.go
+DO $$
+DECLARE
+    registry TEXT[] := ARRAY[
+        'AED', 'AFN', 'ALL', 'AMD', 'ANG', 'AOA', 'ARS', 'AUD', 'AWG', 'AZN', 'BAM', 'BBD', 'BDT',
+        'BGN', 'BHD', 'BIF', 'BMD', 'BND', 'BOB', 'BRL', 'BSD', 'BTN', 'BWP', 'BYN', 'BZD', 'CAD',
+        'CDF', 'CHF', 'CLP', 'CNY', 'COP', 'CRC', 'CUP', 'CVE', 'CZK', 'DJF', 'DKK', 'DOP', 'DZD',
+        'EGP', 'ERN', 'ETB', 'EUR', 'FJD', 'FKP', 'GBP', 'GEL', 'GHS', 'GIP', 'GMD', 'GNF', 'GTQ',
+        'GYD', 'HKD', 'HNL', 'HTG', 'HUF', 'IDR', 'ILS', 'INR', 'IQD', 'IRR', 'ISK', 'JMD', 'JOD',
+        'JPY', 'KES', 'KGS', 'KHR', 'KMF', 'KPW', 'KRW', 'KWD', 'KYD', 'KZT', 'LAK', 'LBP', 'LKR',
+        'LRD', 'LSL', 'LYD', 'MAD', 'MDL', 'MGA', 'MKD', 'MMK', 'MNT', 'MOP', 'MRU', 'MUR', 'MVR',
+        'MWK', 'MXN', 'MYR', 'MZN', 'NAD', 'NGN', 'NIO', 'NOK', 'NPR', 'NZD', 'OMR', 'PAB', 'PEN',
+        'PGK', 'PHP', 'PKR', 'PLN', 'PYG', 'QAR', 'RON', 'RSD', 'RUB', 'RWF', 'SAR', 'SBD', 'SCR',
+        'SDG', 'SEK', 'SGD', 'SHP', 'SLE', 'SOS', 'SRD', 'SSP', 'STN', 'SVC', 'SYP', 'SZL', 'THB',
+        'TJS', 'TMT', 'TND', 'TOP', 'TRY', 'TTD', 'TWD', 'TZS', 'UAH', 'UGX', 'USD', 'UYU', 'UZS',
+        'VES', 'VND', 'VUV', 'WST', 'XAF', 'XCD', 'XOF', 'XPF', 'XTS', 'YER', 'ZAR', 'ZMW', 'ZWL'
+    ];
+    unknown TEXT;
+BEGIN
+    SELECT string_agg(format('%s.%s %L (%s rows)', tbl, col, code, n), ', ' ORDER BY tbl, col, code)
+    INTO unknown
+    FROM (
+        SELECT tbl, col, code, COUNT(*) AS n
+        FROM (
+            SELECT 'accounts' AS tbl, 'currency' AS col, currency AS code FROM accounts
+            UNION ALL SELECT 'transactions', 'currency', currency FROM transactions
+            UNION ALL SELECT 'transactions', 'settled_currency', settled_currency FROM transactions WHERE settled_currency IS NOT NULL
+            UNION ALL SELECT 'transfers', 'currency', currency FROM transfers
+            UNION ALL SELECT 'postings', 'currency', currency FROM postings
+            UNION ALL SELECT 'fx_rates', 'base_currency', base_currency FROM fx_rates
+            UNION ALL SELECT 'fx_rates', 'quote_currency', quote_currency FROM fx_rates
+            UNION ALL SELECT 'fx_quotes', 'base_currency', base_currency FROM fx_quotes
+            UNION ALL SELECT 'fx_quotes', 'quote_currency', quote_currency FROM fx_quotes
+            UNION ALL SELECT 'fee_schedules', 'currency', currency FROM fee_schedules WHERE currency <> 'ANY'
+            UNION ALL SELECT 'recurring_schedules', 'currency', currency FROM recurring_schedules
+            UNION ALL SELECT 'reconciliation_findings', 'currency', currency FROM reconciliation_findings
+        ) codes
+        WHERE code <> ALL (registry)
+        GROUP BY tbl, col, code
+    ) unknown_codes;
+
+    IF unknown IS NOT NULL THEN
+        RAISE EXCEPTION 'currency codes outside the registry: %', unknown
+            USING HINT = 'Correct or remove these rows, then rerun this migration.';
+    END IF;
+END;
+$$;
+
+COMMIT;
@@ -0,0 +1,6 @@
+-- Limit usage counts card authorizations as well as debits, so the partial
+-- index behind it covers both. Reversals, fees and interest never count.
+DROP INDEX idx_transactions_account_debits;
+CREATE INDEX idx_transactions_account_debits ON transactions(account_id, (COALESCE(execute_at, created_at)))
+    WHERE type IN ('DEBIT', 'AUTHORIZE')
+      AND reverses_transaction_id IS NULL AND fee_for_transaction_id IS NULL AND interest_period IS NULL;
@@ -80,7 +80,7 @@ func (p *OutboxPublisher) publishBatch(ctx context.Context) error {
 
 	// Fetch pending events
 	query := `
-		SELECT id, aggregate_type, aggregate_id, event_type, payload, created_at
+		SELECT id, tenant_id, aggregate_type, aggregate_id, event_type, payload, created_at
 		FROM outbox_events
 		WHERE status = 'PENDING'
 		ORDER BY created_at ASC
@@ -98,7 +98,7 @@ func (p *OutboxPublisher) publishBatch(ctx context.Context) error {
 	for rows.Next() {
 		var event outboxEvent
 		err := rows.Scan(
-			&event.ID, &event.AggregateType, &event.AggregateID,
+			&event.ID, &event.TenantID, &event.AggregateType, &event.AggregateID,
 			&event.EventType, &event.Payload, &event.CreatedAt,
 		)
 		if err != nil {
@@ -143,15 +143,22 @@ func (p *OutboxPublisher) publishEvent(ctx context.Context, event outboxEvent) e
 		TraceID:        "", // Will be set by tracing middleware if available
 		IdempotencyKey: "", // Will be extracted from payload if needed
 		AggregateID:    event.AggregateID,
+		TenantID:       event.TenantID,
 		Payload:        event.Payload,
 	}
 
-	// Extract idempotency key from payload if it's a transaction event
-	if event.EventType == "transaction.created" {
+	// Extract idempotency key from payload if it's a transaction or transfer event
+	switch event.EventType {
+	case types.EventTypeTransactionCreated:
 		var payload types.TransactionCreatedPayload
 		if err := json.Unmarshal(event.Payload, &payload); err == nil {
 			envelope.IdempotencyKey = payload.IdempotencyKey
 		}
+	case types.EventTypeTransferCreated:
+		var payload types.TransferCreatedPayload
+		if err := json.Unmarshal(event.Payload, &payload); err == nil {
+			envelope.IdempotencyKey = payload.IdempotencyKey
+		}
 	}
 
 	envelopeBytes, err := json.Marshal(envelope)
@@ -166,6 +173,7 @@ func (p *OutboxPublisher) publishEvent(ctx context.Context, event outboxEvent) e
 		Headers: []kafka.Header{
 			{Key: "event_type", Value: []byte(event.EventType)},
 			{Key: "aggregate_id", Value: []byte(event.AggregateID.String())},
+			{Key: "tenant_id", Value: []byte(event.TenantID)},
 		},
 	}
 
@@ -213,6 +221,7 @@ func (p *OutboxPublisher) Close() error {
 
 type outboxEvent struct {
 	ID            uuid.UUID
+	TenantID      string
 	AggregateType string
 	AggregateID   uuid.UUID
 	EventType     string
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -30,37 +31,87 @@ type Config struct {
 	WorkerConsumerGroup string
 	PublisherInterval   time.Duration
 	PublisherBatchSize  int
+	HoldTTL             time.Duration
+	HoldExpiryInterval  time.Duration
+	SnapshotInterval    time.Duration
+	SchedulerInterval   time.Duration
+
+	// InterestAccrualInterval is how often the worker accrues daily interest
+	// and posts the interest of ended months
+	InterestAccrualInterval time.Duration
+
+	// TransactionBatchMaxSize caps the items of POST /v1/transactions/batch
+	TransactionBatchMaxSize int
+
+	// ScheduleRunnerInterval is how often the API runs due recurring schedules
+	ScheduleRunnerInterval time.Duration
+
+	// Reconciliation. Auto-repair of drifted balances is opt-in. An interval
+	// of 0 turns off the worker's periodic run in favour of cmd/reconcile.
+	ReconciliationInterval   time.Duration
+	ReconciliationAutoRepair bool
+
+	// FX. FXRevenueAccounts maps a currency to the account credited with
+	// FX spread (FX_REVENUE_ACCOUNTS=USD=<uuid>,EUR=<uuid>)
+	FXQuoteTTL        time.Duration
+	FXRevenueAccounts map[string]string
+
+	// FeeRevenueAccounts maps a currency to the account credited with fees
+	// (FEE_REVENUE_ACCOUNTS=USD=<uuid>); others go to the FEE_REVENUE system account
+	FeeRevenueAccounts map[string]string
+
+	// Risk rules. RiskRulesFile replaces the risk_rules table with a JSON
+	// file; table rules are reloaded every RiskRulesRefreshInterval.
+	RiskRulesFile            string
+	RiskRulesRefreshInterval time.Duration
 
 	// Observability
 	JaegerEndpoint string
 	LogLevel       string
 	Env            string
 
-	// API
-	APIKey string
+	// API. APIKey authenticates the default tenant; APIKeys maps further
+	// keys to their tenant (API_KEYS=<key>=<tenant>,...).
+	APIKey  string
+	APIKeys map[string]string
 }
 
 // LoadConfig loads configuration from environment variables
 func LoadConfig() (*Config, error) {
 	cfg := &Config{
-		PostgresHost:           getEnv("POSTGRES_HOST", "postgres"),
-		PostgresPort:           getEnvAsInt("POSTGRES_PORT", 5432),
-		PostgresUser:           getEnv("POSTGRES_USER", "postgres"),
-		PostgresPassword:       getEnv("POSTGRES_PASSWORD", "postgres"),
-		PostgresDB:             getEnv("POSTGRES_DB", "transactions"),
-		RedisHost:              getEnv("REDIS_HOST", "redis"),
-		RedisPort:              getEnvAsInt("REDIS_PORT", 6379),
-		KafkaBrokers:           getEnv("KAFKA_BROKERS", "redpanda:9092"),
-		KafkaTransactionsTopic: getEnv("KAFKA_TRANSACTIONS_TOPIC", "transactions"),
-		KafkaDLQTopic:          getEnv("KAFKA_DLQ_TOPIC", "transactions.dlq"),
-		APIPort:                getEnvAsInt("API_PORT", 8080),
-		WorkerConsumerGroup:    getEnv("WORKER_CONSUMER_GROUP", "transaction-workers"),
-		PublisherInterval:      getEnvAsDuration("PUBLISHER_INTERVAL", 5*time.Second),
-		PublisherBatchSize:     getEnvAsInt("PUBLISHER_BATCH_SIZE", 100),
-		JaegerEndpoint:         getEnv("JAEGER_ENDPOINT", "http://jaeger:14268/api/traces"),
-		LogLevel:               getEnv("LOG_LEVEL", "info"),
-		Env:                    getEnv("ENV", "development"),
-		APIKey:                 getEnv("API_KEY", ""),
+		PostgresHost:             getEnv("POSTGRES_HOST", "postgres"),
+		PostgresPort:             getEnvAsInt("POSTGRES_PORT", 5432),
+		PostgresUser:             getEnv("POSTGRES_USER", "postgres"),
+		PostgresPassword:         getEnv("POSTGRES_PASSWORD", "postgres"),
+		PostgresDB:               getEnv("POSTGRES_DB", "transactions"),
+		RedisHost:                getEnv("REDIS_HOST", "redis"),
+		RedisPort:                getEnvAsInt("REDIS_PORT", 6379),
+		KafkaBrokers:             getEnv("KAFKA_BROKERS", "redpanda:9092"),
+		KafkaTransactionsTopic:   getEnv("KAFKA_TRANSACTIONS_TOPIC", "transactions"),
+		KafkaDLQTopic:            getEnv("KAFKA_DLQ_TOPIC", "transactions.dlq"),
+		APIPort:                  getEnvAsInt("API_PORT", 8080),
+		WorkerConsumerGroup:      getEnv("WORKER_CONSUMER_GROUP", "transaction-workers"),
+		PublisherInterval:        getEnvAsDuration("PUBLISHER_INTERVAL", 5*time.Second),
+		PublisherBatchSize:       getEnvAsInt("PUBLISHER_BATCH_SIZE", 100),
+		HoldTTL:                  getEnvAsDuration("HOLD_TTL", 7*24*time.Hour),
+		HoldExpiryInterval:       getEnvAsDuration("HOLD_EXPIRY_INTERVAL", time.Minute),
+		SnapshotInterval:         getEnvAsDuration("BALANCE_SNAPSHOT_INTERVAL", time.Hour),
+		SchedulerInterval:        getEnvAsDuration("SCHEDULER_INTERVAL", 5*time.Second),
+		InterestAccrualInterval:  getEnvAsDuration("INTEREST_ACCRUAL_INTERVAL", time.Hour),
+		TransactionBatchMaxSize:  getEnvAsInt("TRANSACTION_BATCH_MAX_SIZE", 1000),
+		ScheduleRunnerInterval:   getEnvAsDuration("SCHEDULE_RUNNER_INTERVAL", 30*time.Second),
+		ReconciliationInterval:   getEnvAsDuration("RECONCILIATION_INTERVAL", time.Hour),
+		ReconciliationAutoRepair: getEnvAsBool("RECONCILIATION_AUTO_REPAIR", false),
+		FXQuoteTTL:               getEnvAsDuration("FX_QUOTE_TTL", time.Minute),
+		FXRevenueAccounts:        getEnvAsMap("FX_REVENUE_ACCOUNTS"),
+		FeeRevenueAccounts:       getEnvAsMap("FEE_REVENUE_ACCOUNTS"),
+		RiskRulesFile:            getEnv("RISK_RULES_FILE", ""),
+		RiskRulesRefreshInterval: getEnvAsDuration("RISK_RULES_REFRESH_INTERVAL", 30*time.Second),
+		JaegerEndpoint:           getEnv("JAEGER_ENDPOINT", "http://jaeger:14268/api/traces"),
+		LogLevel:                 getEnv("LOG_LEVEL", "info"),
+		Env:                      getEnv("ENV", "development"),
+		APIKey:                   getEnv("API_KEY", ""),
+		APIKeys:                  getEnvAsMap("API_KEYS"),
 	}
 
 	return cfg, nil
@@ -96,3 +147,24 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsMap parses a comma-separated list of key=value pairs
+func getEnvAsMap(key string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && k != "" && v != "" {
+			result[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return result
+}
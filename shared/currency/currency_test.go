@@ -0,0 +1,99 @@
+package currency
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		amount int64
+		code   string
+		want   string
+	}{
+		{amount: 1234, code: "USD", want: "12.34"},
+		{amount: 5, code: "USD", want: "0.05"},
+		{amount: 0, code: "USD", want: "0.00"},
+		{amount: 100, code: "USD", want: "1.00"},
+		{amount: -1234, code: "USD", want: "-12.34"},
+		{amount: -5, code: "USD", want: "-0.05"},
+		{amount: 1234, code: "JPY", want: "1234"},
+		{amount: 0, code: "JPY", want: "0"},
+		{amount: -1234, code: "JPY", want: "-1234"},
+		{amount: 1234, code: "KWD", want: "1.234"},
+		{amount: 5, code: "KWD", want: "0.005"},
+		{amount: 1000, code: "KWD", want: "1.000"},
+		{amount: -5, code: "KWD", want: "-0.005"},
+		{amount: -12345, code: "KWD", want: "-12.345"},
+		{amount: 1234, code: "ZZZ", want: "12.34"},
+	}
+
+	for _, tt := range tests {
+		if got := Format(tt.amount, tt.code); got != tt.want {
+			t.Errorf("Format(%d, %q) = %q; want %q", tt.amount, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		code    string
+		wantErr bool
+	}{
+		{code: "USD"},
+		{code: "JPY"},
+		{code: "KWD"},
+		{code: "XTS"},
+		{code: "", wantErr: true},
+		{code: "usd", wantErr: true},
+		{code: "ZZZ", wantErr: true},
+		{code: "USDX", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := Validate("currency", tt.code)
+		if tt.wantErr && err == nil {
+			t.Errorf("Validate(%q) = nil; want an error", tt.code)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("Validate(%q) = %v; want nil", tt.code, err)
+		}
+	}
+}
+
+func TestExponent(t *testing.T) {
+	tests := []struct {
+		code string
+		want int
+	}{
+		{code: "USD", want: 2},
+		{code: "JPY", want: 0},
+		{code: "KWD", want: 3},
+		{code: "ZZZ", want: 2},
+	}
+
+	for _, tt := range tests {
+		if got := Exponent(tt.code); got != tt.want {
+			t.Errorf("Exponent(%q) = %d; want %d", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     *big.Rat
+	}{
+		{from: "USD", to: "EUR", want: big.NewRat(1, 1)},
+		{from: "USD", to: "JPY", want: big.NewRat(1, 100)},
+		{from: "JPY", to: "USD", want: big.NewRat(100, 1)},
+		{from: "USD", to: "KWD", want: big.NewRat(10, 1)},
+		{from: "KWD", to: "JPY", want: big.NewRat(1, 1000)},
+	}
+
+	for _, tt := range tests {
+		if got := Scale(tt.from, tt.to); got.Cmp(tt.want) != 0 {
+			t.Errorf("Scale(%q, %q) = %s; want %s", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
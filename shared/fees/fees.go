@@ -0,0 +1,135 @@
+package fees
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/types"
+)
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Chargeable reports whether transactions of the type can carry a fee
+func Chargeable(t types.TransactionType) bool {
+	return t == types.TransactionTypeDebit || t == types.TransactionTypeCredit || t == types.TransactionTypeCapture
+}
+
+// Validate checks that a schedule's amounts and rates are in range and its
+// tiers are in ascending order of volume
+func Validate(req types.SetFeeScheduleRequest) error {
+	if req.FlatCents < 0 {
+		return fmt.Errorf("flat_cents must not be negative")
+	}
+	if req.RateBps < 0 || req.RateBps > 10000 {
+		return fmt.Errorf("rate_bps must be between 0 and 10000")
+	}
+	if (req.MinCents != nil && *req.MinCents < 0) || (req.MaxCents != nil && *req.MaxCents < 0) {
+		return fmt.Errorf("min_cents and max_cents must not be negative")
+	}
+	if req.MinCents != nil && req.MaxCents != nil && *req.MinCents > *req.MaxCents {
+		return fmt.Errorf("min_cents must not exceed max_cents")
+	}
+	for i, tier := range req.Tiers {
+		if tier.MinMonthlyVolumeCents < 0 || tier.FlatCents < 0 || tier.RateBps < 0 || tier.RateBps > 10000 {
+			return fmt.Errorf("tier %d is out of range", i)
+		}
+		if i > 0 && tier.MinMonthlyVolumeCents <= req.Tiers[i-1].MinMonthlyVolumeCents {
+			return fmt.Errorf("tiers must be in ascending order of min_monthly_volume_cents")
+		}
+	}
+	return nil
+}
+
+// Calculate returns the fee on amountCents given the account's monthly volume
+// of the transaction type so far. Percentages round half up.
+func Calculate(schedule types.FeeSchedule, amountCents, monthlyVolumeCents int64) int64 {
+	flat, rate := schedule.FlatCents, schedule.RateBps
+	for _, tier := range schedule.Tiers {
+		if monthlyVolumeCents >= tier.MinMonthlyVolumeCents {
+			flat, rate = tier.FlatCents, tier.RateBps
+		}
+	}
+
+	// Split the amount so amount*rate cannot overflow
+	fee := flat + amountCents/10000*rate + (amountCents%10000*rate+5000)/10000
+	if schedule.MinCents != nil && fee < *schedule.MinCents {
+		fee = *schedule.MinCents
+	}
+	if schedule.MaxCents != nil && fee > *schedule.MaxCents {
+		fee = *schedule.MaxCents
+	}
+	return fee
+}
+
+// Lookup returns the schedule for the transaction type in the currency,
+// falling back to the schedule for any currency, or nil if neither exists
+func Lookup(ctx context.Context, q queryer, transactionType types.TransactionType, currency string) (*types.FeeSchedule, error) {
+	query := `
+		SELECT ` + ScheduleColumns + `
+		FROM fee_schedules
+		WHERE transaction_type = $1 AND currency IN ($2, $3)
+		ORDER BY currency = $3
+		LIMIT 1
+	`
+	schedule, err := ScanSchedule(q.QueryRowContext(ctx, query, transactionType, currency, types.FeeCurrencyAny))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load fee schedule: %w", err)
+	}
+	return schedule, nil
+}
+
+// MonthlyVolume sums the account's processed transactions of the type in the
+// UTC month containing at, in the account currency. Reversals, fees,
+// interest and exclude are left out.
+func MonthlyVolume(ctx context.Context, q queryer, accountID uuid.UUID, transactionType types.TransactionType, at time.Time, exclude uuid.UUID) (int64, error) {
+	at = at.UTC()
+	month := time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.UTC)
+	query := `
+		SELECT COALESCE(SUM(COALESCE(settled_amount_cents, amount_cents)), 0)
+		FROM transactions
+		WHERE account_id = $1 AND type = $2 AND status = $3 AND id <> $4
+		  AND reverses_transaction_id IS NULL AND fee_for_transaction_id IS NULL
+		  AND interest_period IS NULL AND created_at >= $5 AND created_at < $6
+	`
+	var volume int64
+	err := q.QueryRowContext(ctx, query, accountID, transactionType, types.TransactionStatusProcessed, exclude, month, month.AddDate(0, 1, 0)).Scan(&volume)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute monthly volume: %w", err)
+	}
+	return volume, nil
+}
+
+// ScheduleColumns is the column list read by ScanSchedule
+const ScheduleColumns = `id, transaction_type, currency, flat_cents, rate_bps, min_cents, max_cents, tiers, updated_at`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// ScanSchedule scans a row selected with ScheduleColumns
+func ScanSchedule(row rowScanner) (*types.FeeSchedule, error) {
+	var schedule types.FeeSchedule
+	var tiers []byte
+	err := row.Scan(&schedule.ID, &schedule.TransactionType, &schedule.Currency, &schedule.FlatCents, &schedule.RateBps,
+		&schedule.MinCents, &schedule.MaxCents, &tiers, &schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(tiers, &schedule.Tiers); err != nil {
+		return nil, fmt.Errorf("failed to parse fee tiers: %w", err)
+	}
+	return &schedule, nil
+}
@@ -0,0 +1,155 @@
+package fees
+
+import (
+	"testing"
+
+	"github.com/yash/transaction-system/shared/types"
+)
+
+func cents(n int64) *int64 {
+	return &n
+}
+
+func TestCalculate(t *testing.T) {
+	tiered := types.FeeSchedule{
+		FlatCents: 30,
+		RateBps:   290,
+		Tiers: []types.FeeTier{
+			{MinMonthlyVolumeCents: 100000, FlatCents: 25, RateBps: 250},
+			{MinMonthlyVolumeCents: 1000000, FlatCents: 0, RateBps: 150},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		schedule types.FeeSchedule
+		amount   int64
+		volume   int64
+		want     int64
+	}{
+		{
+			name:     "flat fee only",
+			schedule: types.FeeSchedule{FlatCents: 50},
+			amount:   12345,
+			want:     50,
+		},
+		{
+			name:     "flat plus rate",
+			schedule: types.FeeSchedule{FlatCents: 30, RateBps: 290},
+			amount:   10000,
+			want:     320,
+		},
+		{
+			name:     "rate rounds half up",
+			schedule: types.FeeSchedule{RateBps: 50},
+			amount:   100,
+			want:     1,
+		},
+		{
+			name:     "rate rounds down below half",
+			schedule: types.FeeSchedule{RateBps: 49},
+			amount:   100,
+			want:     0,
+		},
+		{
+			name:     "remainder of the split amount is rounded once",
+			schedule: types.FeeSchedule{RateBps: 25},
+			amount:   10020,
+			want:     25,
+		},
+		{
+			name:     "large amounts do not overflow",
+			schedule: types.FeeSchedule{RateBps: 10000},
+			amount:   922337203685477580,
+			want:     922337203685477580,
+		},
+		{
+			name:     "base rates below the first tier",
+			schedule: tiered,
+			amount:   10000,
+			volume:   99999,
+			want:     320,
+		},
+		{
+			name:     "tier applies from its minimum volume",
+			schedule: tiered,
+			amount:   10000,
+			volume:   100000,
+			want:     275,
+		},
+		{
+			name:     "highest reached tier wins",
+			schedule: tiered,
+			amount:   10000,
+			volume:   5000000,
+			want:     150,
+		},
+		{
+			name:     "clamped to the minimum",
+			schedule: types.FeeSchedule{RateBps: 100, MinCents: cents(50)},
+			amount:   1000,
+			want:     50,
+		},
+		{
+			name:     "clamped to the maximum",
+			schedule: types.FeeSchedule{RateBps: 100, MaxCents: cents(500)},
+			amount:   1000000,
+			want:     500,
+		},
+		{
+			name:     "within the bounds",
+			schedule: types.FeeSchedule{RateBps: 100, MinCents: cents(50), MaxCents: cents(500)},
+			amount:   20000,
+			want:     200,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Calculate(tt.schedule, tt.amount, tt.volume); got != tt.want {
+				t.Errorf("Calculate() = %d; want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     types.SetFeeScheduleRequest
+		wantErr bool
+	}{
+		{name: "empty schedule", req: types.SetFeeScheduleRequest{}},
+		{name: "full schedule", req: types.SetFeeScheduleRequest{
+			FlatCents: 30, RateBps: 290, MinCents: cents(50), MaxCents: cents(500),
+			Tiers: []types.FeeTier{{MinMonthlyVolumeCents: 1000, RateBps: 250}, {MinMonthlyVolumeCents: 2000, RateBps: 200}},
+		}},
+		{name: "min equal to max", req: types.SetFeeScheduleRequest{MinCents: cents(50), MaxCents: cents(50)}},
+		{name: "negative flat", req: types.SetFeeScheduleRequest{FlatCents: -1}, wantErr: true},
+		{name: "negative rate", req: types.SetFeeScheduleRequest{RateBps: -1}, wantErr: true},
+		{name: "rate above 100%", req: types.SetFeeScheduleRequest{RateBps: 10001}, wantErr: true},
+		{name: "negative min", req: types.SetFeeScheduleRequest{MinCents: cents(-1)}, wantErr: true},
+		{name: "min above max", req: types.SetFeeScheduleRequest{MinCents: cents(100), MaxCents: cents(50)}, wantErr: true},
+		{name: "tier out of range", req: types.SetFeeScheduleRequest{
+			Tiers: []types.FeeTier{{MinMonthlyVolumeCents: 1000, RateBps: 10001}},
+		}, wantErr: true},
+		{name: "tiers out of order", req: types.SetFeeScheduleRequest{
+			Tiers: []types.FeeTier{{MinMonthlyVolumeCents: 2000}, {MinMonthlyVolumeCents: 1000}},
+		}, wantErr: true},
+		{name: "duplicate tier volume", req: types.SetFeeScheduleRequest{
+			Tiers: []types.FeeTier{{MinMonthlyVolumeCents: 1000}, {MinMonthlyVolumeCents: 1000}},
+		}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.req)
+			if tt.wantErr && err == nil {
+				t.Errorf("Validate() = nil; want an error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Validate() = %v; want nil", err)
+			}
+		})
+	}
+}
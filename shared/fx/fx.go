@@ -0,0 +1,120 @@
+package fx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/yash/transaction-system/shared/currency"
+)
+
+// ErrRateNotFound is returned when no rate exists for a currency pair
+var ErrRateNotFound = errors.New("fx rate not found")
+
+// Rate is a mid-market conversion rate with the spread charged on top of it.
+// Value is the number of quote currency units per base currency unit.
+type Rate struct {
+	Value     *big.Rat
+	SpreadBps int
+}
+
+// Conversion is the result of converting an amount into another currency.
+// SettledCents is what the account is charged (debit) or receives (credit);
+// SpreadCents is the revenue kept, the gap between the mid amount and it.
+type Conversion struct {
+	MidCents     int64
+	SpreadCents  int64
+	SettledCents int64
+}
+
+// ParseRate parses a positive decimal rate such as "1.0825"
+func ParseRate(s string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return nil, fmt.Errorf("invalid rate %q", s)
+	}
+	if rate.Sign() <= 0 {
+		return nil, fmt.Errorf("rate must be positive")
+	}
+	return rate, nil
+}
+
+// FormatRate renders a rate with up to 12 decimal places and no trailing zeros
+func FormatRate(rate *big.Rat) string {
+	s := rate.FloatString(12)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// Round rounds a rational to the nearest integer, halves away from zero
+func Round(r *big.Rat) int64 {
+	num := new(big.Int).Abs(r.Num())
+	quo, rem := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(r.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		quo.Neg(quo)
+	}
+	return quo.Int64()
+}
+
+// Convert converts amountCents, in minor units of from, at the rate into minor
+// units of to; rates are quoted in major units, so the amount is rescaled when
+// the currencies have different exponents. The spread is charged on top of a
+// debit and withheld from a credit, so the customer always gets the worse side.
+func Convert(amountCents int64, from, to string, rate Rate, debit bool) Conversion {
+	mid := new(big.Rat).Mul(new(big.Rat).SetInt64(amountCents), rate.Value)
+	mid.Mul(mid, currency.Scale(from, to))
+	midCents := Round(mid)
+	spreadCents := Round(new(big.Rat).Mul(new(big.Rat).SetInt64(midCents), big.NewRat(int64(rate.SpreadBps), 10000)))
+
+	settledCents := midCents - spreadCents
+	if debit {
+		settledCents = midCents + spreadCents
+	}
+	return Conversion{
+		MidCents:     midCents,
+		SpreadCents:  spreadCents,
+		SettledCents: settledCents,
+	}
+}
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// LatestRate returns the most recent effective rate for converting base into
+// quote. A rate published for the inverse pair is inverted.
+func LatestRate(ctx context.Context, q queryer, base, quote string) (*Rate, error) {
+	query := `
+		SELECT base_currency, rate, spread_bps
+		FROM fx_rates
+		WHERE ((base_currency = $1 AND quote_currency = $2) OR (base_currency = $2 AND quote_currency = $1))
+		  AND effective_at <= NOW()
+		ORDER BY effective_at DESC, created_at DESC
+		LIMIT 1
+	`
+	var rateBase, rateValue string
+	var spreadBps int
+	err := q.QueryRowContext(ctx, query, base, quote).Scan(&rateBase, &rateValue, &spreadBps)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s/%s", ErrRateNotFound, base, quote)
+		}
+		return nil, fmt.Errorf("failed to load fx rate: %w", err)
+	}
+
+	value, err := ParseRate(rateValue)
+	if err != nil {
+		return nil, err
+	}
+	if rateBase != base {
+		value.Inv(value)
+	}
+	return &Rate{Value: value, SpreadBps: spreadBps}, nil
+}
@@ -0,0 +1,122 @@
+package fx
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		num, denom int64
+		want       int64
+	}{
+		{num: 0, denom: 1, want: 0},
+		{num: 7, denom: 1, want: 7},
+		{num: 1, denom: 3, want: 0},
+		{num: 2, denom: 3, want: 1},
+		{num: 1, denom: 2, want: 1},
+		{num: 5, denom: 2, want: 3},
+		{num: 7, denom: 3, want: 2},
+		{num: 49999, denom: 100000, want: 0},
+		{num: 50000, denom: 100000, want: 1},
+		{num: -1, denom: 3, want: 0},
+		{num: -1, denom: 2, want: -1},
+		{num: -5, denom: 2, want: -3},
+		{num: -7, denom: 3, want: -2},
+	}
+
+	for _, tt := range tests {
+		r := big.NewRat(tt.num, tt.denom)
+		if got := Round(r); got != tt.want {
+			t.Errorf("Round(%s) = %d; want %d", r, got, tt.want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name      string
+		amount    int64
+		from, to  string
+		rate      string
+		spreadBps int
+		debit     bool
+		want      Conversion
+	}{
+		{
+			name: "debit pays the spread on top", amount: 10000, from: "USD", to: "EUR", rate: "0.9235", spreadBps: 50, debit: true,
+			want: Conversion{MidCents: 9235, SpreadCents: 46, SettledCents: 9281},
+		},
+		{
+			name: "credit has the spread withheld", amount: 10000, from: "USD", to: "EUR", rate: "0.9235", spreadBps: 50,
+			want: Conversion{MidCents: 9235, SpreadCents: 46, SettledCents: 9189},
+		},
+		{
+			name: "spread rounds half away from zero", amount: 10000, from: "USD", to: "EUR", rate: "1", spreadBps: 5, debit: true,
+			want: Conversion{MidCents: 10000, SpreadCents: 5, SettledCents: 10005},
+		},
+		{
+			name: "mid rounds half away from zero", amount: 3, from: "USD", to: "EUR", rate: "0.5",
+			want: Conversion{MidCents: 2, SettledCents: 2},
+		},
+		{
+			name: "into a currency without minor units", amount: 1234, from: "USD", to: "JPY", rate: "151.37", spreadBps: 25, debit: true,
+			want: Conversion{MidCents: 1868, SpreadCents: 5, SettledCents: 1873},
+		},
+		{
+			name: "from a currency without minor units", amount: 10000, from: "JPY", to: "USD", rate: "0.0066",
+			want: Conversion{MidCents: 6600, SettledCents: 6600},
+		},
+		{
+			name: "into a currency with three decimals", amount: 100, from: "USD", to: "KWD", rate: "0.307",
+			want: Conversion{MidCents: 307, SettledCents: 307},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := ParseRate(tt.rate)
+			if err != nil {
+				t.Fatalf("ParseRate(%q): %v", tt.rate, err)
+			}
+			got := Convert(tt.amount, tt.from, tt.to, Rate{Value: value, SpreadBps: tt.spreadBps}, tt.debit)
+			if got != tt.want {
+				t.Errorf("Convert() = %+v; want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAndFormatRate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "1.0825", want: "1.0825"},
+		{in: " 151.370 ", want: "151.37"},
+		{in: "2", want: "2"},
+		{in: "1/3", want: "0.333333333333"},
+		{in: "0", wantErr: true},
+		{in: "-1.5", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		rate, err := ParseRate(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseRate(%q) = %s; want an error", tt.in, rate)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseRate(%q): %v", tt.in, err)
+			continue
+		}
+		if got := FormatRate(rate); got != tt.want {
+			t.Errorf("FormatRate(ParseRate(%q)) = %q; want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package interest
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/yash/transaction-system/shared/types"
+)
+
+// ParseRate parses an annual rate such as "0.0425". Rates range from 0 to 1.
+func ParseRate(s string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return nil, fmt.Errorf("invalid annual_rate %q", s)
+	}
+	if rate.Sign() < 0 || rate.Cmp(big.NewRat(1, 1)) > 0 {
+		return nil, fmt.Errorf("annual_rate must be between 0 and 1")
+	}
+	return rate, nil
+}
+
+// DayFraction returns the fraction of a year the given UTC day counts for.
+// Under 30/360 (bond basis) the 30th of a month counts for nothing when the
+// month has a 31st, and the last day of February makes up the rest of its
+// 30 days, so every month totals 30/360.
+func DayFraction(convention types.DayCountConvention, day time.Time) *big.Rat {
+	if convention != types.DayCount30360 {
+		return big.NewRat(1, 365)
+	}
+	next := day.AddDate(0, 0, 1)
+	return big.NewRat(int64(days30360(day, next)), 360)
+}
+
+// days30360 counts the days from start to end under 30/360 (bond basis)
+func days30360(start, end time.Time) int {
+	d1, d2 := start.Day(), end.Day()
+	if d1 == 31 {
+		d1 = 30
+	}
+	if d2 == 31 && d1 == 30 {
+		d2 = 30
+	}
+	return 360*(end.Year()-start.Year()) + 30*(int(end.Month())-int(start.Month())) + d2 - d1
+}
+
+// Accrue returns the interest in cents, with fractions, that balanceCents
+// accrues for one day at the annual rate
+func Accrue(balanceCents int64, rate *big.Rat, convention types.DayCountConvention, day time.Time) *big.Rat {
+	amount := new(big.Rat).SetInt64(balanceCents)
+	amount.Mul(amount, rate)
+	return amount.Mul(amount, DayFraction(convention, day))
+}
+
+// InterestBalance returns the part of an end-of-day balance interest accrues
+// on: the positive balance of a STANDARD account, the drawn amount of a
+// CREDIT_LINE, and nothing otherwise
+func InterestBalance(kind types.AccountKind, balanceCents int64) int64 {
+	switch {
+	case kind == types.AccountKindCreditLine && balanceCents < 0:
+		return -balanceCents
+	case kind != types.AccountKindCreditLine && balanceCents > 0:
+		return balanceCents
+	default:
+		return 0
+	}
+}
+
+// PostingType returns the type of the transaction that posts interest to an
+// account of the kind: a CREDIT paid on savings or a DEBIT charged on a credit line
+func PostingType(kind types.AccountKind) types.TransactionType {
+	if kind == types.AccountKindCreditLine {
+		return types.TransactionTypeDebit
+	}
+	return types.TransactionTypeCredit
+}
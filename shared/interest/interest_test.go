@@ -0,0 +1,121 @@
+package interest
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/yash/transaction-system/shared/types"
+)
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDayFraction30360(t *testing.T) {
+	tests := []struct {
+		day  time.Time
+		want *big.Rat
+	}{
+		{day: day(2026, time.January, 15), want: big.NewRat(1, 360)},
+		{day: day(2026, time.January, 30), want: big.NewRat(0, 1)},
+		{day: day(2026, time.January, 31), want: big.NewRat(1, 360)},
+		{day: day(2026, time.April, 30), want: big.NewRat(1, 360)},
+		{day: day(2026, time.February, 28), want: big.NewRat(3, 360)},
+		{day: day(2028, time.February, 28), want: big.NewRat(1, 360)},
+		{day: day(2028, time.February, 29), want: big.NewRat(2, 360)},
+		{day: day(2026, time.December, 31), want: big.NewRat(1, 360)},
+	}
+
+	for _, tt := range tests {
+		if got := DayFraction(types.DayCount30360, tt.day); got.Cmp(tt.want) != 0 {
+			t.Errorf("DayFraction(30/360, %s) = %s; want %s", tt.day.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestDayFraction30360MonthTotals(t *testing.T) {
+	for _, year := range []int{2026, 2028} {
+		for month := time.January; month <= time.December; month++ {
+			total := new(big.Rat)
+			for d := day(year, month, 1); d.Month() == month; d = d.AddDate(0, 0, 1) {
+				total.Add(total, DayFraction(types.DayCount30360, d))
+			}
+			if want := big.NewRat(30, 360); total.Cmp(want) != 0 {
+				t.Errorf("30/360 total for %d-%02d = %s; want %s", year, month, total, want)
+			}
+		}
+	}
+}
+
+func TestDayFractionActual365(t *testing.T) {
+	for _, d := range []time.Time{day(2026, time.January, 30), day(2026, time.February, 28), day(2028, time.February, 29)} {
+		if got, want := DayFraction(types.DayCountActual365, d), big.NewRat(1, 365); got.Cmp(want) != 0 {
+			t.Errorf("DayFraction(ACT/365, %s) = %s; want %s", d.Format("2006-01-02"), got, want)
+		}
+	}
+}
+
+func TestAccrue(t *testing.T) {
+	tests := []struct {
+		name       string
+		balance    int64
+		rate       string
+		convention types.DayCountConvention
+		day        time.Time
+		want       *big.Rat
+	}{
+		{
+			name: "actual/365", balance: 1000000, rate: "0.0365", convention: types.DayCountActual365,
+			day: day(2026, time.March, 10), want: big.NewRat(100, 1),
+		},
+		{
+			name: "30/360", balance: 1000000, rate: "0.036", convention: types.DayCount30360,
+			day: day(2026, time.March, 10), want: big.NewRat(100, 1),
+		},
+		{
+			name: "30/360 on the 30th of a 31-day month", balance: 1000000, rate: "0.036", convention: types.DayCount30360,
+			day: day(2026, time.March, 30), want: big.NewRat(0, 1),
+		},
+		{
+			name: "fractions are kept", balance: 1234, rate: "0.05", convention: types.DayCountActual365,
+			day: day(2026, time.March, 10), want: big.NewRat(617, 3650),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := ParseRate(tt.rate)
+			if err != nil {
+				t.Fatalf("ParseRate(%q): %v", tt.rate, err)
+			}
+			if got := Accrue(tt.balance, rate, tt.convention, tt.day); got.Cmp(tt.want) != 0 {
+				t.Errorf("Accrue() = %s; want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in      string
+		wantErr bool
+	}{
+		{in: "0.0425"},
+		{in: " 0 "},
+		{in: "1"},
+		{in: "1.01", wantErr: true},
+		{in: "-0.01", wantErr: true},
+		{in: "abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseRate(tt.in)
+		if tt.wantErr && err == nil {
+			t.Errorf("ParseRate(%q) = nil error; want an error", tt.in)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("ParseRate(%q): %v", tt.in, err)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package limits
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/yash/transaction-system/shared/types"
+)
+
+// AcceptedStatuses are the statuses of debits the API counts: every debit it
+// has accepted that has not failed or been cancelled
+var AcceptedStatuses = []types.TransactionStatus{
+	types.TransactionStatusScheduled,
+	types.TransactionStatusPending,
+	types.TransactionStatusInReview,
+	types.TransactionStatusProcessing,
+	types.TransactionStatusProcessed,
+}
+
+// ProcessedStatuses are the statuses of debits the worker counts when it
+// enforces limits authoritatively
+var ProcessedStatuses = []types.TransactionStatus{types.TransactionStatusProcessed}
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Effective returns the limits that apply to an account: each limit comes
+// from the account's override if set, otherwise from its tier
+func Effective(ctx context.Context, q queryer, accountID uuid.UUID) (types.TransactionLimits, error) {
+	query := `
+		SELECT COALESCE(o.max_single_debit_cents, t.max_single_debit_cents),
+		       COALESCE(o.max_daily_debit_cents, t.max_daily_debit_cents),
+		       COALESCE(o.max_monthly_debit_cents, t.max_monthly_debit_cents),
+		       COALESCE(o.max_daily_debit_count, t.max_daily_debit_count),
+		       COALESCE(o.max_monthly_debit_count, t.max_monthly_debit_count)
+		FROM accounts a
+		LEFT JOIN transaction_limits o ON o.account_id = a.id
+		LEFT JOIN transaction_limits t ON t.tier = a.tier
+		WHERE a.id = $1
+	`
+	var limits types.TransactionLimits
+	err := q.QueryRowContext(ctx, query, accountID).Scan(
+		&limits.MaxSingleDebitCents, &limits.MaxDailyDebitCents, &limits.MaxMonthlyDebitCents,
+		&limits.MaxDailyDebitCount, &limits.MaxMonthlyDebitCount,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return limits, fmt.Errorf("account not found")
+		}
+		return limits, fmt.Errorf("failed to load limits: %w", err)
+	}
+	return limits, nil
+}
+
+// Merge returns the override of each limit if set, otherwise the tier's
+func Merge(override, tier types.TransactionLimits) types.TransactionLimits {
+	merged := tier
+	if override.MaxSingleDebitCents != nil {
+		merged.MaxSingleDebitCents = override.MaxSingleDebitCents
+	}
+	if override.MaxDailyDebitCents != nil {
+		merged.MaxDailyDebitCents = override.MaxDailyDebitCents
+	}
+	if override.MaxMonthlyDebitCents != nil {
+		merged.MaxMonthlyDebitCents = override.MaxMonthlyDebitCents
+	}
+	if override.MaxDailyDebitCount != nil {
+		merged.MaxDailyDebitCount = override.MaxDailyDebitCount
+	}
+	if override.MaxMonthlyDebitCount != nil {
+		merged.MaxMonthlyDebitCount = override.MaxMonthlyDebitCount
+	}
+	return merged
+}
+
+// Windows returns the start of the UTC day and month containing at
+func Windows(at time.Time) (time.Time, time.Time) {
+	at = at.UTC()
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return day, month
+}
+
+// Usage sums the account's debits in the day and month containing at. Only
+// transactions in one of the given statuses count; exclude (if not uuid.Nil)
+// is left out so a transaction is not counted against itself. Reversals are
+// refunds and fees and interest are charged by the platform; none counts. A
+// scheduled debit counts in the window of its execute_at. A card
+// authorization counts as one debit of its held amount, or of its captured
+// amount once captured; a voided or expired one no longer counts and its
+// captures never count apart. The filters match the partial index
+// idx_transactions_account_debits.
+func Usage(ctx context.Context, q queryer, accountID uuid.UUID, at time.Time, statuses []types.TransactionStatus, exclude uuid.UUID) (types.LimitUsage, error) {
+	day, month := Windows(at)
+	statusNames := make(pq.StringArray, len(statuses))
+	for i, status := range statuses {
+		statusNames[i] = string(status)
+	}
+
+	query := `
+		SELECT COALESCE(SUM(amount) FILTER (WHERE counted_at >= $3), 0),
+		       COUNT(*) FILTER (WHERE counted_at >= $3),
+		       COALESCE(SUM(amount), 0),
+		       COUNT(*)
+		FROM (
+			SELECT CASE WHEN type = 'AUTHORIZE' THEN COALESCE(captured_amount_cents, amount_cents)
+			            ELSE COALESCE(settled_amount_cents, amount_cents) END AS amount,
+			       COALESCE(execute_at, created_at) AS counted_at
+			FROM transactions
+			WHERE account_id = $1 AND reverses_transaction_id IS NULL
+			  AND (type = 'DEBIT' OR (type = 'AUTHORIZE' AND COALESCE(hold_status, 'HELD') IN ('HELD', 'CAPTURED')))
+			  AND fee_for_transaction_id IS NULL AND interest_period IS NULL
+			  AND status = ANY($2) AND id <> $5
+			  AND COALESCE(execute_at, created_at) >= $4
+			  AND COALESCE(execute_at, created_at) < $6
+		) debits
+	`
+	var usage types.LimitUsage
+	err := q.QueryRowContext(ctx, query, accountID, statusNames, day, month, exclude, month.AddDate(0, 1, 0)).Scan(
+		&usage.DailyDebitCents, &usage.DailyDebitCount, &usage.MonthlyDebitCents, &usage.MonthlyDebitCount,
+	)
+	if err != nil {
+		return usage, fmt.Errorf("failed to compute limit usage: %w", err)
+	}
+	return usage, nil
+}
+
+// Evaluate returns the first limit a debit of amountCents would exceed on
+// top of usage, or nil if it is within every limit
+func Evaluate(limits types.TransactionLimits, usage types.LimitUsage, amountCents int64) *types.LimitViolation {
+	switch {
+	case limits.MaxSingleDebitCents != nil && amountCents > *limits.MaxSingleDebitCents:
+		return violation(types.FailureCodeSingleDebitLimit, "debit of %d exceeds single debit limit of %d",
+			amountCents, *limits.MaxSingleDebitCents)
+	case limits.MaxDailyDebitCents != nil && usage.DailyDebitCents+amountCents > *limits.MaxDailyDebitCents:
+		return violation(types.FailureCodeDailyAmountLimit, "debit of %d exceeds daily debit limit of %d (used %d)",
+			amountCents, *limits.MaxDailyDebitCents, usage.DailyDebitCents)
+	case limits.MaxMonthlyDebitCents != nil && usage.MonthlyDebitCents+amountCents > *limits.MaxMonthlyDebitCents:
+		return violation(types.FailureCodeMonthlyAmountLimit, "debit of %d exceeds monthly debit limit of %d (used %d)",
+			amountCents, *limits.MaxMonthlyDebitCents, usage.MonthlyDebitCents)
+	case limits.MaxDailyDebitCount != nil && usage.DailyDebitCount+1 > *limits.MaxDailyDebitCount:
+		return violation(types.FailureCodeDailyCountLimit, "daily debit count limit of %d reached",
+			*limits.MaxDailyDebitCount)
+	case limits.MaxMonthlyDebitCount != nil && usage.MonthlyDebitCount+1 > *limits.MaxMonthlyDebitCount:
+		return violation(types.FailureCodeMonthlyCountLimit, "monthly debit count limit of %d reached",
+			*limits.MaxMonthlyDebitCount)
+	}
+	return nil
+}
+
+// Check evaluates a debit of amountCents against the account's effective
+// limits and its usage in the windows containing at
+func Check(ctx context.Context, q queryer, accountID uuid.UUID, amountCents int64, at time.Time, statuses []types.TransactionStatus, exclude uuid.UUID) (*types.LimitViolation, error) {
+	limits, err := Effective(ctx, q, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if limits == (types.TransactionLimits{}) {
+		return nil, nil
+	}
+	usage, err := Usage(ctx, q, accountID, at, statuses, exclude)
+	if err != nil {
+		return nil, err
+	}
+	return Evaluate(limits, usage, amountCents), nil
+}
+
+// Headroom returns what may still be debited within each limit, never below zero
+func Headroom(limits types.TransactionLimits, usage types.LimitUsage) types.LimitHeadroom {
+	var headroom types.LimitHeadroom
+	if limits.MaxSingleDebitCents != nil {
+		headroom.SingleDebitCents = remaining(*limits.MaxSingleDebitCents, 0)
+	}
+	if limits.MaxDailyDebitCents != nil {
+		headroom.DailyDebitCents = remaining(*limits.MaxDailyDebitCents, usage.DailyDebitCents)
+	}
+	if limits.MaxMonthlyDebitCents != nil {
+		headroom.MonthlyDebitCents = remaining(*limits.MaxMonthlyDebitCents, usage.MonthlyDebitCents)
+	}
+	if limits.MaxDailyDebitCount != nil {
+		count := int(*remaining(int64(*limits.MaxDailyDebitCount), int64(usage.DailyDebitCount)))
+		headroom.DailyDebitCount = &count
+	}
+	if limits.MaxMonthlyDebitCount != nil {
+		count := int(*remaining(int64(*limits.MaxMonthlyDebitCount), int64(usage.MonthlyDebitCount)))
+		headroom.MonthlyDebitCount = &count
+	}
+
+	// No single debit can exceed what is left of the daily and monthly totals,
+	// and none fits once a count limit is reached
+	for _, total := range []*int64{headroom.DailyDebitCents, headroom.MonthlyDebitCents} {
+		if total != nil && (headroom.SingleDebitCents == nil || *total < *headroom.SingleDebitCents) {
+			single := *total
+			headroom.SingleDebitCents = &single
+		}
+	}
+	for _, count := range []*int{headroom.DailyDebitCount, headroom.MonthlyDebitCount} {
+		if count != nil && *count == 0 {
+			headroom.SingleDebitCents = remaining(0, 0)
+		}
+	}
+	return headroom
+}
+
+func remaining(limit, used int64) *int64 {
+	left := limit - used
+	if left < 0 {
+		left = 0
+	}
+	return &left
+}
+
+func violation(code types.FailureCode, format string, args ...interface{}) *types.LimitViolation {
+	return &types.LimitViolation{Code: code, Reason: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,239 @@
+package limits
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yash/transaction-system/shared/types"
+)
+
+func cents(v int64) *int64 { return &v }
+
+func count(v int) *int { return &v }
+
+func TestEvaluate(t *testing.T) {
+	all := types.TransactionLimits{
+		MaxSingleDebitCents:  cents(10000),
+		MaxDailyDebitCents:   cents(20000),
+		MaxMonthlyDebitCents: cents(50000),
+		MaxDailyDebitCount:   count(3),
+		MaxMonthlyDebitCount: count(10),
+	}
+	tests := []struct {
+		name   string
+		limits types.TransactionLimits
+		usage  types.LimitUsage
+		amount int64
+		want   types.FailureCode
+	}{
+		{name: "no limits", amount: 1 << 40},
+		{name: "within every limit", limits: all, amount: 10000},
+		{name: "single limit", limits: all, amount: 10001, want: types.FailureCodeSingleDebitLimit},
+		{
+			name:   "daily amount reached exactly",
+			limits: all,
+			usage:  types.LimitUsage{DailyDebitCents: 15000, MonthlyDebitCents: 15000},
+			amount: 5000,
+		},
+		{
+			name:   "daily amount",
+			limits: all,
+			usage:  types.LimitUsage{DailyDebitCents: 15000, MonthlyDebitCents: 15000},
+			amount: 5001,
+			want:   types.FailureCodeDailyAmountLimit,
+		},
+		{
+			name:   "monthly amount",
+			limits: all,
+			usage:  types.LimitUsage{MonthlyDebitCents: 45000},
+			amount: 5001,
+			want:   types.FailureCodeMonthlyAmountLimit,
+		},
+		{
+			name:   "daily count",
+			limits: all,
+			usage:  types.LimitUsage{DailyDebitCount: 3, MonthlyDebitCount: 3},
+			amount: 1,
+			want:   types.FailureCodeDailyCountLimit,
+		},
+		{
+			name:   "monthly count",
+			limits: all,
+			usage:  types.LimitUsage{MonthlyDebitCount: 10},
+			amount: 1,
+			want:   types.FailureCodeMonthlyCountLimit,
+		},
+		{
+			name:   "single limit is checked before the daily totals",
+			limits: all,
+			usage:  types.LimitUsage{DailyDebitCents: 20000, DailyDebitCount: 3},
+			amount: 10001,
+			want:   types.FailureCodeSingleDebitLimit,
+		},
+		{
+			name:   "amounts are checked before counts",
+			limits: all,
+			usage:  types.LimitUsage{MonthlyDebitCents: 50000, DailyDebitCount: 3},
+			amount: 1,
+			want:   types.FailureCodeMonthlyAmountLimit,
+		},
+		{
+			name:   "unset limits are skipped",
+			limits: types.TransactionLimits{MaxMonthlyDebitCount: count(1)},
+			usage:  types.LimitUsage{DailyDebitCents: 1 << 40},
+			amount: 1 << 40,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Evaluate(tt.limits, tt.usage, tt.amount)
+			if tt.want == "" {
+				if got != nil {
+					t.Fatalf("Evaluate() = %+v; want nil", got)
+				}
+				return
+			}
+			if got == nil || got.Code != tt.want {
+				t.Fatalf("Evaluate() = %+v; want code %s", got, tt.want)
+			}
+			if got.Reason == "" {
+				t.Errorf("Evaluate() has no reason")
+			}
+		})
+	}
+}
+
+func TestHeadroom(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits types.TransactionLimits
+		usage  types.LimitUsage
+		want   types.LimitHeadroom
+	}{
+		{name: "no limits"},
+		{
+			name:   "single limit alone",
+			limits: types.TransactionLimits{MaxSingleDebitCents: cents(10000)},
+			usage:  types.LimitUsage{DailyDebitCents: 5000, DailyDebitCount: 2},
+			want:   types.LimitHeadroom{SingleDebitCents: cents(10000)},
+		},
+		{
+			name: "single clamped to the daily remainder",
+			limits: types.TransactionLimits{
+				MaxSingleDebitCents:  cents(10000),
+				MaxDailyDebitCents:   cents(20000),
+				MaxMonthlyDebitCents: cents(50000),
+			},
+			usage: types.LimitUsage{DailyDebitCents: 12500, MonthlyDebitCents: 30000},
+			want: types.LimitHeadroom{
+				SingleDebitCents:  cents(7500),
+				DailyDebitCents:   cents(7500),
+				MonthlyDebitCents: cents(20000),
+			},
+		},
+		{
+			name: "single clamped to the monthly remainder",
+			limits: types.TransactionLimits{
+				MaxSingleDebitCents:  cents(10000),
+				MaxDailyDebitCents:   cents(20000),
+				MaxMonthlyDebitCents: cents(50000),
+			},
+			usage: types.LimitUsage{MonthlyDebitCents: 48000},
+			want: types.LimitHeadroom{
+				SingleDebitCents:  cents(2000),
+				DailyDebitCents:   cents(20000),
+				MonthlyDebitCents: cents(2000),
+			},
+		},
+		{
+			name:   "single derived from a total without a single limit",
+			limits: types.TransactionLimits{MaxDailyDebitCents: cents(20000)},
+			usage:  types.LimitUsage{DailyDebitCents: 5000},
+			want:   types.LimitHeadroom{SingleDebitCents: cents(15000), DailyDebitCents: cents(15000)},
+		},
+		{
+			name:   "usage over a limit is not negative",
+			limits: types.TransactionLimits{MaxDailyDebitCents: cents(20000), MaxDailyDebitCount: count(2)},
+			usage:  types.LimitUsage{DailyDebitCents: 25000, DailyDebitCount: 4},
+			want: types.LimitHeadroom{
+				SingleDebitCents: cents(0),
+				DailyDebitCents:  cents(0),
+				DailyDebitCount:  count(0),
+			},
+		},
+		{
+			name: "no single debit fits once a count is reached",
+			limits: types.TransactionLimits{
+				MaxSingleDebitCents:  cents(10000),
+				MaxMonthlyDebitCount: count(10),
+			},
+			usage: types.LimitUsage{MonthlyDebitCount: 10},
+			want:  types.LimitHeadroom{SingleDebitCents: cents(0), MonthlyDebitCount: count(0)},
+		},
+		{
+			name:   "count headroom left",
+			limits: types.TransactionLimits{MaxDailyDebitCount: count(3), MaxMonthlyDebitCount: count(10)},
+			usage:  types.LimitUsage{DailyDebitCount: 1, MonthlyDebitCount: 7},
+			want:   types.LimitHeadroom{DailyDebitCount: count(2), MonthlyDebitCount: count(3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Headroom(tt.limits, tt.usage)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Headroom() = %s; want %s", formatHeadroom(got), formatHeadroom(tt.want))
+			}
+		})
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tier := types.TransactionLimits{MaxSingleDebitCents: cents(10000), MaxDailyDebitCount: count(5)}
+	override := types.TransactionLimits{MaxSingleDebitCents: cents(2500), MaxMonthlyDebitCents: cents(90000)}
+
+	got := Merge(override, tier)
+	want := types.TransactionLimits{
+		MaxSingleDebitCents:  cents(2500),
+		MaxMonthlyDebitCents: cents(90000),
+		MaxDailyDebitCount:   count(5),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %+v; want %+v", got, want)
+	}
+}
+
+func TestWindows(t *testing.T) {
+	tests := []struct {
+		at    time.Time
+		day   time.Time
+		month time.Time
+	}{
+		{
+			at:    time.Date(2024, time.February, 29, 23, 59, 59, 0, time.UTC),
+			day:   time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+			month: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			// 2024-03-01 02:00 UTC
+			at:    time.Date(2024, time.February, 29, 21, 0, 0, 0, time.FixedZone("UTC-5", -5*60*60)),
+			day:   time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			month: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		day, month := Windows(tt.at)
+		if !day.Equal(tt.day) || !month.Equal(tt.month) {
+			t.Errorf("Windows(%v) = %v, %v; want %v, %v", tt.at, day, month, tt.day, tt.month)
+		}
+	}
+}
+
+func formatHeadroom(h types.LimitHeadroom) string {
+	data, _ := json.Marshal(h)
+	return string(data)
+}
@@ -0,0 +1,36 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/tenant"
+)
+
+// Write inserts a PENDING outbox event inside the caller's DB transaction so the
+// event is published only if the surrounding state change commits. The event
+// belongs to the tenant of ctx.
+func Write(ctx context.Context, tx *sql.Tx, aggregateType string, aggregateID uuid.UUID, eventType string, payload interface{}) error {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	query := `
+		INSERT INTO outbox_events (id, tenant_id, aggregate_type, aggregate_id, event_type, payload, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err = tx.ExecContext(ctx, query,
+		uuid.New(), tenant.FromContext(ctx), aggregateType, aggregateID, eventType,
+		payloadBytes, "PENDING", time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create outbox event: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package recurrence
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yash/transaction-system/shared/types"
+)
+
+// weekdays maps RRULE day codes to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Parse parses the supported RRULE subset: FREQ (DAILY, WEEKLY, MONTHLY),
+// INTERVAL, BYDAY (WEEKLY only), BYMONTHDAY (MONTHLY only, one day), COUNT
+// and UNTIL. An "RRULE:" prefix is accepted.
+func Parse(rrule string) (types.RecurrenceRule, error) {
+	var rule types.RecurrenceRule
+	rrule = strings.TrimPrefix(strings.TrimSpace(rrule), "RRULE:")
+
+	for _, part := range strings.Split(rrule, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return rule, fmt.Errorf("invalid RRULE part %q", part)
+		}
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Frequency = types.RecurrenceFrequency(strings.ToUpper(value))
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(value)
+		case "BYDAY":
+			rule.Weekdays = strings.Split(strings.ToUpper(value), ",")
+		case "BYMONTHDAY":
+			rule.MonthDay, err = strconv.Atoi(value)
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(value)
+		case "UNTIL":
+			var until time.Time
+			until, err = time.Parse("20060102T150405Z", value)
+			if err != nil {
+				until, err = time.Parse("20060102", value)
+			}
+			rule.Until = &until
+		default:
+			return rule, fmt.Errorf("unsupported RRULE part %s", key)
+		}
+		if err != nil {
+			return rule, fmt.Errorf("invalid RRULE %s: %q", key, value)
+		}
+	}
+
+	return rule, Validate(rule)
+}
+
+// Format renders a rule as an RRULE string
+func Format(rule types.RecurrenceRule) string {
+	parts := []string{"FREQ=" + string(rule.Frequency)}
+	if rule.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(rule.Interval))
+	}
+	if len(rule.Weekdays) > 0 {
+		parts = append(parts, "BYDAY="+strings.Join(rule.Weekdays, ","))
+	}
+	if rule.MonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(rule.MonthDay))
+	}
+	if rule.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(rule.Count))
+	}
+	if rule.Until != nil {
+		parts = append(parts, "UNTIL="+rule.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Validate checks that a rule is complete and within the supported subset
+func Validate(rule types.RecurrenceRule) error {
+	switch rule.Frequency {
+	case types.RecurrenceDaily, types.RecurrenceWeekly, types.RecurrenceMonthly:
+	default:
+		return fmt.Errorf("frequency must be DAILY, WEEKLY or MONTHLY")
+	}
+	if rule.Interval < 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	if rule.Count < 0 {
+		return fmt.Errorf("count must be positive")
+	}
+	if len(rule.Weekdays) > 0 {
+		if rule.Frequency != types.RecurrenceWeekly {
+			return fmt.Errorf("weekdays require WEEKLY frequency")
+		}
+		for _, day := range rule.Weekdays {
+			if _, ok := weekdays[day]; !ok {
+				return fmt.Errorf("invalid weekday %q", day)
+			}
+		}
+	}
+	if rule.MonthDay != 0 {
+		if rule.Frequency != types.RecurrenceMonthly {
+			return fmt.Errorf("month_day requires MONTHLY frequency")
+		}
+		if rule.MonthDay < -1 || rule.MonthDay > 31 {
+			return fmt.Errorf("month_day must be between 1 and 31, or -1 for the last day")
+		}
+	}
+	return nil
+}
+
+// Next returns the first occurrence of a rule anchored at start that falls
+// strictly after the given time, or false once the rule has ended by UNTIL.
+// COUNT is left to the caller, which knows how many occurrences have run.
+func Next(rule types.RecurrenceRule, start, after time.Time) (time.Time, bool) {
+	start = start.UTC()
+	after = after.UTC()
+	if after.Before(start) {
+		after = start.Add(-time.Nanosecond)
+	}
+
+	interval := rule.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	timeOfDay := start.Sub(day)
+
+	var next time.Time
+	switch rule.Frequency {
+	case types.RecurrenceDaily:
+		step := time.Duration(interval) * 24 * time.Hour
+		k := int(after.Sub(start) / step)
+		for next = start.AddDate(0, 0, k*interval); !next.After(after); k++ {
+			next = start.AddDate(0, 0, (k+1)*interval)
+		}
+
+	case types.RecurrenceWeekly:
+		offsets := weekdayOffsets(rule.Weekdays, start.Weekday())
+		weekStart := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		k := int(after.Sub(weekStart)/(7*24*time.Hour)) / interval
+	weeks:
+		for ; ; k++ {
+			base := weekStart.AddDate(0, 0, 7*interval*k)
+			for _, offset := range offsets {
+				candidate := base.AddDate(0, 0, offset).Add(timeOfDay)
+				if !candidate.Before(start) && candidate.After(after) {
+					next = candidate
+					break weeks
+				}
+			}
+		}
+
+	case types.RecurrenceMonthly:
+		monthDay := rule.MonthDay
+		if monthDay == 0 {
+			monthDay = start.Day()
+		}
+		months := (after.Year()-start.Year())*12 + int(after.Month()-start.Month())
+		k := months / interval
+		if k < 0 {
+			k = 0
+		}
+		for ; ; k++ {
+			first := time.Date(start.Year(), start.Month()+time.Month(k*interval), 1, 0, 0, 0, 0, time.UTC)
+			lastDay := first.AddDate(0, 1, -1).Day()
+			d := monthDay
+			if d == -1 || d > lastDay {
+				d = lastDay
+			}
+			candidate := first.AddDate(0, 0, d-1).Add(timeOfDay)
+			if !candidate.Before(start) && candidate.After(after) {
+				next = candidate
+				break
+			}
+		}
+
+	default:
+		return time.Time{}, false
+	}
+
+	if rule.Until != nil && next.After(*rule.Until) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+// weekdayOffsets returns the sorted day offsets from Monday of the given RRULE
+// day codes, defaulting to the anchor weekday
+func weekdayOffsets(codes []string, anchor time.Weekday) []int {
+	if len(codes) == 0 {
+		return []int{(int(anchor) + 6) % 7}
+	}
+	offsets := make([]int, 0, len(codes))
+	for _, code := range codes {
+		offsets = append(offsets, (int(weekdays[code])+6)%7)
+	}
+	sort.Ints(offsets)
+	return offsets
+}
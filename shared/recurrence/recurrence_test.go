@@ -0,0 +1,179 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yash/transaction-system/shared/types"
+)
+
+func date(year int, month time.Month, day, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestNext(t *testing.T) {
+	until := date(2024, time.March, 1, 0)
+	tests := []struct {
+		name  string
+		rrule string
+		start time.Time
+		after time.Time
+		want  time.Time
+		ok    bool
+	}{
+		{
+			name:  "first occurrence is the start",
+			rrule: "FREQ=DAILY",
+			start: date(2024, time.January, 10, 9),
+			after: date(2024, time.January, 1, 0),
+			want:  date(2024, time.January, 10, 9),
+			ok:    true,
+		},
+		{
+			name:  "daily with interval",
+			rrule: "FREQ=DAILY;INTERVAL=3",
+			start: date(2024, time.January, 10, 9),
+			after: date(2024, time.January, 10, 9),
+			want:  date(2024, time.January, 13, 9),
+			ok:    true,
+		},
+		{
+			name:  "weekly on several days",
+			rrule: "FREQ=WEEKLY;BYDAY=MO,FR",
+			start: date(2024, time.January, 1, 9), // a Monday
+			after: date(2024, time.January, 2, 0),
+			want:  date(2024, time.January, 5, 9),
+			ok:    true,
+		},
+		{
+			name:  "weekly wraps to the next week",
+			rrule: "FREQ=WEEKLY;BYDAY=MO,FR",
+			start: date(2024, time.January, 1, 9),
+			after: date(2024, time.January, 5, 9),
+			want:  date(2024, time.January, 8, 9),
+			ok:    true,
+		},
+		{
+			name:  "monthly on the start day",
+			rrule: "FREQ=MONTHLY",
+			start: date(2024, time.January, 15, 9),
+			after: date(2024, time.January, 15, 9),
+			want:  date(2024, time.February, 15, 9),
+			ok:    true,
+		},
+		{
+			name:  "monthly start on the 31st clamps to the end of February",
+			rrule: "FREQ=MONTHLY",
+			start: date(2024, time.January, 31, 9),
+			after: date(2024, time.January, 31, 9),
+			want:  date(2024, time.February, 29, 9),
+			ok:    true,
+		},
+		{
+			name:  "monthly clamping does not carry over to longer months",
+			rrule: "FREQ=MONTHLY",
+			start: date(2024, time.January, 31, 9),
+			after: date(2024, time.February, 29, 9),
+			want:  date(2024, time.March, 31, 9),
+			ok:    true,
+		},
+		{
+			name:  "BYMONTHDAY=31 clamps in a 30 day month",
+			rrule: "FREQ=MONTHLY;BYMONTHDAY=31",
+			start: date(2024, time.April, 1, 9),
+			after: date(2024, time.April, 1, 9),
+			want:  date(2024, time.April, 30, 9),
+			ok:    true,
+		},
+		{
+			name:  "BYMONTHDAY=-1 is the last day of a non-leap February",
+			rrule: "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: date(2023, time.January, 31, 9),
+			after: date(2023, time.January, 31, 9),
+			want:  date(2023, time.February, 28, 9),
+			ok:    true,
+		},
+		{
+			name:  "BYMONTHDAY before the start day begins next month",
+			rrule: "FREQ=MONTHLY;BYMONTHDAY=5",
+			start: date(2024, time.January, 20, 9),
+			after: date(2024, time.January, 1, 0),
+			want:  date(2024, time.February, 5, 9),
+			ok:    true,
+		},
+		{
+			name:  "monthly with interval crosses the year",
+			rrule: "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=-1",
+			start: date(2024, time.November, 1, 9),
+			after: date(2024, time.November, 30, 9),
+			want:  date(2025, time.January, 31, 9),
+			ok:    true,
+		},
+		{
+			name:  "ends after UNTIL",
+			rrule: "FREQ=MONTHLY;UNTIL=" + until.Format("20060102T150405Z"),
+			start: date(2024, time.January, 15, 9),
+			after: date(2024, time.February, 15, 9),
+			ok:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rrule)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.rrule, err)
+			}
+			got, ok := Next(rule, tt.start, tt.after)
+			if ok != tt.ok || !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, %v; want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		rrule   string
+		want    string
+		wantErr bool
+	}{
+		{rrule: "RRULE:FREQ=weekly;BYDAY=mo,we", want: "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{rrule: "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=12", want: "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=12"},
+		{rrule: "FREQ=DAILY;INTERVAL=1", want: "FREQ=DAILY"},
+		{rrule: "FREQ=DAILY;UNTIL=20240301", want: "FREQ=DAILY;UNTIL=20240301T000000Z"},
+		{rrule: "FREQ=YEARLY", wantErr: true},
+		{rrule: "FREQ=DAILY;BYDAY=MO", wantErr: true},
+		{rrule: "FREQ=WEEKLY;BYMONTHDAY=1", wantErr: true},
+		{rrule: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{rrule: "FREQ=MONTHLY;BYMONTHDAY=-2", wantErr: true},
+		{rrule: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{rrule: "FREQ=DAILY;COUNT=two", wantErr: true},
+		{rrule: "FREQ=DAILY;BYHOUR=9", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rrule, func(t *testing.T) {
+			rule, err := Parse(tt.rrule)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = %+v; want an error", tt.rrule, rule)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.rrule, err)
+			}
+			if got := Format(rule); got != tt.want {
+				t.Errorf("Format(Parse(%q)) = %q; want %q", tt.rrule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextUnknownFrequency(t *testing.T) {
+	start := date(2024, time.January, 1, 0)
+	if got, ok := Next(types.RecurrenceRule{Frequency: "YEARLY"}, start, start); ok {
+		t.Errorf("Next() = %v, true; want false", got)
+	}
+}
@@ -0,0 +1,36 @@
+package risk
+
+import (
+	"context"
+
+	"github.com/yash/transaction-system/shared/types"
+)
+
+// Engine decides whether a transaction may proceed by evaluating the rules
+// of its source
+type Engine struct {
+	source Source
+}
+
+// NewEngine creates a new rules engine
+func NewEngine(source Source) *Engine {
+	return &Engine{source: source}
+}
+
+// Evaluate gathers the facts the rules need and returns the decision of the
+// first matching rule. With no rules every transaction is allowed.
+func (e *Engine) Evaluate(ctx context.Context, q queryer, payload types.TransactionCreatedPayload) (Decision, error) {
+	rules, err := e.source.Rules(ctx)
+	if err != nil {
+		return Decision{}, err
+	}
+	if len(rules) == 0 {
+		return Decision{Action: types.RiskActionAllow}, nil
+	}
+
+	facts, err := Facts(ctx, q, payload, rules)
+	if err != nil {
+		return Decision{}, err
+	}
+	return Evaluate(rules, facts), nil
+}
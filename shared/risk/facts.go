@@ -0,0 +1,140 @@
+package risk
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yash/transaction-system/shared/types"
+)
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Facts gathers what rules may test about a transaction: its payload and
+// metadata, its account, the account's history, and its velocity over the
+// last hour and day. The transaction itself is never counted. Numbers are
+// float64 so they compare with JSON rule values. Groups no rule refers to
+// are not queried.
+func Facts(ctx context.Context, q queryer, payload types.TransactionCreatedPayload, rules []types.RiskRule) (map[string]interface{}, error) {
+	facts := map[string]interface{}{
+		"type":         string(payload.Type),
+		"amount_cents": float64(payload.AmountCents),
+		"currency":     payload.Currency,
+	}
+	if len(payload.Metadata) > 0 {
+		var metadata map[string]interface{}
+		if err := json.Unmarshal(payload.Metadata, &metadata); err == nil {
+			flatten("metadata", metadata, facts)
+		}
+	}
+
+	if uses(rules, "account.") {
+		var kind, tier, status string
+		var balance, held int64
+		var ageHours float64
+		accountQuery := `
+			SELECT kind, tier, status, balance_cents, held_cents,
+			       EXTRACT(EPOCH FROM NOW() - created_at) / 3600
+			FROM accounts
+			WHERE id = $1
+		`
+		err := q.QueryRowContext(ctx, accountQuery, payload.AccountID).Scan(&kind, &tier, &status, &balance, &held, &ageHours)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load account facts: %w", err)
+		}
+		facts["account.kind"] = kind
+		facts["account.tier"] = tier
+		facts["account.status"] = status
+		facts["account.balance_cents"] = float64(balance)
+		facts["account.available_cents"] = float64(balance - held)
+		facts["account.age_hours"] = ageHours
+	}
+
+	if uses(rules, "history.") {
+		var processed, failed, maxDebit int64
+		historyQuery := `
+			SELECT COUNT(*) FILTER (WHERE status = 'PROCESSED'),
+			       COUNT(*) FILTER (WHERE status = 'FAILED'),
+			       COALESCE(MAX(COALESCE(settled_amount_cents, amount_cents))
+			                FILTER (WHERE status = 'PROCESSED' AND type = 'DEBIT' AND reverses_transaction_id IS NULL), 0)
+			FROM transactions
+			WHERE account_id = $1 AND id <> $2
+		`
+		err := q.QueryRowContext(ctx, historyQuery, payload.AccountID, payload.TransactionID).Scan(&processed, &failed, &maxDebit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load history facts: %w", err)
+		}
+		facts["history.processed_count"] = float64(processed)
+		facts["history.failed_count"] = float64(failed)
+		facts["history.max_debit_cents"] = float64(maxDebit)
+	}
+
+	if uses(rules, "velocity.") {
+		// Counts every attempt that was not cancelled, including ones still in
+		// flight, so a burst is seen before it is processed
+		var count1h, count24h, debit1h, debit24h, failed24h, currencies24h int64
+		velocityQuery := `
+			SELECT COUNT(*) FILTER (WHERE created_at >= NOW() - INTERVAL '1 hour' AND status <> 'FAILED'),
+			       COUNT(*) FILTER (WHERE status <> 'FAILED'),
+			       COALESCE(SUM(COALESCE(settled_amount_cents, amount_cents))
+			                FILTER (WHERE created_at >= NOW() - INTERVAL '1 hour' AND status <> 'FAILED' AND is_debit), 0),
+			       COALESCE(SUM(COALESCE(settled_amount_cents, amount_cents))
+			                FILTER (WHERE status <> 'FAILED' AND is_debit), 0),
+			       COUNT(*) FILTER (WHERE status = 'FAILED'),
+			       COUNT(DISTINCT currency)
+			FROM (
+				SELECT created_at, status, currency, amount_cents, settled_amount_cents,
+				       type = 'DEBIT' AND reverses_transaction_id IS NULL AS is_debit
+				FROM transactions
+				WHERE account_id = $1 AND id <> $2 AND status NOT IN ('SCHEDULED', 'CANCELLED')
+				  AND created_at >= NOW() - INTERVAL '24 hours'
+			) recent
+		`
+		err := q.QueryRowContext(ctx, velocityQuery, payload.AccountID, payload.TransactionID).Scan(
+			&count1h, &count24h, &debit1h, &debit24h, &failed24h, &currencies24h,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load velocity facts: %w", err)
+		}
+		facts["velocity.count_1h"] = float64(count1h)
+		facts["velocity.count_24h"] = float64(count24h)
+		facts["velocity.debit_cents_1h"] = float64(debit1h)
+		facts["velocity.debit_cents_24h"] = float64(debit24h)
+		facts["velocity.failed_count_24h"] = float64(failed24h)
+		facts["velocity.distinct_currencies_24h"] = float64(currencies24h)
+	}
+
+	return facts, nil
+}
+
+// uses reports whether any enabled rule tests a field with the given prefix
+func uses(rules []types.RiskRule, prefix string) bool {
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		for _, c := range rule.Conditions {
+			if strings.HasPrefix(c.Field, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// flatten adds the scalar values of a JSON object to facts under dotted keys
+func flatten(prefix string, value map[string]interface{}, facts map[string]interface{}) {
+	for key, v := range value {
+		name := prefix + "." + key
+		if nested, ok := v.(map[string]interface{}); ok {
+			flatten(name, nested, facts)
+			continue
+		}
+		facts[name] = v
+	}
+}
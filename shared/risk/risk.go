@@ -0,0 +1,194 @@
+package risk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yash/transaction-system/shared/types"
+)
+
+// Fields are the facts a condition may test besides "metadata.<key>"
+var Fields = map[string]bool{
+	"type":                             true,
+	"amount_cents":                     true,
+	"currency":                         true,
+	"account.kind":                     true,
+	"account.tier":                     true,
+	"account.status":                   true,
+	"account.balance_cents":            true,
+	"account.available_cents":          true,
+	"account.age_hours":                true,
+	"history.processed_count":          true,
+	"history.failed_count":             true,
+	"history.max_debit_cents":          true,
+	"velocity.count_1h":                true,
+	"velocity.count_24h":               true,
+	"velocity.debit_cents_1h":          true,
+	"velocity.debit_cents_24h":         true,
+	"velocity.failed_count_24h":        true,
+	"velocity.distinct_currencies_24h": true,
+}
+
+// Decision is the outcome of evaluating the rules against a transaction.
+// Rule is empty when no rule matched.
+type Decision struct {
+	Action types.RiskAction
+	Rule   string
+	Reason string
+}
+
+// Validate checks that a rule is complete and every condition can be evaluated
+func Validate(rule types.RiskRule) error {
+	if strings.TrimSpace(rule.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !rule.Action.Valid() {
+		return fmt.Errorf("action must be ALLOW, DENY or REVIEW")
+	}
+	if len(rule.Conditions) == 0 {
+		return fmt.Errorf("at least one condition is required")
+	}
+	for i, c := range rule.Conditions {
+		if err := validateCondition(c); err != nil {
+			return fmt.Errorf("condition %d: %v", i, err)
+		}
+	}
+	return nil
+}
+
+func validateCondition(c types.RiskCondition) error {
+	if !Fields[c.Field] && !(strings.HasPrefix(c.Field, "metadata.") && len(c.Field) > len("metadata.")) {
+		return fmt.Errorf("unknown field %q", c.Field)
+	}
+	switch c.Op {
+	case types.RiskOpEq, types.RiskOpNe:
+		if !isScalar(c.Value) {
+			return fmt.Errorf("%s requires a string, number or boolean value", c.Op)
+		}
+	case types.RiskOpGt, types.RiskOpGte, types.RiskOpLt, types.RiskOpLte:
+		if _, ok := c.Value.(float64); !ok {
+			return fmt.Errorf("%s requires a number value", c.Op)
+		}
+	case types.RiskOpIn, types.RiskOpNotIn:
+		values, ok := c.Value.([]interface{})
+		if !ok || len(values) == 0 {
+			return fmt.Errorf("%s requires a non-empty list value", c.Op)
+		}
+		for _, v := range values {
+			if !isScalar(v) {
+				return fmt.Errorf("%s list values must be strings, numbers or booleans", c.Op)
+			}
+		}
+	case types.RiskOpExists:
+		if _, ok := c.Value.(bool); !ok {
+			return fmt.Errorf("exists requires a boolean value")
+		}
+	default:
+		return fmt.Errorf("unknown op %q", c.Op)
+	}
+	return nil
+}
+
+// Sort orders rules by ascending priority, then name, which is the order
+// Evaluate applies them in
+func Sort(rules []types.RiskRule) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority < rules[j].Priority
+		}
+		return rules[i].Name < rules[j].Name
+	})
+}
+
+// Evaluate applies sorted rules to facts. The first enabled rule whose
+// conditions all hold decides; a transaction no rule matches is allowed.
+func Evaluate(rules []types.RiskRule, facts map[string]interface{}) Decision {
+	for _, rule := range rules {
+		if !rule.Enabled || !matches(rule, facts) {
+			continue
+		}
+		reason := fmt.Sprintf("matched risk rule %s", rule.Name)
+		if rule.Description != "" {
+			reason += ": " + rule.Description
+		}
+		return Decision{Action: rule.Action, Rule: rule.Name, Reason: reason}
+	}
+	return Decision{Action: types.RiskActionAllow}
+}
+
+// matches reports whether every condition of a rule holds
+func matches(rule types.RiskRule, facts map[string]interface{}) bool {
+	for _, c := range rule.Conditions {
+		if !holds(c, facts) {
+			return false
+		}
+	}
+	return true
+}
+
+// holds evaluates one condition. A missing fact only satisfies exists=false;
+// values of different kinds are never equal.
+func holds(c types.RiskCondition, facts map[string]interface{}) bool {
+	fact, ok := facts[c.Field]
+	if ok && fact == nil {
+		ok = false
+	}
+	if c.Op == types.RiskOpExists {
+		want, _ := c.Value.(bool)
+		return ok == want
+	}
+	if !ok {
+		return false
+	}
+
+	switch c.Op {
+	case types.RiskOpEq:
+		return equal(fact, c.Value)
+	case types.RiskOpNe:
+		return !equal(fact, c.Value)
+	case types.RiskOpIn, types.RiskOpNotIn:
+		values, _ := c.Value.([]interface{})
+		found := false
+		for _, v := range values {
+			if equal(fact, v) {
+				found = true
+				break
+			}
+		}
+		return found == (c.Op == types.RiskOpIn)
+	}
+
+	a, aok := fact.(float64)
+	b, bok := c.Value.(float64)
+	if !aok || !bok {
+		return false
+	}
+	switch c.Op {
+	case types.RiskOpGt:
+		return a > b
+	case types.RiskOpGte:
+		return a >= b
+	case types.RiskOpLt:
+		return a < b
+	case types.RiskOpLte:
+		return a <= b
+	}
+	return false
+}
+
+func equal(a, b interface{}) bool {
+	if !isScalar(a) || !isScalar(b) {
+		return false
+	}
+	return a == b
+}
+
+func isScalar(v interface{}) bool {
+	switch v.(type) {
+	case string, float64, bool:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,165 @@
+package risk
+
+import (
+	"testing"
+
+	"github.com/yash/transaction-system/shared/types"
+)
+
+func rule(name string, priority int, action types.RiskAction, conditions ...types.RiskCondition) types.RiskRule {
+	return types.RiskRule{Name: name, Priority: priority, Action: action, Conditions: conditions, Enabled: true}
+}
+
+func cond(field string, op types.RiskOperator, value interface{}) types.RiskCondition {
+	return types.RiskCondition{Field: field, Op: op, Value: value}
+}
+
+func TestEvaluatePriority(t *testing.T) {
+	large := cond("amount_cents", types.RiskOpGte, float64(100000))
+	disabled := rule("a-disabled", 0, types.RiskActionDeny, large)
+	disabled.Enabled = false
+
+	tests := []struct {
+		name     string
+		rules    []types.RiskRule
+		wantRule string
+		want     types.RiskAction
+	}{
+		{
+			name:  "no rules allows",
+			rules: nil,
+			want:  types.RiskActionAllow,
+		},
+		{
+			name: "lowest priority wins regardless of input order",
+			rules: []types.RiskRule{
+				rule("review-large", 20, types.RiskActionReview, large),
+				rule("deny-large", 10, types.RiskActionDeny, large),
+			},
+			wantRule: "deny-large",
+			want:     types.RiskActionDeny,
+		},
+		{
+			name: "an ALLOW rule can take precedence over a DENY",
+			rules: []types.RiskRule{
+				rule("deny-large", 10, types.RiskActionDeny, large),
+				rule("allow-gold", 5, types.RiskActionAllow, large, cond("account.tier", types.RiskOpEq, "GOLD")),
+			},
+			wantRule: "allow-gold",
+			want:     types.RiskActionAllow,
+		},
+		{
+			name: "equal priorities are ordered by name",
+			rules: []types.RiskRule{
+				rule("b-review", 10, types.RiskActionReview, large),
+				rule("a-deny", 10, types.RiskActionDeny, large),
+			},
+			wantRule: "a-deny",
+			want:     types.RiskActionDeny,
+		},
+		{
+			name: "disabled rules are skipped",
+			rules: []types.RiskRule{
+				disabled,
+				rule("b-review", 10, types.RiskActionReview, large),
+			},
+			wantRule: "b-review",
+			want:     types.RiskActionReview,
+		},
+		{
+			name: "a rule matches only when every condition holds",
+			rules: []types.RiskRule{
+				rule("deny-eur", 1, types.RiskActionDeny, large, cond("currency", types.RiskOpEq, "EUR")),
+				rule("review-large", 2, types.RiskActionReview, large),
+			},
+			wantRule: "review-large",
+			want:     types.RiskActionReview,
+		},
+	}
+
+	facts := map[string]interface{}{
+		"amount_cents": float64(250000),
+		"currency":     "USD",
+		"account.tier": "GOLD",
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := append([]types.RiskRule(nil), tt.rules...)
+			Sort(rules)
+			got := Evaluate(rules, facts)
+			if got.Action != tt.want || got.Rule != tt.wantRule {
+				t.Errorf("Evaluate() = %s by %q; want %s by %q", got.Action, got.Rule, tt.want, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestConditions(t *testing.T) {
+	facts := map[string]interface{}{
+		"amount_cents":      float64(5000),
+		"currency":          "USD",
+		"metadata.country":  "FR",
+		"metadata.verified": true,
+		"account.tier":      nil,
+	}
+	tests := []struct {
+		name      string
+		condition types.RiskCondition
+		want      bool
+	}{
+		{name: "eq string", condition: cond("currency", types.RiskOpEq, "USD"), want: true},
+		{name: "eq across kinds", condition: cond("amount_cents", types.RiskOpEq, "5000"), want: false},
+		{name: "ne", condition: cond("currency", types.RiskOpNe, "EUR"), want: true},
+		{name: "gt", condition: cond("amount_cents", types.RiskOpGt, float64(5000)), want: false},
+		{name: "gte", condition: cond("amount_cents", types.RiskOpGte, float64(5000)), want: true},
+		{name: "lt", condition: cond("amount_cents", types.RiskOpLt, float64(5001)), want: true},
+		{name: "lte", condition: cond("amount_cents", types.RiskOpLte, float64(4999)), want: false},
+		{name: "compare a string", condition: cond("currency", types.RiskOpGt, float64(0)), want: false},
+		{name: "in", condition: cond("metadata.country", types.RiskOpIn, []interface{}{"DE", "FR"}), want: true},
+		{name: "not_in", condition: cond("metadata.country", types.RiskOpNotIn, []interface{}{"DE", "FR"}), want: false},
+		{name: "eq bool", condition: cond("metadata.verified", types.RiskOpEq, true), want: true},
+		{name: "exists", condition: cond("metadata.country", types.RiskOpExists, true), want: true},
+		{name: "missing fact", condition: cond("metadata.channel", types.RiskOpNe, "web"), want: false},
+		{name: "missing fact exists false", condition: cond("metadata.channel", types.RiskOpExists, false), want: true},
+		{name: "nil fact is missing", condition: cond("account.tier", types.RiskOpExists, false), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := holds(tt.condition, facts); got != tt.want {
+				t.Errorf("holds(%+v) = %v; want %v", tt.condition, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := cond("amount_cents", types.RiskOpGt, float64(100))
+	tests := []struct {
+		name    string
+		rule    types.RiskRule
+		wantErr bool
+	}{
+		{name: "valid", rule: rule("large", 1, types.RiskActionReview, valid)},
+		{name: "metadata field", rule: rule("fr", 1, types.RiskActionDeny, cond("metadata.country", types.RiskOpIn, []interface{}{"FR"}))},
+		{name: "missing name", rule: rule(" ", 1, types.RiskActionDeny, valid), wantErr: true},
+		{name: "unknown action", rule: rule("large", 1, "BLOCK", valid), wantErr: true},
+		{name: "no conditions", rule: rule("large", 1, types.RiskActionDeny), wantErr: true},
+		{name: "unknown field", rule: rule("x", 1, types.RiskActionDeny, cond("account.owner", types.RiskOpEq, "a")), wantErr: true},
+		{name: "bare metadata prefix", rule: rule("x", 1, types.RiskActionDeny, cond("metadata.", types.RiskOpEq, "a")), wantErr: true},
+		{name: "unknown op", rule: rule("x", 1, types.RiskActionDeny, cond("currency", "like", "US%")), wantErr: true},
+		{name: "numeric op on a string", rule: rule("x", 1, types.RiskActionDeny, cond("amount_cents", types.RiskOpGt, "100")), wantErr: true},
+		{name: "empty list", rule: rule("x", 1, types.RiskActionDeny, cond("currency", types.RiskOpIn, []interface{}{})), wantErr: true},
+		{name: "nested list", rule: rule("x", 1, types.RiskActionDeny, cond("currency", types.RiskOpIn, []interface{}{[]interface{}{"USD"}})), wantErr: true},
+		{name: "exists without a bool", rule: rule("x", 1, types.RiskActionDeny, cond("currency", types.RiskOpExists, "yes")), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v; want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package risk
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// Source provides the risk rules in evaluation order
+type Source interface {
+	Rules(ctx context.Context) ([]types.RiskRule, error)
+}
+
+// FileSource serves rules read once from a JSON file holding an array of rules
+type FileSource struct {
+	rules []types.RiskRule
+}
+
+// NewFileSource loads and validates the rules in path
+func NewFileSource(path string) (*FileSource, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read risk rules: %w", err)
+	}
+	var rules []types.RiskRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse risk rules: %w", err)
+	}
+	seen := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if err := Validate(rule); err != nil {
+			return nil, fmt.Errorf("invalid risk rule %q: %w", rule.Name, err)
+		}
+		if seen[rule.Name] {
+			return nil, fmt.Errorf("duplicate risk rule %q", rule.Name)
+		}
+		seen[rule.Name] = true
+	}
+	Sort(rules)
+	return &FileSource{rules: rules}, nil
+}
+
+// Rules returns the rules of the file
+func (s *FileSource) Rules(ctx context.Context) ([]types.RiskRule, error) {
+	return s.rules, nil
+}
+
+// DBSource serves the enabled rules of the risk_rules table, reloading them
+// at most once per refresh interval. If a reload fails the previous rules
+// stay in use.
+type DBSource struct {
+	db       *sql.DB
+	refresh  time.Duration
+	logger   *zap.Logger
+	mu       sync.Mutex
+	rules    []types.RiskRule
+	loadedAt time.Time
+}
+
+// NewDBSource creates a new table-backed rule source
+func NewDBSource(db *sql.DB, refresh time.Duration, logger *zap.Logger) *DBSource {
+	return &DBSource{
+		db:      db,
+		refresh: refresh,
+		logger:  logger,
+	}
+}
+
+// Rules returns the cached rules, reloading them once they are stale
+func (s *DBSource) Rules(ctx context.Context) ([]types.RiskRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loadedAt.IsZero() && time.Since(s.loadedAt) < s.refresh {
+		return s.rules, nil
+	}
+
+	rules, err := LoadRules(ctx, s.db, true)
+	if err != nil {
+		if s.loadedAt.IsZero() {
+			return nil, err
+		}
+		s.logger.Warn("Failed to reload risk rules, using previous rules", zap.Error(err))
+		return s.rules, nil
+	}
+	s.rules = rules
+	s.loadedAt = time.Now()
+	return rules, nil
+}
+
+// LoadRules reads the rules of the risk_rules table in evaluation order.
+// Rows that no longer validate are skipped rather than failing every rule.
+func LoadRules(ctx context.Context, db *sql.DB, enabledOnly bool) ([]types.RiskRule, error) {
+	query := `
+		SELECT name, COALESCE(description, ''), action, priority, conditions, enabled, updated_at
+		FROM risk_rules
+		WHERE enabled OR NOT $1
+		ORDER BY priority, name
+	`
+	rows, err := db.QueryContext(ctx, query, enabledOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query risk rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []types.RiskRule{}
+	for rows.Next() {
+		var rule types.RiskRule
+		var conditions []byte
+		var updatedAt time.Time
+		if err := rows.Scan(&rule.Name, &rule.Description, &rule.Action, &rule.Priority, &conditions, &rule.Enabled, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan risk rule: %w", err)
+		}
+		rule.UpdatedAt = &updatedAt
+		if err := json.Unmarshal(conditions, &rule.Conditions); err != nil {
+			continue
+		}
+		if enabledOnly && Validate(rule) != nil {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
@@ -0,0 +1,35 @@
+package tenant
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Default is the tenant of requests made without a tenant-specific API key
+// and of rows created before tenants existed
+const Default = "default"
+
+type contextKey struct{}
+
+// WithID returns a context carrying the tenant ID
+func WithID(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, tenantID)
+}
+
+// FromContext returns the tenant ID carried by ctx, or Default
+func FromContext(ctx context.Context) string {
+	if tenantID, ok := ctx.Value(contextKey{}).(string); ok && tenantID != "" {
+		return tenantID
+	}
+	return Default
+}
+
+// Scope sets app.tenant_id for the rest of the DB transaction, which the
+// row-level security policies on tenant tables compare against
+func Scope(ctx context.Context, tx *sql.Tx, tenantID string) error {
+	if _, err := tx.ExecContext(ctx, `SELECT set_config('app.tenant_id', $1, true)`, tenantID); err != nil {
+		return fmt.Errorf("failed to scope transaction to tenant: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package txstatus
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/types"
+)
+
+// Actors recorded for status changes the system makes on its own. Changes
+// requested through the API record the caller's actor instead.
+const (
+	ActorAPI       = "api"
+	ActorWorker    = "worker"
+	ActorScheduler = "scheduler"
+)
+
+// Transition moves a transaction of the tenant of ctx to status to inside the
+// caller's DB transaction and records the change in its status history. The
+// transaction row is locked first; a move the state machine does not allow
+// returns an error wrapping types.ErrInvalidTransition. It returns the status
+// the transaction moved from.
+func Transition(ctx context.Context, tx *sql.Tx, transactionID uuid.UUID, to types.TransactionStatus, reason, actor string) (types.TransactionStatus, error) {
+	var from types.TransactionStatus
+	lockQuery := `SELECT status FROM transactions WHERE id = $1 AND tenant_id = $2 FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, lockQuery, transactionID, tenant.FromContext(ctx)).Scan(&from); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", fmt.Errorf("transaction not found")
+		}
+		return "", fmt.Errorf("failed to lock transaction: %w", err)
+	}
+	if err := types.ValidateTransition(from, to); err != nil {
+		return from, err
+	}
+
+	updateQuery := `
+		UPDATE transactions
+		SET status = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+	if _, err := tx.ExecContext(ctx, updateQuery, to, transactionID); err != nil {
+		return from, fmt.Errorf("failed to update transaction status: %w", err)
+	}
+
+	return from, record(ctx, tx, transactionID, &from, to, reason, actor)
+}
+
+// RecordCreated records the status a transaction was inserted with as the
+// first entry of its status history
+func RecordCreated(ctx context.Context, tx *sql.Tx, transactionID uuid.UUID, status types.TransactionStatus, reason, actor string) error {
+	return record(ctx, tx, transactionID, nil, status, reason, actor)
+}
+
+// querier is implemented by *sql.DB and *sql.Tx
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// History returns the status history of a transaction of the tenant of ctx,
+// oldest first. It is empty if the transaction does not exist.
+func History(ctx context.Context, q querier, transactionID uuid.UUID) ([]types.TransactionStatusChange, error) {
+	query := `
+		SELECT id, transaction_id, from_status, to_status, reason, actor, created_at
+		FROM transaction_status_history
+		WHERE transaction_id = $1 AND tenant_id = $2
+		ORDER BY created_at ASC, id ASC
+	`
+	rows, err := q.QueryContext(ctx, query, transactionID, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query status history: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []types.TransactionStatusChange
+	for rows.Next() {
+		var change types.TransactionStatusChange
+		if err := rows.Scan(
+			&change.ID, &change.TransactionID, &change.FromStatus, &change.ToStatus,
+			&change.Reason, &change.Actor, &change.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan status change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate status history: %w", err)
+	}
+	return changes, nil
+}
+
+// record inserts a status history entry belonging to the tenant of ctx
+func record(ctx context.Context, tx *sql.Tx, transactionID uuid.UUID, from *types.TransactionStatus, to types.TransactionStatus, reason, actor string) error {
+	query := `
+		INSERT INTO transaction_status_history (id, tenant_id, transaction_id, from_status, to_status, reason, actor, created_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, clock_timestamp())
+	`
+	if _, err := tx.ExecContext(ctx, query, uuid.New(), tenant.FromContext(ctx), transactionID, from, to, reason, actor); err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+	return nil
+}
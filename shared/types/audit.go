@@ -0,0 +1,50 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit log entity types
+const (
+	AuditEntityAccount     = "account"
+	AuditEntityLimitTier   = "limit_tier"
+	AuditEntityTransaction = "transaction"
+	AuditEntityRiskRule    = "risk_rule"
+	AuditEntityFeeSchedule = "fee_schedule"
+	AuditEntityCustomer    = "customer"
+)
+
+// Audit log actions
+const (
+	AuditActionOverdraftLimitUpdated = "overdraft_limit.updated"
+	AuditActionBalanceRepaired       = "balance.repaired"
+	AuditActionAccountSuspended      = "account.suspended"
+	AuditActionAccountReactivated    = "account.reactivated"
+	AuditActionAccountClosed         = "account.closed"
+	AuditActionLimitsUpdated         = "limits.updated"
+	AuditActionTransactionApproved   = "transaction.approved"
+	AuditActionTransactionRejected   = "transaction.rejected"
+	AuditActionRiskRuleUpdated       = "risk_rule.updated"
+	AuditActionRiskRuleDeleted       = "risk_rule.deleted"
+	AuditActionFeeScheduleUpdated    = "fee_schedule.updated"
+	AuditActionFeeScheduleDeleted    = "fee_schedule.deleted"
+	AuditActionInterestUpdated       = "interest.updated"
+	AuditActionCustomerCreated       = "customer.created"
+	AuditActionCustomerUpdated       = "customer.updated"
+	AuditActionAccountOwnerAdded     = "account_owner.added"
+	AuditActionAccountOwnerRemoved   = "account_owner.removed"
+)
+
+// AuditLog is an append-only record of an administrative change
+type AuditLog struct {
+	ID         uuid.UUID       `json:"id"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   *uuid.UUID      `json:"entity_id,omitempty"`
+	Details    json.RawMessage `json:"details,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	CreatedBy  *string         `json:"created_by,omitempty"`
+}
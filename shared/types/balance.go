@@ -0,0 +1,46 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BalanceInterval is the bucket size of a balance history
+type BalanceInterval string
+
+const (
+	BalanceIntervalHour  BalanceInterval = "hour"
+	BalanceIntervalDay   BalanceInterval = "day"
+	BalanceIntervalWeek  BalanceInterval = "week"
+	BalanceIntervalMonth BalanceInterval = "month"
+)
+
+// AccountBalance is the ledger balance of an account at a point in time
+type AccountBalance struct {
+	AccountID      uuid.UUID `json:"account_id"`
+	Currency       string    `json:"currency"`
+	AsOf           time.Time `json:"as_of"`
+	BalanceCents   int64     `json:"balance_cents"`
+	BalanceDecimal string    `json:"balance_decimal"`
+}
+
+// BalanceHistoryPoint summarises the postings to an account in one bucket
+type BalanceHistoryPoint struct {
+	PeriodStart         time.Time `json:"period_start"`
+	PeriodEnd           time.Time `json:"period_end"`
+	OpeningBalanceCents int64     `json:"opening_balance_cents"`
+	CreditsCents        int64     `json:"credits_cents"`
+	DebitsCents         int64     `json:"debits_cents"`
+	ClosingBalanceCents int64     `json:"closing_balance_cents"`
+}
+
+// BalanceHistory is the balance of an account over a time range
+type BalanceHistory struct {
+	AccountID uuid.UUID             `json:"account_id"`
+	Currency  string                `json:"currency"`
+	From      time.Time             `json:"from"`
+	To        time.Time             `json:"to"`
+	Interval  BalanceInterval       `json:"interval"`
+	Points    []BalanceHistoryPoint `json:"points"`
+}
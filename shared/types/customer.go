@@ -0,0 +1,97 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccountOwnerRole is how a customer owns an account
+type AccountOwnerRole string
+
+const (
+	// AccountOwnerRolePrimary is the account's customer_id; every owned account has exactly one
+	AccountOwnerRolePrimary AccountOwnerRole = "PRIMARY"
+	// AccountOwnerRoleJoint is an additional owner of a joint account
+	AccountOwnerRoleJoint AccountOwnerRole = "JOINT"
+)
+
+// Address is a customer's postal address
+type Address struct {
+	Line1      string `json:"line1,omitempty"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city,omitempty"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Country    string `json:"country,omitempty"`
+}
+
+// Customer is a person who owns accounts
+type Customer struct {
+	ID          uuid.UUID `json:"id"`
+	FullName    string    `json:"full_name"`
+	Email       *string   `json:"email,omitempty"`
+	Phone       *string   `json:"phone,omitempty"`
+	DateOfBirth *string   `json:"date_of_birth,omitempty"`
+	Address     *Address  `json:"address,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreateCustomerRequest represents a request to create a customer.
+// DateOfBirth is YYYY-MM-DD.
+type CreateCustomerRequest struct {
+	FullName    string   `json:"full_name"`
+	Email       *string  `json:"email,omitempty"`
+	Phone       *string  `json:"phone,omitempty"`
+	DateOfBirth *string  `json:"date_of_birth,omitempty"`
+	Address     *Address `json:"address,omitempty"`
+}
+
+// UpdateCustomerRequest represents a partial update of a customer's profile.
+// Omitted fields are left unchanged.
+type UpdateCustomerRequest struct {
+	FullName    *string  `json:"full_name,omitempty"`
+	Email       *string  `json:"email,omitempty"`
+	Phone       *string  `json:"phone,omitempty"`
+	DateOfBirth *string  `json:"date_of_birth,omitempty"`
+	Address     *Address `json:"address,omitempty"`
+}
+
+// AccountOwner is a customer's ownership of an account
+type AccountOwner struct {
+	CustomerID uuid.UUID        `json:"customer_id"`
+	Role       AccountOwnerRole `json:"role"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// AddAccountOwnerRequest represents a request to add a joint owner to an account
+type AddAccountOwnerRequest struct {
+	CustomerID uuid.UUID `json:"customer_id"`
+}
+
+// CustomerAccount is an account a customer owns and the customer's role on it
+type CustomerAccount struct {
+	Account
+	Role AccountOwnerRole `json:"role"`
+}
+
+// CustomerBalance totals the balances of a customer's accounts in one
+// currency. Credit line balances are negative, so the totals are net.
+type CustomerBalance struct {
+	Currency                string `json:"currency"`
+	AccountCount            int    `json:"account_count"`
+	BalanceCents            int64  `json:"balance_cents"`
+	HeldCents               int64  `json:"held_cents"`
+	AvailableBalanceCents   int64  `json:"available_balance_cents"`
+	BalanceDecimal          string `json:"balance_decimal"`
+	AvailableBalanceDecimal string `json:"available_balance_decimal"`
+}
+
+// CustomerAccounts is everything a customer holds: every account they own,
+// alone or jointly, and the balances per currency
+type CustomerAccounts struct {
+	CustomerID uuid.UUID         `json:"customer_id"`
+	Accounts   []CustomerAccount `json:"accounts"`
+	Balances   []CustomerBalance `json:"balances"`
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeeCurrencyAny is the currency of a fee schedule that applies to accounts
+// in any currency without a schedule of their own
+const FeeCurrencyAny = "ANY"
+
+// FeeTier replaces a schedule's flat fee and rate once the account's volume of
+// the transaction type in the current UTC month reaches MinMonthlyVolumeCents
+type FeeTier struct {
+	MinMonthlyVolumeCents int64 `json:"min_monthly_volume_cents"`
+	FlatCents             int64 `json:"flat_cents"`
+	RateBps               int64 `json:"rate_bps"`
+}
+
+// FeeSchedule prices a transaction type in an account currency. The fee is
+// FlatCents plus RateBps basis points of the amount in the account currency,
+// clamped to MinCents and MaxCents.
+type FeeSchedule struct {
+	ID              uuid.UUID       `json:"id"`
+	TransactionType TransactionType `json:"transaction_type"`
+	Currency        string          `json:"currency"`
+	FlatCents       int64           `json:"flat_cents"`
+	RateBps         int64           `json:"rate_bps"`
+	MinCents        *int64          `json:"min_cents,omitempty"`
+	MaxCents        *int64          `json:"max_cents,omitempty"`
+	Tiers           []FeeTier       `json:"tiers"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+}
+
+// SetFeeScheduleRequest represents a request to set the fee schedule of a
+// transaction type and currency
+type SetFeeScheduleRequest struct {
+	FlatCents int64     `json:"flat_cents"`
+	RateBps   int64     `json:"rate_bps"`
+	MinCents  *int64    `json:"min_cents,omitempty"`
+	MaxCents  *int64    `json:"max_cents,omitempty"`
+	Tiers     []FeeTier `json:"tiers,omitempty"`
+}
@@ -0,0 +1,50 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FXRate is a published mid-market rate: Rate units of QuoteCurrency per unit
+// of BaseCurrency, with SpreadBps charged on top. Rates are decimal strings.
+type FXRate struct {
+	ID            uuid.UUID `json:"id"`
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	Rate          string    `json:"rate"`
+	SpreadBps     int       `json:"spread_bps"`
+	EffectiveAt   time.Time `json:"effective_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// CreateFXRateRequest represents a request to publish a rate
+type CreateFXRateRequest struct {
+	BaseCurrency  string     `json:"base_currency"`
+	QuoteCurrency string     `json:"quote_currency"`
+	Rate          string     `json:"rate"`
+	SpreadBps     int        `json:"spread_bps"`
+	EffectiveAt   *time.Time `json:"effective_at,omitempty"`
+}
+
+// FailureCodeQuoteExpired fails a transaction whose FX quote expired before
+// the worker applied it
+const FailureCodeQuoteExpired FailureCode = "QUOTE_EXPIRED"
+
+// FXQuote locks the current rate for a currency pair until ExpiresAt.
+// Transactions in BaseCurrency on a QuoteCurrency account may reference it.
+type FXQuote struct {
+	ID            uuid.UUID `json:"id"`
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	Rate          string    `json:"rate"`
+	SpreadBps     int       `json:"spread_bps"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// CreateFXQuoteRequest represents a request to lock a rate
+type CreateFXQuoteRequest struct {
+	BaseCurrency  string `json:"base_currency"`
+	QuoteCurrency string `json:"quote_currency"`
+}
@@ -0,0 +1,43 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DayCountConvention decides the fraction of a year each day of interest is
+type DayCountConvention string
+
+const (
+	// DayCountActual365 counts every calendar day as 1/365 of a year
+	DayCountActual365 DayCountConvention = "ACT/365"
+	// DayCount30360 treats every month as 30 days of a 360-day year
+	DayCount30360 DayCountConvention = "30/360"
+)
+
+// Valid reports whether c is a supported convention
+func (c DayCountConvention) Valid() bool {
+	return c == DayCountActual365 || c == DayCount30360
+}
+
+// SetInterestRequest represents a request to set an account's interest terms.
+// A nil AnnualRate stops interest; DayCount defaults to ACT/365.
+type SetInterestRequest struct {
+	AnnualRate *string            `json:"annual_rate"`
+	DayCount   DayCountConvention `json:"day_count,omitempty"`
+}
+
+// InterestAccrual is the interest one account accrued on one day.
+// AmountCents keeps fractions of a cent until the month is posted.
+type InterestAccrual struct {
+	AccountID     uuid.UUID          `json:"account_id"`
+	AccrualDate   string             `json:"accrual_date"`
+	BalanceCents  int64              `json:"balance_cents"`
+	AnnualRate    string             `json:"annual_rate"`
+	DayCount      DayCountConvention `json:"day_count"`
+	AmountCents   string             `json:"amount_cents"`
+	PostedAt      *time.Time         `json:"posted_at,omitempty"`
+	TransactionID *uuid.UUID         `json:"transaction_id,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
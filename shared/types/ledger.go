@@ -0,0 +1,66 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PostingDirection represents the side of a ledger posting
+type PostingDirection string
+
+const (
+	PostingDirectionDebit  PostingDirection = "DEBIT"
+	PostingDirectionCredit PostingDirection = "CREDIT"
+)
+
+// Opposite returns the other side of the ledger
+func (d PostingDirection) Opposite() PostingDirection {
+	if d == PostingDirectionDebit {
+		return PostingDirectionCredit
+	}
+	return PostingDirectionDebit
+}
+
+// System account codes. System accounts hold the offsetting side of
+// movements that enter or leave the platform.
+const (
+	SystemAccountSettlement = "SETTLEMENT"
+	// SystemAccountFXPosition holds the platform's position in each currency
+	// bought or sold when converting a transaction
+	SystemAccountFXPosition = "FX_POSITION"
+	// SystemAccountFXRevenue collects FX spread when no revenue account is configured
+	SystemAccountFXRevenue = "FX_REVENUE"
+	// SystemAccountFeeRevenue collects fees when no revenue account is configured
+	SystemAccountFeeRevenue = "FEE_REVENUE"
+)
+
+// JournalEntry groups the balanced postings of a single ledger movement
+type JournalEntry struct {
+	ID            uuid.UUID  `json:"id"`
+	TransactionID *uuid.UUID `json:"transaction_id,omitempty"`
+	Description   string     `json:"description"`
+	Postings      []Posting  `json:"postings"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Posting represents a single debit or credit line of a journal entry
+type Posting struct {
+	ID             uuid.UUID        `json:"id"`
+	JournalEntryID uuid.UUID        `json:"journal_entry_id"`
+	TransactionID  *uuid.UUID       `json:"transaction_id,omitempty"`
+	AccountID      uuid.UUID        `json:"account_id"`
+	Direction      PostingDirection `json:"direction"`
+	AmountCents    int64            `json:"amount_cents"`
+	Currency       string           `json:"currency"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+// SignedAmount returns the effect of the posting on the account balance.
+// Credits increase the balance and debits decrease it.
+func (p Posting) SignedAmount() int64 {
+	if p.Direction == PostingDirectionDebit {
+		return -p.AmountCents
+	}
+	return p.AmountCents
+}
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultAccountTier is the tier of accounts created without one
+const DefaultAccountTier = "STANDARD"
+
+// FailureCode is the machine-readable reason a transaction failed
+type FailureCode string
+
+const (
+	FailureCodeSingleDebitLimit   FailureCode = "LIMIT_SINGLE_DEBIT"
+	FailureCodeDailyAmountLimit   FailureCode = "LIMIT_DAILY_AMOUNT"
+	FailureCodeMonthlyAmountLimit FailureCode = "LIMIT_MONTHLY_AMOUNT"
+	FailureCodeDailyCountLimit    FailureCode = "LIMIT_DAILY_COUNT"
+	FailureCodeMonthlyCountLimit  FailureCode = "LIMIT_MONTHLY_COUNT"
+)
+
+// TransactionLimits caps the debits of an account. Amounts are in the account
+// currency; windows are UTC calendar days and months. A nil limit is unlimited.
+type TransactionLimits struct {
+	MaxSingleDebitCents  *int64 `json:"max_single_debit_cents,omitempty"`
+	MaxDailyDebitCents   *int64 `json:"max_daily_debit_cents,omitempty"`
+	MaxMonthlyDebitCents *int64 `json:"max_monthly_debit_cents,omitempty"`
+	MaxDailyDebitCount   *int   `json:"max_daily_debit_count,omitempty"`
+	MaxMonthlyDebitCount *int   `json:"max_monthly_debit_count,omitempty"`
+}
+
+// LimitUsage is the debit activity counted against an account's limits
+type LimitUsage struct {
+	DailyDebitCents   int64 `json:"daily_debit_cents"`
+	DailyDebitCount   int   `json:"daily_debit_count"`
+	MonthlyDebitCents int64 `json:"monthly_debit_cents"`
+	MonthlyDebitCount int   `json:"monthly_debit_count"`
+}
+
+// LimitHeadroom is what an account may still debit before hitting a limit.
+// A nil field is unlimited.
+type LimitHeadroom struct {
+	SingleDebitCents  *int64 `json:"single_debit_cents,omitempty"`
+	DailyDebitCents   *int64 `json:"daily_debit_cents,omitempty"`
+	MonthlyDebitCents *int64 `json:"monthly_debit_cents,omitempty"`
+	DailyDebitCount   *int   `json:"daily_debit_count,omitempty"`
+	MonthlyDebitCount *int   `json:"monthly_debit_count,omitempty"`
+}
+
+// LimitViolation describes the limit a debit would exceed
+type LimitViolation struct {
+	Code   FailureCode `json:"code"`
+	Reason string      `json:"reason"`
+}
+
+// AccountLimits is an account's effective limits, where they come from, and
+// the headroom left in the current windows
+type AccountLimits struct {
+	AccountID     uuid.UUID         `json:"account_id"`
+	Tier          string            `json:"tier"`
+	Currency      string            `json:"currency"`
+	Limits        TransactionLimits `json:"limits"`
+	TierLimits    TransactionLimits `json:"tier_limits"`
+	Overrides     TransactionLimits `json:"overrides"`
+	Usage         LimitUsage        `json:"usage"`
+	Headroom      LimitHeadroom     `json:"headroom"`
+	DayResetsAt   time.Time         `json:"day_resets_at"`
+	MonthResetsAt time.Time         `json:"month_resets_at"`
+}
+
+// SetAccountLimitsRequest replaces an account's limit overrides and optionally moves it to another tier
+type SetAccountLimitsRequest struct {
+	Tier      string            `json:"tier,omitempty"`
+	Overrides TransactionLimits `json:"overrides"`
+}
+
+// TierLimits are the limits of every account in a tier without an override
+type TierLimits struct {
+	Tier      string            `json:"tier"`
+	Limits    TransactionLimits `json:"limits"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
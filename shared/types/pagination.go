@@ -0,0 +1,8 @@
+package types
+
+// PageCursors link a page of a keyset-paginated list to the pages around it.
+// A nil cursor means there is no page in that direction.
+type PageCursors struct {
+	NextCursor *string `json:"next_cursor"`
+	PrevCursor *string `json:"prev_cursor"`
+}
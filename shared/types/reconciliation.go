@@ -0,0 +1,45 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReconciliationStatus represents the state of a reconciliation run
+type ReconciliationStatus string
+
+const (
+	ReconciliationStatusRunning   ReconciliationStatus = "RUNNING"
+	ReconciliationStatusCompleted ReconciliationStatus = "COMPLETED"
+	ReconciliationStatusFailed    ReconciliationStatus = "FAILED"
+)
+
+// ReconciliationRun is one pass of the balance reconciliation job
+type ReconciliationRun struct {
+	ID               uuid.UUID               `json:"id"`
+	Status           ReconciliationStatus    `json:"status"`
+	AutoRepair       bool                    `json:"auto_repair"`
+	AccountsScanned  int                     `json:"accounts_scanned"`
+	DriftAccounts    int                     `json:"drift_accounts"`
+	RepairedAccounts int                     `json:"repaired_accounts"`
+	Error            *string                 `json:"error,omitempty"`
+	StartedAt        time.Time               `json:"started_at"`
+	CompletedAt      *time.Time              `json:"completed_at,omitempty"`
+	Findings         []ReconciliationFinding `json:"findings"`
+}
+
+// ReconciliationFinding records an account whose stored balance does not
+// match the signed sum of its PROCESSED transactions or its ledger postings
+type ReconciliationFinding struct {
+	ID                   uuid.UUID `json:"id"`
+	RunID                uuid.UUID `json:"run_id"`
+	AccountID            uuid.UUID `json:"account_id"`
+	Currency             string    `json:"currency"`
+	BalanceCents         int64     `json:"balance_cents"`
+	ExpectedBalanceCents int64     `json:"expected_balance_cents"`
+	LedgerBalanceCents   int64     `json:"ledger_balance_cents"`
+	DriftCents           int64     `json:"drift_cents"`
+	Repaired             bool      `json:"repaired"`
+	CreatedAt            time.Time `json:"created_at"`
+}
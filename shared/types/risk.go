@@ -0,0 +1,85 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// RiskAction is the outcome of a risk rule
+type RiskAction string
+
+const (
+	RiskActionAllow  RiskAction = "ALLOW"
+	RiskActionDeny   RiskAction = "DENY"
+	RiskActionReview RiskAction = "REVIEW"
+)
+
+// Valid reports whether a is a known action
+func (a RiskAction) Valid() bool {
+	return a == RiskActionAllow || a == RiskActionDeny || a == RiskActionReview
+}
+
+// Failure codes of transactions stopped by risk rules
+const (
+	FailureCodeRiskDenied     FailureCode = "RISK_DENIED"
+	FailureCodeReviewRejected FailureCode = "REVIEW_REJECTED"
+)
+
+// RiskOperator compares a fact with a condition value
+type RiskOperator string
+
+const (
+	RiskOpEq     RiskOperator = "eq"
+	RiskOpNe     RiskOperator = "ne"
+	RiskOpGt     RiskOperator = "gt"
+	RiskOpGte    RiskOperator = "gte"
+	RiskOpLt     RiskOperator = "lt"
+	RiskOpLte    RiskOperator = "lte"
+	RiskOpIn     RiskOperator = "in"
+	RiskOpNotIn  RiskOperator = "not_in"
+	RiskOpExists RiskOperator = "exists"
+)
+
+// RiskCondition compares one fact of a transaction, such as "amount_cents" or
+// "metadata.country", with a value
+type RiskCondition struct {
+	Field string       `json:"field"`
+	Op    RiskOperator `json:"op"`
+	Value interface{}  `json:"value"`
+}
+
+// RiskRule matches a transaction when all of its conditions hold. Rules are
+// evaluated by ascending priority and the first match decides.
+type RiskRule struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Action      RiskAction      `json:"action"`
+	Priority    int             `json:"priority"`
+	Conditions  []RiskCondition `json:"conditions"`
+	Enabled     bool            `json:"enabled"`
+	UpdatedAt   *time.Time      `json:"updated_at,omitempty"`
+}
+
+// UnmarshalJSON defaults Enabled to true when it is omitted
+func (r *RiskRule) UnmarshalJSON(data []byte) error {
+	type plain RiskRule
+	rule := plain{Enabled: true}
+	if err := json.Unmarshal(data, &rule); err != nil {
+		return err
+	}
+	*r = RiskRule(rule)
+	return nil
+}
+
+// ReviewDecision is how a transaction held for review was resolved
+type ReviewDecision string
+
+const (
+	ReviewDecisionApproved ReviewDecision = "APPROVED"
+	ReviewDecisionRejected ReviewDecision = "REJECTED"
+)
+
+// ReviewTransactionRequest is the body of an approve or reject request
+type ReviewTransactionRequest struct {
+	Note string `json:"note,omitempty"`
+}
@@ -0,0 +1,99 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecurrenceFrequency is how often a recurring schedule repeats
+type RecurrenceFrequency string
+
+const (
+	RecurrenceDaily   RecurrenceFrequency = "DAILY"
+	RecurrenceWeekly  RecurrenceFrequency = "WEEKLY"
+	RecurrenceMonthly RecurrenceFrequency = "MONTHLY"
+)
+
+// RecurrenceRule describes when a schedule fires. Occurrences fall at the
+// schedule's start time of day, UTC. Weekdays use RRULE codes (MO..SU); a
+// MonthDay of -1 is the last day of the month, and days past the end of a
+// short month fall on its last day.
+type RecurrenceRule struct {
+	Frequency RecurrenceFrequency `json:"frequency"`
+	Interval  int                 `json:"interval,omitempty"`
+	Weekdays  []string            `json:"weekdays,omitempty"`
+	MonthDay  int                 `json:"month_day,omitempty"`
+	Count     int                 `json:"count,omitempty"`
+	Until     *time.Time          `json:"until,omitempty"`
+}
+
+// ScheduleStatus represents the state of a recurring schedule
+type ScheduleStatus string
+
+const (
+	ScheduleStatusActive    ScheduleStatus = "ACTIVE"
+	ScheduleStatusPaused    ScheduleStatus = "PAUSED"
+	ScheduleStatusCompleted ScheduleStatus = "COMPLETED"
+	ScheduleStatusCancelled ScheduleStatus = "CANCELLED"
+)
+
+// TransactionTemplate is the transaction a recurring schedule creates at
+// every occurrence
+type TransactionTemplate struct {
+	AccountID   uuid.UUID       `json:"account_id"`
+	AmountCents int64           `json:"amount_cents"`
+	Currency    string          `json:"currency"`
+	Type        TransactionType `json:"type"`
+	Metadata    json.RawMessage `json:"metadata,omitempty"`
+}
+
+// Request returns the CreateTransactionRequest for one occurrence
+func (t TransactionTemplate) Request(idempotencyKey string) CreateTransactionRequest {
+	return CreateTransactionRequest{
+		AccountID:      t.AccountID,
+		AmountCents:    t.AmountCents,
+		Currency:       t.Currency,
+		Type:           t.Type,
+		IdempotencyKey: idempotencyKey,
+		Metadata:       t.Metadata,
+	}
+}
+
+// RecurringSchedule creates a transaction from its template at every
+// occurrence of its rule
+type RecurringSchedule struct {
+	ID                uuid.UUID           `json:"id"`
+	Template          TransactionTemplate `json:"template"`
+	Rule              RecurrenceRule      `json:"rule"`
+	RRule             string              `json:"rrule"`
+	StartAt           time.Time           `json:"start_at"`
+	NextRunAt         *time.Time          `json:"next_run_at,omitempty"`
+	LastRunAt         *time.Time          `json:"last_run_at,omitempty"`
+	OccurrenceCount   int                 `json:"occurrence_count"`
+	LastTransactionID *uuid.UUID          `json:"last_transaction_id,omitempty"`
+	LastError         *string             `json:"last_error,omitempty"`
+	Status            ScheduleStatus      `json:"status"`
+	CreatedAt         time.Time           `json:"created_at"`
+	UpdatedAt         time.Time           `json:"updated_at"`
+}
+
+// CreateScheduleRequest represents a request to create a recurring schedule.
+// The rule is given either as Rule or as an RRULE string.
+type CreateScheduleRequest struct {
+	Template TransactionTemplate `json:"template"`
+	Rule     *RecurrenceRule     `json:"rule,omitempty"`
+	RRule    string              `json:"rrule,omitempty"`
+	StartAt  *time.Time          `json:"start_at,omitempty"`
+}
+
+// UpdateScheduleRequest represents a partial update of a recurring schedule.
+// Status may only move between ACTIVE and PAUSED.
+type UpdateScheduleRequest struct {
+	AmountCents *int64          `json:"amount_cents,omitempty"`
+	Metadata    json.RawMessage `json:"metadata,omitempty"`
+	Rule        *RecurrenceRule `json:"rule,omitempty"`
+	RRule       *string         `json:"rrule,omitempty"`
+	Status      *ScheduleStatus `json:"status,omitempty"`
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatementLine is one posting to the account with the balance after it
+type StatementLine struct {
+	PostedAt            time.Time        `json:"posted_at"`
+	TransactionID       *uuid.UUID       `json:"transaction_id,omitempty"`
+	Type                string           `json:"type"`
+	Direction           PostingDirection `json:"direction"`
+	AmountCents         int64            `json:"amount_cents"`
+	Description         string           `json:"description"`
+	RunningBalanceCents int64            `json:"running_balance_cents"`
+}
+
+// StatementTotal is the number and sum of statement lines of one type
+type StatementTotal struct {
+	Type         string `json:"type"`
+	Count        int    `json:"count"`
+	CreditsCents int64  `json:"credits_cents"`
+	DebitsCents  int64  `json:"debits_cents"`
+}
+
+// Statement lists every processed movement on an account in a period
+// between its opening and closing balances
+type Statement struct {
+	AccountID           uuid.UUID        `json:"account_id"`
+	Currency            string           `json:"currency"`
+	Period              string           `json:"period"`
+	PeriodStart         time.Time        `json:"period_start"`
+	PeriodEnd           time.Time        `json:"period_end"`
+	OpeningBalanceCents int64            `json:"opening_balance_cents"`
+	ClosingBalanceCents int64            `json:"closing_balance_cents"`
+	Totals              []StatementTotal `json:"totals"`
+	Lines               []StatementLine  `json:"lines"`
+	GeneratedAt         time.Time        `json:"generated_at"`
+}
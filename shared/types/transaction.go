@@ -11,18 +11,68 @@ import (
 type TransactionType string
 
 const (
-	TransactionTypeDebit  TransactionType = "DEBIT"
-	TransactionTypeCredit TransactionType = "CREDIT"
+	TransactionTypeDebit     TransactionType = "DEBIT"
+	TransactionTypeCredit    TransactionType = "CREDIT"
+	TransactionTypeAuthorize TransactionType = "AUTHORIZE"
+	TransactionTypeCapture   TransactionType = "CAPTURE"
+	TransactionTypeVoid      TransactionType = "VOID"
 )
 
+// Valid reports whether t is a known transaction type
+func (t TransactionType) Valid() bool {
+	switch t {
+	case TransactionTypeDebit, TransactionTypeCredit, TransactionTypeAuthorize, TransactionTypeCapture, TransactionTypeVoid:
+		return true
+	default:
+		return false
+	}
+}
+
+// ReversalType returns the type of the transaction that undoes a transaction
+// of this type. Only types that move funds can be reversed.
+func (t TransactionType) ReversalType() (TransactionType, bool) {
+	switch t {
+	case TransactionTypeDebit, TransactionTypeCapture:
+		return TransactionTypeCredit, true
+	case TransactionTypeCredit:
+		return TransactionTypeDebit, true
+	default:
+		return "", false
+	}
+}
+
 // TransactionStatus represents the status of a transaction
 type TransactionStatus string
 
 const (
+	TransactionStatusScheduled  TransactionStatus = "SCHEDULED"
 	TransactionStatusPending    TransactionStatus = "PENDING"
+	TransactionStatusInReview   TransactionStatus = "IN_REVIEW"
 	TransactionStatusProcessing TransactionStatus = "PROCESSING"
 	TransactionStatusProcessed  TransactionStatus = "PROCESSED"
 	TransactionStatusFailed     TransactionStatus = "FAILED"
+	TransactionStatusCancelled  TransactionStatus = "CANCELLED"
+)
+
+// Valid reports whether s is a known transaction status
+func (s TransactionStatus) Valid() bool {
+	switch s {
+	case TransactionStatusScheduled, TransactionStatusPending, TransactionStatusInReview, TransactionStatusProcessing,
+		TransactionStatusProcessed, TransactionStatusFailed, TransactionStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// HoldStatus represents the lifecycle of the hold placed by an AUTHORIZE transaction
+type HoldStatus string
+
+const (
+	HoldStatusHeld     HoldStatus = "HELD"
+	HoldStatusCaptured HoldStatus = "CAPTURED"
+	HoldStatusVoided   HoldStatus = "VOIDED"
+	HoldStatusExpired  HoldStatus = "EXPIRED"
 )
 
 // AccountStatus represents the status of an account
@@ -31,48 +81,295 @@ type AccountStatus string
 const (
 	AccountStatusActive    AccountStatus = "ACTIVE"
 	AccountStatusSuspended AccountStatus = "SUSPENDED"
+	AccountStatusClosed    AccountStatus = "CLOSED"
+)
+
+// Valid reports whether s is a known account status
+func (s AccountStatus) Valid() bool {
+	switch s {
+	case AccountStatusActive, AccountStatusSuspended, AccountStatusClosed:
+		return true
+	default:
+		return false
+	}
+}
+
+// FailureCodeAccountNotFound fails a transaction or transfer whose account no
+// longer exists or belongs to another tenant when the worker applies it
+const FailureCodeAccountNotFound FailureCode = "ACCOUNT_NOT_FOUND"
+
+// AccountStatusReason is the reason code recorded with an account status change
+type AccountStatusReason string
+
+const (
+	AccountStatusReasonCustomerRequest AccountStatusReason = "CUSTOMER_REQUEST"
+	AccountStatusReasonFraudSuspected  AccountStatusReason = "FRAUD_SUSPECTED"
+	AccountStatusReasonCompliance      AccountStatusReason = "COMPLIANCE"
+	AccountStatusReasonDormant         AccountStatusReason = "DORMANT"
+	AccountStatusReasonResolved        AccountStatusReason = "RESOLVED"
+	AccountStatusReasonOther           AccountStatusReason = "OTHER"
 )
 
-// Account represents a financial account
+// Valid reports whether r is a known reason code
+func (r AccountStatusReason) Valid() bool {
+	switch r {
+	case AccountStatusReasonCustomerRequest, AccountStatusReasonFraudSuspected, AccountStatusReasonCompliance,
+		AccountStatusReasonDormant, AccountStatusReasonResolved, AccountStatusReasonOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// AccountKind distinguishes deposit accounts from lending accounts
+type AccountKind string
+
+const (
+	AccountKindStandard   AccountKind = "STANDARD"
+	AccountKindCreditLine AccountKind = "CREDIT_LINE"
+)
+
+// Account represents a financial account. Debits may take the balance down
+// to -OverdraftLimitCents; for a CREDIT_LINE that is the credit limit.
+// Amounts ending in Cents are in minor units of the currency (yen for JPY,
+// fils for KWD); the Decimal fields render them in major units.
 type Account struct {
-	ID           uuid.UUID     `json:"id"`
-	CreatedAt    time.Time     `json:"created_at"`
-	UpdatedAt    time.Time     `json:"updated_at"`
-	Currency     string        `json:"currency"`
-	BalanceCents int64         `json:"balance_cents"`
-	Status       AccountStatus `json:"status"`
+	ID                      uuid.UUID           `json:"id"`
+	TenantID                string              `json:"tenant_id"`
+	CreatedAt               time.Time           `json:"created_at"`
+	UpdatedAt               time.Time           `json:"updated_at"`
+	Currency                string              `json:"currency"`
+	BalanceCents            int64               `json:"balance_cents"`
+	HeldCents               int64               `json:"held_cents"`
+	AvailableBalanceCents   int64               `json:"available_balance_cents"`
+	BalanceDecimal          string              `json:"balance_decimal"`
+	AvailableBalanceDecimal string              `json:"available_balance_decimal"`
+	OverdraftLimitCents     int64               `json:"overdraft_limit_cents"`
+	Kind                    AccountKind         `json:"kind"`
+	Tier                    string              `json:"tier"`
+	CustomerID              *uuid.UUID          `json:"customer_id,omitempty"`
+	InterestRate            *string             `json:"interest_rate,omitempty"`
+	InterestDayCount        *DayCountConvention `json:"interest_day_count,omitempty"`
+	Status                  AccountStatus       `json:"status"`
 }
 
 // Transaction represents a financial transaction
 type Transaction struct {
-	ID             uuid.UUID         `json:"id"`
-	AccountID      uuid.UUID         `json:"account_id"`
-	AmountCents    int64             `json:"amount_cents"`
-	Currency       string            `json:"currency"`
-	Type           TransactionType   `json:"type"`
-	Status         TransactionStatus `json:"status"`
-	IdempotencyKey string            `json:"idempotency_key"`
-	FailureReason  *string           `json:"failure_reason,omitempty"`
-	Metadata       json.RawMessage   `json:"metadata,omitempty"`
-	CreatedAt      time.Time         `json:"created_at"`
-	UpdatedAt      time.Time         `json:"updated_at"`
+	ID                    uuid.UUID         `json:"id"`
+	TenantID              string            `json:"tenant_id"`
+	AccountID             uuid.UUID         `json:"account_id"`
+	AmountCents           int64             `json:"amount_cents"`
+	AmountDecimal         string            `json:"amount_decimal"`
+	Currency              string            `json:"currency"`
+	Type                  TransactionType   `json:"type"`
+	Status                TransactionStatus `json:"status"`
+	IdempotencyKey        string            `json:"idempotency_key"`
+	FailureReason         *string           `json:"failure_reason,omitempty"`
+	FailureCode           *FailureCode      `json:"failure_code,omitempty"`
+	Metadata              json.RawMessage   `json:"metadata,omitempty"`
+	TransferID            *uuid.UUID        `json:"transfer_id,omitempty"`
+	AuthorizationID       *uuid.UUID        `json:"authorization_id,omitempty"`
+	HoldStatus            *HoldStatus       `json:"hold_status,omitempty"`
+	HoldExpiresAt         *time.Time        `json:"hold_expires_at,omitempty"`
+	CapturedAmountCents   *int64            `json:"captured_amount_cents,omitempty"`
+	ReversesTransactionID *uuid.UUID        `json:"reverses_transaction_id,omitempty"`
+	FXQuoteID             *uuid.UUID        `json:"fx_quote_id,omitempty"`
+	FXRate                *string           `json:"fx_rate,omitempty"`
+	SettledAmountCents    *int64            `json:"settled_amount_cents,omitempty"`
+	SettledCurrency       *string           `json:"settled_currency,omitempty"`
+	FXSpreadCents         *int64            `json:"fx_spread_cents,omitempty"`
+	ExecuteAt             *time.Time        `json:"execute_at,omitempty"`
+	FeeCents              *int64            `json:"fee_cents,omitempty"`
+	FeeTransactionID      *uuid.UUID        `json:"fee_transaction_id,omitempty"`
+	FeeForTransactionID   *uuid.UUID        `json:"fee_for_transaction_id,omitempty"`
+	InterestPeriod        *string           `json:"interest_period,omitempty"`
+	ReviewReason          *string           `json:"review_reason,omitempty"`
+	ReviewDecision        *ReviewDecision   `json:"review_decision,omitempty"`
+	ReviewedBy            *string           `json:"reviewed_by,omitempty"`
+	ReviewedAt            *time.Time        `json:"reviewed_at,omitempty"`
+	CreatedAt             time.Time         `json:"created_at"`
+	UpdatedAt             time.Time         `json:"updated_at"`
 }
 
 // CreateTransactionRequest represents a request to create a transaction
 type CreateTransactionRequest struct {
-	AccountID      uuid.UUID       `json:"account_id"`
-	AmountCents    int64           `json:"amount_cents"`
-	Currency       string          `json:"currency"`
-	Type           TransactionType `json:"type"`
+	AccountID       uuid.UUID       `json:"account_id"`
+	AmountCents     int64           `json:"amount_cents"`
+	Currency        string          `json:"currency"`
+	Type            TransactionType `json:"type"`
+	IdempotencyKey  string          `json:"idempotency_key"`
+	Metadata        json.RawMessage `json:"metadata,omitempty"`
+	AuthorizationID *uuid.UUID      `json:"authorization_id,omitempty"`
+	FXQuoteID       *uuid.UUID      `json:"fx_quote_id,omitempty"`
+	ExecuteAt       *time.Time      `json:"execute_at,omitempty"`
+}
+
+// ReverseTransactionRequest represents a request to reverse a processed
+// transaction. AmountCents defaults to the amount not yet reversed.
+type ReverseTransactionRequest struct {
+	AmountCents    int64           `json:"amount_cents,omitempty"`
 	IdempotencyKey string          `json:"idempotency_key"`
 	Metadata       json.RawMessage `json:"metadata,omitempty"`
 }
 
-// CreateAccountRequest represents a request to create an account
+// TransactionSort orders a transaction list; a leading "-" sorts descending
+type TransactionSort string
+
+const (
+	TransactionSortCreatedAtDesc   TransactionSort = "-created_at"
+	TransactionSortCreatedAtAsc    TransactionSort = "created_at"
+	TransactionSortUpdatedAtDesc   TransactionSort = "-updated_at"
+	TransactionSortUpdatedAtAsc    TransactionSort = "updated_at"
+	TransactionSortAmountCentsDesc TransactionSort = "-amount_cents"
+	TransactionSortAmountCentsAsc  TransactionSort = "amount_cents"
+)
+
+// Valid reports whether s is a known sort order
+func (s TransactionSort) Valid() bool {
+	switch s {
+	case TransactionSortCreatedAtDesc, TransactionSortCreatedAtAsc, TransactionSortUpdatedAtDesc,
+		TransactionSortUpdatedAtAsc, TransactionSortAmountCentsDesc, TransactionSortAmountCentsAsc:
+		return true
+	default:
+		return false
+	}
+}
+
+// TransactionFilter narrows a transaction list; unset fields match every
+// transaction. CreatedFrom is inclusive and CreatedTo exclusive. Each
+// Metadata entry must be contained in the transaction's metadata.
+type TransactionFilter struct {
+	AccountID      *uuid.UUID
+	Statuses       []TransactionStatus
+	Types          []TransactionType
+	Currency       string
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	MinAmountCents *int64
+	MaxAmountCents *int64
+	Metadata       map[string]string
+	Sort           TransactionSort
+}
+
+// BatchMode selects how a transaction batch handles failing items
+type BatchMode string
+
+const (
+	// BatchModeAtomic commits every item or none of them
+	BatchModeAtomic BatchMode = "ATOMIC"
+	// BatchModeBestEffort commits the items that succeed and reports the rest
+	BatchModeBestEffort BatchMode = "BEST_EFFORT"
+)
+
+// CreateTransactionBatchRequest represents a request to create many transactions at once
+type CreateTransactionBatchRequest struct {
+	Mode         BatchMode                  `json:"mode"`
+	Transactions []CreateTransactionRequest `json:"transactions"`
+}
+
+// TransactionBatchItem is the result of one item of a batch. Status is the
+// HTTP status the item would have received as a single request; the other
+// items of an ATOMIC batch rolled back by a failing one have 424.
+type TransactionBatchItem struct {
+	Index       int          `json:"index"`
+	Status      int          `json:"status"`
+	Committed   bool         `json:"committed"`
+	Transaction *Transaction `json:"transaction,omitempty"`
+	Error       string       `json:"error,omitempty"`
+	Details     string       `json:"details,omitempty"`
+	Err         error        `json:"-"`
+}
+
+// TransactionBatchResult represents the outcome of a transaction batch
+type TransactionBatchResult struct {
+	Mode           BatchMode              `json:"mode"`
+	Committed      bool                   `json:"committed"`
+	SucceededCount int                    `json:"succeeded_count"`
+	FailedCount    int                    `json:"failed_count"`
+	Items          []TransactionBatchItem `json:"items"`
+}
+
+// CreateAccountRequest represents a request to create an account. CustomerID
+// is the primary owner; JointOwnerIDs requires it.
 type CreateAccountRequest struct {
-	Currency string `json:"currency"`
+	Currency            string      `json:"currency"`
+	Kind                AccountKind `json:"kind,omitempty"`
+	Tier                string      `json:"tier,omitempty"`
+	OverdraftLimitCents int64       `json:"overdraft_limit_cents,omitempty"`
+	CustomerID          *uuid.UUID  `json:"customer_id,omitempty"`
+	JointOwnerIDs       []uuid.UUID `json:"joint_owner_ids,omitempty"`
+}
+
+// UpdateOverdraftLimitRequest represents a request to set an account's overdraft limit
+type UpdateOverdraftLimitRequest struct {
+	OverdraftLimitCents int64  `json:"overdraft_limit_cents"`
+	Reason              string `json:"reason,omitempty"`
+}
+
+// ChangeAccountStatusRequest is the body of a suspend, reactivate or close request
+type ChangeAccountStatusRequest struct {
+	ReasonCode AccountStatusReason `json:"reason_code"`
+	Note       string              `json:"note,omitempty"`
+}
+
+// AccountSort orders an account list; a leading "-" sorts descending
+type AccountSort string
+
+const (
+	AccountSortCreatedAtDesc    AccountSort = "-created_at"
+	AccountSortCreatedAtAsc     AccountSort = "created_at"
+	AccountSortBalanceCentsDesc AccountSort = "-balance_cents"
+	AccountSortBalanceCentsAsc  AccountSort = "balance_cents"
+)
+
+// Valid reports whether s is a known sort order
+func (s AccountSort) Valid() bool {
+	switch s {
+	case AccountSortCreatedAtDesc, AccountSortCreatedAtAsc, AccountSortBalanceCentsDesc, AccountSortBalanceCentsAsc:
+		return true
+	default:
+		return false
+	}
 }
 
+// AccountFilter narrows an account list; unset fields match every account.
+// CreatedFrom is inclusive and CreatedTo exclusive; the balance bounds are
+// inclusive. OwnerID matches accounts the customer owns, alone or jointly.
+type AccountFilter struct {
+	Statuses        []AccountStatus
+	Currency        string
+	CreatedFrom     *time.Time
+	CreatedTo       *time.Time
+	MinBalanceCents *int64
+	MaxBalanceCents *int64
+	OwnerID         *uuid.UUID
+	Sort            AccountSort
+}
+
+// AccountTotal totals the balances of the accounts matching a list's filters
+// in one currency. Credit line balances are negative, so the totals are net.
+type AccountTotal struct {
+	Currency                string `json:"currency"`
+	AccountCount            int    `json:"account_count"`
+	BalanceCents            int64  `json:"balance_cents"`
+	HeldCents               int64  `json:"held_cents"`
+	AvailableBalanceCents   int64  `json:"available_balance_cents"`
+	BalanceDecimal          string `json:"balance_decimal"`
+	AvailableBalanceDecimal string `json:"available_balance_decimal"`
+}
+
+// Event types carried in EventEnvelope.EventType
+const (
+	EventTypeTransactionCreated  = "transaction.created"
+	EventTypeTransactionReversed = "transaction.reversed"
+	EventTypeTransferCreated     = "transfer.created"
+	EventTypeTransferProcessed   = "transfer.processed"
+	EventTypeTransferFailed      = "transfer.failed"
+	EventTypeAccountSuspended    = "account.suspended"
+	EventTypeAccountReactivated  = "account.reactivated"
+	EventTypeAccountClosed       = "account.closed"
+)
+
 // EventEnvelope represents a message envelope for event streaming
 type EventEnvelope struct {
 	EventID        uuid.UUID       `json:"event_id"`
@@ -81,18 +378,40 @@ type EventEnvelope struct {
 	TraceID        string          `json:"trace_id"`
 	IdempotencyKey string          `json:"idempotency_key"`
 	AggregateID    uuid.UUID       `json:"aggregate_id"`
+	TenantID       string          `json:"tenant_id,omitempty"`
 	Payload        json.RawMessage `json:"payload"`
 }
 
 // TransactionCreatedPayload represents the payload for transaction.created event
 type TransactionCreatedPayload struct {
-	TransactionID  uuid.UUID       `json:"transaction_id"`
-	AccountID      uuid.UUID       `json:"account_id"`
-	AmountCents    int64           `json:"amount_cents"`
-	Currency       string          `json:"currency"`
-	Type           TransactionType `json:"type"`
-	IdempotencyKey string          `json:"idempotency_key"`
-	Metadata       json.RawMessage `json:"metadata,omitempty"`
+	TransactionID         uuid.UUID       `json:"transaction_id"`
+	AccountID             uuid.UUID       `json:"account_id"`
+	AmountCents           int64           `json:"amount_cents"`
+	Currency              string          `json:"currency"`
+	Type                  TransactionType `json:"type"`
+	IdempotencyKey        string          `json:"idempotency_key"`
+	Metadata              json.RawMessage `json:"metadata,omitempty"`
+	AuthorizationID       *uuid.UUID      `json:"authorization_id,omitempty"`
+	ReversesTransactionID *uuid.UUID      `json:"reverses_transaction_id,omitempty"`
+	FXQuoteID             *uuid.UUID      `json:"fx_quote_id,omitempty"`
+	InterestPeriod        *string         `json:"interest_period,omitempty"`
+}
+
+// CreatedPayload returns the transaction.created event payload for the transaction
+func (t *Transaction) CreatedPayload() TransactionCreatedPayload {
+	return TransactionCreatedPayload{
+		TransactionID:         t.ID,
+		AccountID:             t.AccountID,
+		AmountCents:           t.AmountCents,
+		Currency:              t.Currency,
+		Type:                  t.Type,
+		IdempotencyKey:        t.IdempotencyKey,
+		Metadata:              t.Metadata,
+		AuthorizationID:       t.AuthorizationID,
+		ReversesTransactionID: t.ReversesTransactionID,
+		FXQuoteID:             t.FXQuoteID,
+		InterestPeriod:        t.InterestPeriod,
+	}
 }
 
 // TransactionProcessedPayload represents the payload for transaction.processed event
@@ -108,3 +427,27 @@ type TransactionFailedPayload struct {
 	AccountID     uuid.UUID `json:"account_id"`
 	FailureReason string    `json:"failure_reason"`
 }
+
+// TransactionReversedPayload represents the payload for transaction.reversed event
+type TransactionReversedPayload struct {
+	TransactionID         uuid.UUID `json:"transaction_id"`
+	ReversesTransactionID uuid.UUID `json:"reverses_transaction_id"`
+	AccountID             uuid.UUID `json:"account_id"`
+	AmountCents           int64     `json:"amount_cents"`
+	Currency              string    `json:"currency"`
+	TotalReversedCents    int64     `json:"total_reversed_cents"`
+	FullyReversed         bool      `json:"fully_reversed"`
+	NewBalance            int64     `json:"new_balance"`
+}
+
+// AccountStatusChangedPayload represents the payload for account.suspended,
+// account.reactivated and account.closed events
+type AccountStatusChangedPayload struct {
+	AccountID  uuid.UUID           `json:"account_id"`
+	OldStatus  AccountStatus       `json:"old_status"`
+	NewStatus  AccountStatus       `json:"new_status"`
+	ReasonCode AccountStatusReason `json:"reason_code"`
+	Note       string              `json:"note,omitempty"`
+	Actor      string              `json:"actor"`
+	ChangedAt  time.Time           `json:"changed_at"`
+}
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidTransition is returned for a status change the transaction state
+// machine does not allow
+var ErrInvalidTransition = errors.New("invalid transaction status transition")
+
+// transactionTransitions lists the statuses each status may move to.
+// PROCESSED, FAILED and CANCELLED are terminal.
+var transactionTransitions = map[TransactionStatus][]TransactionStatus{
+	TransactionStatusScheduled:  {TransactionStatusPending, TransactionStatusFailed, TransactionStatusCancelled},
+	TransactionStatusPending:    {TransactionStatusProcessing, TransactionStatusCancelled},
+	TransactionStatusProcessing: {TransactionStatusProcessed, TransactionStatusFailed, TransactionStatusInReview},
+	TransactionStatusInReview:   {TransactionStatusPending, TransactionStatusFailed},
+}
+
+// CanTransitionTo reports whether a transaction may move from s to next
+func (s TransactionStatus) CanTransitionTo(next TransactionStatus) bool {
+	for _, allowed := range transactionTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal reports whether no further status change is allowed
+func (s TransactionStatus) IsTerminal() bool {
+	return len(transactionTransitions[s]) == 0
+}
+
+// ValidateTransition returns an error wrapping ErrInvalidTransition if a
+// transaction may not move from one status to the other
+func ValidateTransition(from, to TransactionStatus) error {
+	if !from.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+	}
+	return nil
+}
+
+// TransactionStatusChange is one entry of a transaction's status history.
+// FromStatus is nil for the status the transaction was created with.
+type TransactionStatusChange struct {
+	ID            uuid.UUID          `json:"id"`
+	TransactionID uuid.UUID          `json:"transaction_id"`
+	FromStatus    *TransactionStatus `json:"from_status,omitempty"`
+	ToStatus      TransactionStatus  `json:"to_status"`
+	Reason        *string            `json:"reason,omitempty"`
+	Actor         string             `json:"actor"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
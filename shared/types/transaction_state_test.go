@@ -0,0 +1,78 @@
+package types
+
+import (
+	"errors"
+	"testing"
+)
+
+var allStatuses = []TransactionStatus{
+	TransactionStatusScheduled,
+	TransactionStatusPending,
+	TransactionStatusInReview,
+	TransactionStatusProcessing,
+	TransactionStatusProcessed,
+	TransactionStatusFailed,
+	TransactionStatusCancelled,
+}
+
+func TestValidateTransition(t *testing.T) {
+	allowed := map[TransactionStatus][]TransactionStatus{
+		TransactionStatusScheduled:  {TransactionStatusPending, TransactionStatusFailed, TransactionStatusCancelled},
+		TransactionStatusPending:    {TransactionStatusProcessing, TransactionStatusCancelled},
+		TransactionStatusProcessing: {TransactionStatusProcessed, TransactionStatusFailed, TransactionStatusInReview},
+		TransactionStatusInReview:   {TransactionStatusPending, TransactionStatusFailed},
+	}
+
+	for _, from := range allStatuses {
+		for _, to := range allStatuses {
+			want := false
+			for _, next := range allowed[from] {
+				if next == to {
+					want = true
+				}
+			}
+
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s.CanTransitionTo(%s) = %v; want %v", from, to, got, want)
+			}
+			err := ValidateTransition(from, to)
+			if want && err != nil {
+				t.Errorf("ValidateTransition(%s, %s) = %v; want nil", from, to, err)
+			}
+			if !want && !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("ValidateTransition(%s, %s) = %v; want ErrInvalidTransition", from, to, err)
+			}
+		}
+	}
+}
+
+func TestIllegalTransitions(t *testing.T) {
+	tests := []struct {
+		from, to TransactionStatus
+	}{
+		{from: TransactionStatusFailed, to: TransactionStatusProcessed},
+		{from: TransactionStatusCancelled, to: TransactionStatusPending},
+		{from: TransactionStatusPending, to: TransactionStatusProcessed},
+		{from: TransactionStatusScheduled, to: TransactionStatusProcessing},
+		{from: TransactionStatusInReview, to: TransactionStatusProcessed},
+		{from: TransactionStatusPending, to: TransactionStatusPending},
+	}
+	for _, to := range allStatuses {
+		tests = append(tests, struct{ from, to TransactionStatus }{from: TransactionStatusProcessed, to: to})
+	}
+
+	for _, tt := range tests {
+		if err := ValidateTransition(tt.from, tt.to); !errors.Is(err, ErrInvalidTransition) {
+			t.Errorf("ValidateTransition(%s, %s) = %v; want ErrInvalidTransition", tt.from, tt.to, err)
+		}
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	for _, s := range allStatuses {
+		want := s == TransactionStatusProcessed || s == TransactionStatusFailed || s == TransactionStatusCancelled
+		if got := s.IsTerminal(); got != want {
+			t.Errorf("%s.IsTerminal() = %v; want %v", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Transfer represents an atomic movement of funds between two accounts.
+// Each transfer is backed by a DEBIT leg on the source account and a CREDIT
+// leg on the destination account.
+type Transfer struct {
+	ID                  uuid.UUID         `json:"id"`
+	FromAccountID       uuid.UUID         `json:"from_account_id"`
+	ToAccountID         uuid.UUID         `json:"to_account_id"`
+	AmountCents         int64             `json:"amount_cents"`
+	AmountDecimal       string            `json:"amount_decimal"`
+	Currency            string            `json:"currency"`
+	Status              TransactionStatus `json:"status"`
+	IdempotencyKey      string            `json:"idempotency_key"`
+	FailureReason       *string           `json:"failure_reason,omitempty"`
+	FailureCode         *FailureCode      `json:"failure_code,omitempty"`
+	Metadata            json.RawMessage   `json:"metadata,omitempty"`
+	DebitTransactionID  uuid.UUID         `json:"debit_transaction_id"`
+	CreditTransactionID uuid.UUID         `json:"credit_transaction_id"`
+	CreatedAt           time.Time         `json:"created_at"`
+	UpdatedAt           time.Time         `json:"updated_at"`
+}
+
+// CreateTransferRequest represents a request to create a transfer
+type CreateTransferRequest struct {
+	FromAccountID  uuid.UUID       `json:"from_account_id"`
+	ToAccountID    uuid.UUID       `json:"to_account_id"`
+	AmountCents    int64           `json:"amount_cents"`
+	Currency       string          `json:"currency"`
+	IdempotencyKey string          `json:"idempotency_key"`
+	Metadata       json.RawMessage `json:"metadata,omitempty"`
+}
+
+// TransferCreatedPayload represents the payload for transfer.created event
+type TransferCreatedPayload struct {
+	TransferID          uuid.UUID       `json:"transfer_id"`
+	FromAccountID       uuid.UUID       `json:"from_account_id"`
+	ToAccountID         uuid.UUID       `json:"to_account_id"`
+	DebitTransactionID  uuid.UUID       `json:"debit_transaction_id"`
+	CreditTransactionID uuid.UUID       `json:"credit_transaction_id"`
+	AmountCents         int64           `json:"amount_cents"`
+	Currency            string          `json:"currency"`
+	IdempotencyKey      string          `json:"idempotency_key"`
+	Metadata            json.RawMessage `json:"metadata,omitempty"`
+}
+
+// TransferProcessedPayload represents the payload for transfer.processed event
+type TransferProcessedPayload struct {
+	TransferID         uuid.UUID `json:"transfer_id"`
+	FromAccountID      uuid.UUID `json:"from_account_id"`
+	ToAccountID        uuid.UUID `json:"to_account_id"`
+	AmountCents        int64     `json:"amount_cents"`
+	Currency           string    `json:"currency"`
+	FromAccountBalance int64     `json:"from_account_balance"`
+	ToAccountBalance   int64     `json:"to_account_balance"`
+}
+
+// TransferFailedPayload represents the payload for transfer.failed event
+type TransferFailedPayload struct {
+	TransferID    uuid.UUID   `json:"transfer_id"`
+	FromAccountID uuid.UUID   `json:"from_account_id"`
+	ToAccountID   uuid.UUID   `json:"to_account_id"`
+	FailureReason string      `json:"failure_reason"`
+	FailureCode   FailureCode `json:"failure_code,omitempty"`
+}
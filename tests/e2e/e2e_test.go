@@ -2,21 +2,27 @@ package e2e
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/yash/transaction-system/shared/currency"
 	"github.com/yash/transaction-system/shared/types"
 )
 
 const (
 	apiBaseURL = "http://localhost:8080"
 	apiKey     = "demo-api-key-12345"
+
+	// tenantBAPIKey authenticates the tenant-b tenant (API_KEYS in docker-compose)
+	tenantBAPIKey = "demo-tenant-b-key-67890"
 )
 
 func TestE2E_TransactionFlow(t *testing.T) {
@@ -70,11 +76,1190 @@ func TestE2E_InsufficientBalance(t *testing.T) {
 	assert.Contains(t, *transaction.FailureReason, "insufficient balance")
 }
 
+func TestE2E_Transfer(t *testing.T) {
+	// Fund the source account
+	fromAccountID := createAccount(t, "USD")
+	toAccountID := createAccount(t, "USD")
+	fundingID := createTransaction(t, fromAccountID, 10000, "USD", types.TransactionTypeCredit, uuid.New().String())
+	waitForTransactionStatus(t, fundingID, types.TransactionStatusProcessed, 30*time.Second)
+
+	// Transfer part of it
+	idempotencyKey := uuid.New().String()
+	transfer := createTransfer(t, fromAccountID, toAccountID, 4000, "USD", idempotencyKey)
+	waitForTransactionStatus(t, transfer.DebitTransactionID, types.TransactionStatusProcessed, 30*time.Second)
+
+	// Both legs were applied together
+	assert.Equal(t, types.TransactionStatusProcessed, getTransaction(t, transfer.CreditTransactionID).Status)
+	assert.Equal(t, int64(6000), getAccount(t, fromAccountID).BalanceCents)
+	assert.Equal(t, int64(4000), getAccount(t, toAccountID).BalanceCents)
+
+	// Idempotency - same key returns the same transfer
+	transfer2 := createTransfer(t, fromAccountID, toAccountID, 4000, "USD", idempotencyKey)
+	assert.Equal(t, transfer.ID, transfer2.ID)
+
+	// A transfer larger than the balance fails both legs
+	failed := createTransfer(t, fromAccountID, toAccountID, 100000, "USD", uuid.New().String())
+	waitForTransactionStatus(t, failed.CreditTransactionID, types.TransactionStatusFailed, 30*time.Second)
+	assert.Equal(t, int64(6000), getAccount(t, fromAccountID).BalanceCents)
+	assert.Equal(t, int64(4000), getAccount(t, toAccountID).BalanceCents)
+}
+
+func TestE2E_AuthorizationHold(t *testing.T) {
+	accountID := createAccount(t, "USD")
+	fundingID := createTransaction(t, accountID, 10000, "USD", types.TransactionTypeCredit, uuid.New().String())
+	waitForTransactionStatus(t, fundingID, types.TransactionStatusProcessed, 30*time.Second)
+
+	// Authorize reserves funds without moving them
+	authID := createTransaction(t, accountID, 7000, "USD", types.TransactionTypeAuthorize, uuid.New().String())
+	waitForTransactionStatus(t, authID, types.TransactionStatusProcessed, 30*time.Second)
+
+	account := getAccount(t, accountID)
+	assert.Equal(t, int64(10000), account.BalanceCents)
+	assert.Equal(t, int64(3000), account.AvailableBalanceCents)
+
+	// A debit beyond the available balance fails
+	debitID := createTransaction(t, accountID, 5000, "USD", types.TransactionTypeDebit, uuid.New().String())
+	waitForTransactionStatus(t, debitID, types.TransactionStatusFailed, 30*time.Second)
+
+	// Partial capture settles the captured amount and releases the rest
+	captureID := createTransactionWithRequest(t, types.CreateTransactionRequest{
+		AccountID:       accountID,
+		AmountCents:     4000,
+		Currency:        "USD",
+		Type:            types.TransactionTypeCapture,
+		IdempotencyKey:  uuid.New().String(),
+		AuthorizationID: &authID,
+	})
+	waitForTransactionStatus(t, captureID, types.TransactionStatusProcessed, 30*time.Second)
+
+	account = getAccount(t, accountID)
+	assert.Equal(t, int64(6000), account.BalanceCents)
+	assert.Equal(t, int64(0), account.HeldCents)
+	assert.Equal(t, int64(6000), account.AvailableBalanceCents)
+
+	auth := getTransaction(t, authID)
+	require.NotNil(t, auth.HoldStatus)
+	assert.Equal(t, types.HoldStatusCaptured, *auth.HoldStatus)
+}
+
+func TestE2E_Reversal(t *testing.T) {
+	accountID := createAccount(t, "USD")
+	fundingID := createTransaction(t, accountID, 10000, "USD", types.TransactionTypeCredit, uuid.New().String())
+	waitForTransactionStatus(t, fundingID, types.TransactionStatusProcessed, 30*time.Second)
+
+	debitID := createTransaction(t, accountID, 4000, "USD", types.TransactionTypeDebit, uuid.New().String())
+	waitForTransactionStatus(t, debitID, types.TransactionStatusProcessed, 30*time.Second)
+
+	// Partial refund of the debit
+	status, refund := reverseTransaction(t, debitID, 1500, uuid.New().String())
+	require.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, types.TransactionTypeCredit, refund.Type)
+	require.NotNil(t, refund.ReversesTransactionID)
+	assert.Equal(t, debitID, *refund.ReversesTransactionID)
+	waitForTransactionStatus(t, refund.ID, types.TransactionStatusProcessed, 30*time.Second)
+	assert.Equal(t, int64(7500), getAccount(t, accountID).BalanceCents)
+
+	// Omitting the amount refunds the remainder
+	status, refund = reverseTransaction(t, debitID, 0, uuid.New().String())
+	require.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, int64(2500), refund.AmountCents)
+	waitForTransactionStatus(t, refund.ID, types.TransactionStatusProcessed, 30*time.Second)
+	assert.Equal(t, int64(10000), getAccount(t, accountID).BalanceCents)
+
+	// Nothing left to refund
+	status, _ = reverseTransaction(t, debitID, 100, uuid.New().String())
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+}
+
+func TestE2E_OverdraftLimit(t *testing.T) {
+	account := createAccountWithRequest(t, types.CreateAccountRequest{
+		Currency:            "USD",
+		Kind:                types.AccountKindCreditLine,
+		OverdraftLimitCents: 5000,
+	})
+	assert.Equal(t, types.AccountKindCreditLine, account.Kind)
+
+	// Draws down the credit line below zero
+	drawID := createTransaction(t, account.ID, 3000, "USD", types.TransactionTypeDebit, uuid.New().String())
+	waitForTransactionStatus(t, drawID, types.TransactionStatusProcessed, 30*time.Second)
+	assert.Equal(t, int64(-3000), getAccount(t, account.ID).BalanceCents)
+
+	// Beyond the limit fails
+	overID := createTransaction(t, account.ID, 3000, "USD", types.TransactionTypeDebit, uuid.New().String())
+	waitForTransactionStatus(t, overID, types.TransactionStatusFailed, 30*time.Second)
+
+	// Raising the limit is audited and lets the draw through
+	updated := updateOverdraftLimit(t, account.ID, 10000)
+	assert.Equal(t, int64(10000), updated.OverdraftLimitCents)
+
+	drawID = createTransaction(t, account.ID, 3000, "USD", types.TransactionTypeDebit, uuid.New().String())
+	waitForTransactionStatus(t, drawID, types.TransactionStatusProcessed, 30*time.Second)
+	assert.Equal(t, int64(-6000), getAccount(t, account.ID).BalanceCents)
+
+	var result struct {
+		AuditLogs []types.AuditLog `json:"audit_logs"`
+	}
+	getJSON(t, fmt.Sprintf("/v1/accounts/%s/audit-logs", account.ID), &result)
+	require.Len(t, result.AuditLogs, 2)
+	assert.Equal(t, types.AuditActionOverdraftLimitUpdated, result.AuditLogs[0].Action)
+}
+
+func TestE2E_FXConversion(t *testing.T) {
+	accountID := createAccount(t, "EUR")
+	postJSON(t, "/v1/fx/rates", types.CreateFXRateRequest{
+		BaseCurrency:  "USD",
+		QuoteCurrency: "EUR",
+		Rate:          "0.9",
+		SpreadBps:     100,
+	}, http.StatusCreated, nil)
+
+	// 100.00 USD at 0.9 is 90.00 EUR, less a 1% spread
+	creditID := createTransaction(t, accountID, 10000, "USD", types.TransactionTypeCredit, uuid.New().String())
+	waitForTransactionStatus(t, creditID, types.TransactionStatusProcessed, 30*time.Second)
+
+	credit := getTransaction(t, creditID)
+	require.NotNil(t, credit.SettledAmountCents)
+	assert.Equal(t, int64(8910), *credit.SettledAmountCents)
+	assert.Equal(t, int64(90), *credit.FXSpreadCents)
+	assert.Equal(t, "0.9", *credit.FXRate)
+	assert.Equal(t, int64(8910), getAccount(t, accountID).BalanceCents)
+
+	// A locked quote converts a debit with the spread on top
+	var quote types.FXQuote
+	postJSON(t, "/v1/fx/quotes", types.CreateFXQuoteRequest{
+		BaseCurrency:  "USD",
+		QuoteCurrency: "EUR",
+	}, http.StatusCreated, &quote)
+
+	debitID := createTransactionWithRequest(t, types.CreateTransactionRequest{
+		AccountID:      accountID,
+		AmountCents:    1000,
+		Currency:       "USD",
+		Type:           types.TransactionTypeDebit,
+		IdempotencyKey: uuid.New().String(),
+		FXQuoteID:      &quote.ID,
+	})
+	waitForTransactionStatus(t, debitID, types.TransactionStatusProcessed, 30*time.Second)
+	assert.Equal(t, int64(8001), getAccount(t, accountID).BalanceCents)
+}
+
+func TestE2E_BalanceAsOf(t *testing.T) {
+	accountID := createAccount(t, "USD")
+	firstID := createTransaction(t, accountID, 5000, "USD", types.TransactionTypeCredit, uuid.New().String())
+	waitForTransactionStatus(t, firstID, types.TransactionStatusProcessed, 30*time.Second)
+
+	between := time.Now().UTC()
+	time.Sleep(10 * time.Millisecond)
+
+	secondID := createTransaction(t, accountID, 2000, "USD", types.TransactionTypeCredit, uuid.New().String())
+	waitForTransactionStatus(t, secondID, types.TransactionStatusProcessed, 30*time.Second)
+
+	var balance types.AccountBalance
+	getJSON(t, fmt.Sprintf("/v1/accounts/%s/balance?as_of=%s", accountID, between.Format(time.RFC3339Nano)), &balance)
+	assert.Equal(t, int64(5000), balance.BalanceCents)
+
+	getJSON(t, fmt.Sprintf("/v1/accounts/%s/balance", accountID), &balance)
+	assert.Equal(t, int64(7000), balance.BalanceCents)
+
+	var history types.BalanceHistory
+	getJSON(t, fmt.Sprintf("/v1/accounts/%s/balance-history?from=%s&interval=day", accountID, between.AddDate(0, 0, -1).Format("2006-01-02")), &history)
+	require.NotEmpty(t, history.Points)
+	last := history.Points[len(history.Points)-1]
+	assert.Equal(t, int64(7000), last.ClosingBalanceCents)
+}
+
+func TestE2E_Statement(t *testing.T) {
+	accountID := createAccount(t, "USD")
+	creditID := createTransaction(t, accountID, 5000, "USD", types.TransactionTypeCredit, uuid.New().String())
+	waitForTransactionStatus(t, creditID, types.TransactionStatusProcessed, 30*time.Second)
+	debitID := createTransaction(t, accountID, 1500, "USD", types.TransactionTypeDebit, uuid.New().String())
+	waitForTransactionStatus(t, debitID, types.TransactionStatusProcessed, 30*time.Second)
+
+	period := time.Now().UTC().Format("2006-01")
+	var statement types.Statement
+	getJSON(t, fmt.Sprintf("/v1/accounts/%s/statements/%s", accountID, period), &statement)
+	assert.Equal(t, int64(0), statement.OpeningBalanceCents)
+	assert.Equal(t, int64(3500), statement.ClosingBalanceCents)
+	require.Len(t, statement.Lines, 2)
+	assert.Equal(t, int64(5000), statement.Lines[0].RunningBalanceCents)
+	assert.Equal(t, int64(3500), statement.Lines[1].RunningBalanceCents)
+	assert.Len(t, statement.Totals, 2)
+
+	httpReq, _ := http.NewRequest("GET", fmt.Sprintf("%s/v1/accounts/%s/statements/%s?format=csv", apiBaseURL, accountID, period), nil)
+	httpReq.Header.Set("X-API-Key", apiKey)
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/csv", resp.Header.Get("Content-Type"))
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	require.NoError(t, err)
+	// Header, opening, two lines, two rows per type total, closing
+	assert.Len(t, records, 9)
+}
+
+func TestE2E_Reconciliation(t *testing.T) {
+	// The worker reconciles on startup, so at least one run exists
+	var result struct {
+		Reconciliations []types.ReconciliationRun `json:"reconciliations"`
+	}
+	getJSON(t, "/v1/admin/reconciliations?limit=5", &result)
+	require.NotEmpty(t, result.Reconciliations)
+
+	for _, run := range result.Reconciliations {
+		if run.Status != types.ReconciliationStatusCompleted {
+			continue
+		}
+		assert.Equal(t, run.DriftAccounts, len(run.Findings))
+		for _, finding := range run.Findings {
+			assert.Equal(t, finding.BalanceCents-finding.ExpectedBalanceCents, finding.DriftCents)
+		}
+	}
+}
+
+func TestE2E_AccountLifecycle(t *testing.T) {
+	accountID := createAccount(t, "USD")
+	creditID := createTransaction(t, accountID, 1000, "USD", types.TransactionTypeCredit, uuid.New().String())
+	waitForTransactionStatus(t, creditID, types.TransactionStatusProcessed, 30*time.Second)
+
+	suspend := types.ChangeAccountStatusRequest{ReasonCode: types.AccountStatusReasonFraudSuspected}
+	var account types.Account
+	postJSON(t, fmt.Sprintf("/v1/accounts/%s/suspend", accountID), suspend, http.StatusOK, &account)
+	assert.Equal(t, types.AccountStatusSuspended, account.Status)
+
+	// Suspended accounts accept no new transactions
+	blocked := types.CreateTransactionRequest{
+		AccountID:      accountID,
+		AmountCents:    100,
+		Currency:       "USD",
+		Type:           types.TransactionTypeCredit,
+		IdempotencyKey: uuid.New().String(),
+	}
+	postJSON(t, "/v1/transactions", blocked, http.StatusBadRequest, nil)
+
+	// Closing requires a zero balance
+	reactivate := types.ChangeAccountStatusRequest{ReasonCode: types.AccountStatusReasonResolved}
+	postJSON(t, fmt.Sprintf("/v1/accounts/%s/reactivate", accountID), reactivate, http.StatusOK, &account)
+	assert.Equal(t, types.AccountStatusActive, account.Status)
+
+	closeReq := types.ChangeAccountStatusRequest{ReasonCode: types.AccountStatusReasonCustomerRequest, Note: "e2e"}
+	postJSON(t, fmt.Sprintf("/v1/accounts/%s/close", accountID), closeReq, http.StatusUnprocessableEntity, nil)
+
+	debitID := createTransaction(t, accountID, 1000, "USD", types.TransactionTypeDebit, uuid.New().String())
+	waitForTransactionStatus(t, debitID, types.TransactionStatusProcessed, 30*time.Second)
+
+	postJSON(t, fmt.Sprintf("/v1/accounts/%s/close", accountID), closeReq, http.StatusOK, &account)
+	assert.Equal(t, types.AccountStatusClosed, account.Status)
+
+	// CLOSED is terminal
+	postJSON(t, fmt.Sprintf("/v1/accounts/%s/reactivate", accountID), reactivate, http.StatusConflict, nil)
+
+	var logs struct {
+		AuditLogs  []types.AuditLog `json:"audit_logs"`
+		NextCursor *string          `json:"next_cursor"`
+		PrevCursor *string          `json:"prev_cursor"`
+	}
+	getJSON(t, fmt.Sprintf("/v1/accounts/%s/audit-logs", accountID), &logs)
+	require.Len(t, logs.AuditLogs, 3)
+	assert.Equal(t, types.AuditActionAccountClosed, logs.AuditLogs[0].Action)
+	assert.Nil(t, logs.NextCursor)
+
+	// The audit trail pages by cursor like the transaction list
+	getJSON(t, fmt.Sprintf("/v1/accounts/%s/audit-logs?limit=2", accountID), &logs)
+	require.Len(t, logs.AuditLogs, 2)
+	require.NotNil(t, logs.NextCursor)
+	assert.Nil(t, logs.PrevCursor)
+	getJSON(t, fmt.Sprintf("/v1/accounts/%s/audit-logs?limit=2&cursor=%s", accountID, url.QueryEscape(*logs.NextCursor)), &logs)
+	require.Len(t, logs.AuditLogs, 1)
+	assert.Equal(t, types.AuditActionAccountSuspended, logs.AuditLogs[0].Action)
+	assert.Nil(t, logs.NextCursor)
+	requestAs(t, apiKey, http.MethodGet, fmt.Sprintf("/v1/accounts/%s/audit-logs?cursor=not-a-cursor", accountID), nil, http.StatusBadRequest, nil)
+}
+
+func TestE2E_ScheduledTransaction(t *testing.T) {
+	accountID := createAccount(t, "USD")
+
+	// Released by the scheduler once due
+	executeAt := time.Now().Add(3 * time.Second)
+	var scheduled types.Transaction
+	postJSON(t, "/v1/transactions", types.CreateTransactionRequest{
+		AccountID:      accountID,
+		AmountCents:    2500,
+		Currency:       "USD",
+		Type:           types.TransactionTypeCredit,
+		IdempotencyKey: uuid.New().String(),
+		ExecuteAt:      &executeAt,
+	}, http.StatusCreated, &scheduled)
+	assert.Equal(t, types.TransactionStatusScheduled, scheduled.Status)
+	require.NotNil(t, scheduled.ExecuteAt)
+
+	waitForTransactionStatus(t, scheduled.ID, types.TransactionStatusProcessed, 60*time.Second)
+	assert.Equal(t, int64(2500), getAccount(t, accountID).BalanceCents)
+
+	// Cancelled while still scheduled
+	later := time.Now().Add(time.Hour)
+	postJSON(t, "/v1/transactions", types.CreateTransactionRequest{
+		AccountID:      accountID,
+		AmountCents:    1000,
+		Currency:       "USD",
+		Type:           types.TransactionTypeDebit,
+		IdempotencyKey: uuid.New().String(),
+		ExecuteAt:      &later,
+	}, http.StatusCreated, &scheduled)
+
+	var cancelled types.Transaction
+	deleteJSON(t, fmt.Sprintf("/v1/transactions/%s", scheduled.ID), http.StatusOK, &cancelled)
+	assert.Equal(t, types.TransactionStatusCancelled, cancelled.Status)
+	deleteJSON(t, fmt.Sprintf("/v1/transactions/%s", scheduled.ID), http.StatusConflict, nil)
+}
+
+func TestE2E_CancelPendingTransaction(t *testing.T) {
+	accountID := createAccount(t, "USD")
+	creditID := createTransaction(t, accountID, 5000, "USD", types.TransactionTypeCredit, uuid.New().String())
+	waitForTransactionStatus(t, creditID, types.TransactionStatusProcessed, 10*time.Second)
+
+	// Cancelled right after submission, before the publisher's next tick
+	debitID := createTransaction(t, accountID, 1200, "USD", types.TransactionTypeDebit, uuid.New().String())
+	var cancelled types.Transaction
+	postJSON(t, fmt.Sprintf("/v1/transactions/%s/cancel", debitID), nil, http.StatusOK, &cancelled)
+	assert.Equal(t, types.TransactionStatusCancelled, cancelled.Status)
+	postJSON(t, fmt.Sprintf("/v1/transactions/%s/cancel", debitID), nil, http.StatusConflict, nil)
+
+	// The worker never applies it
+	time.Sleep(8 * time.Second)
+	assert.Equal(t, types.TransactionStatusCancelled, getTransaction(t, debitID).Status)
+	assert.Equal(t, int64(5000), getAccount(t, accountID).BalanceCents)
+
+	// Processed transactions can no longer be cancelled
+	postJSON(t, fmt.Sprintf("/v1/transactions/%s/cancel", creditID), nil, http.StatusConflict, nil)
+	postJSON(t, fmt.Sprintf("/v1/transactions/%s/cancel", uuid.New()), nil, http.StatusNotFound, nil)
+}
+
+func TestE2E_TransactionStatusHistory(t *testing.T) {
+	accountID := createAccount(t, "USD")
+	creditID := createTransaction(t, accountID, 1000, "USD", types.TransactionTypeCredit, uuid.New().String())
+	waitForTransactionStatus(t, creditID, types.TransactionStatusProcessed, 10*time.Second)
+
+	type history struct {
+		Items []types.TransactionStatusChange `json:"items"`
+	}
+	var processed history
+	getJSON(t, fmt.Sprintf("/v1/transactions/%s/history", creditID), &processed)
+	require.Len(t, processed.Items, 3)
+	assert.Nil(t, processed.Items[0].FromStatus)
+	assert.Equal(t, types.TransactionStatusPending, processed.Items[0].ToStatus)
+	assert.Equal(t, "api", processed.Items[0].Actor)
+	assert.Equal(t, types.TransactionStatusProcessing, processed.Items[1].ToStatus)
+	require.NotNil(t, processed.Items[2].FromStatus)
+	assert.Equal(t, types.TransactionStatusProcessing, *processed.Items[2].FromStatus)
+	assert.Equal(t, types.TransactionStatusProcessed, processed.Items[2].ToStatus)
+	assert.Equal(t, "worker", processed.Items[2].Actor)
+
+	// A rejected debit records its failure reason
+	debitID := createTransaction(t, accountID, 5000, "USD", types.TransactionTypeDebit, uuid.New().String())
+	waitForTransactionStatus(t, debitID, types.TransactionStatusFailed, 10*time.Second)
+	var failed history
+	getJSON(t, fmt.Sprintf("/v1/transactions/%s/history", debitID), &failed)
+	require.Len(t, failed.Items, 3)
+	assert.Equal(t, types.TransactionStatusFailed, failed.Items[2].ToStatus)
+	require.NotNil(t, failed.Items[2].Reason)
+	assert.Contains(t, *failed.Items[2].Reason, "insufficient balance")
+
+	// A cancelled transaction stops at CANCELLED
+	pendingID := createTransaction(t, accountID, 100, "USD", types.TransactionTypeDebit, uuid.New().String())
+	postJSON(t, fmt.Sprintf("/v1/transactions/%s/cancel", pendingID), nil, http.StatusOK, nil)
+	var cancelled history
+	getJSON(t, fmt.Sprintf("/v1/transactions/%s/history", pendingID), &cancelled)
+	require.Len(t, cancelled.Items, 2)
+	require.NotNil(t, cancelled.Items[1].FromStatus)
+	assert.Equal(t, types.TransactionStatusPending, *cancelled.Items[1].FromStatus)
+	assert.Equal(t, types.TransactionStatusCancelled, cancelled.Items[1].ToStatus)
+
+	requestAs(t, apiKey, http.MethodGet, fmt.Sprintf("/v1/transactions/%s/history", uuid.New()), nil, http.StatusNotFound, nil)
+}
+
+func TestE2E_TransactionSearch(t *testing.T) {
+	accountID := createAccount(t, "USD")
+	orderID := uuid.New().String()
+	create := func(amountCents int64, txType types.TransactionType, metadata string) uuid.UUID {
+		return createTransactionWithRequest(t, types.CreateTransactionRequest{
+			AccountID:      accountID,
+			AmountCents:    amountCents,
+			Currency:       "USD",
+			Type:           txType,
+			IdempotencyKey: uuid.New().String(),
+			Metadata:       json.RawMessage(metadata),
+		})
+	}
+	creditID := create(10000, types.TransactionTypeCredit, `{"channel": "web", "attempt": 1}`)
+	waitForTransactionStatus(t, creditID, types.TransactionStatusProcessed, 10*time.Second)
+	debitID := create(2500, types.TransactionTypeDebit, fmt.Sprintf(`{"order_id": %q, "channel": "web"}`, orderID))
+	waitForTransactionStatus(t, debitID, types.TransactionStatusProcessed, 10*time.Second)
+	failedID := create(50000, types.TransactionTypeDebit, `{"channel": "pos"}`)
+	waitForTransactionStatus(t, failedID, types.TransactionStatusFailed, 10*time.Second)
+
+	type listResponse struct {
+		Transactions []types.Transaction `json:"transactions"`
+	}
+	search := func(query url.Values) []uuid.UUID {
+		query.Set("account_id", accountID.String())
+		var listed listResponse
+		getJSON(t, "/v1/transactions?"+query.Encode(), &listed)
+		ids := make([]uuid.UUID, len(listed.Transactions))
+		for i, transaction := range listed.Transactions {
+			ids[i] = transaction.ID
+		}
+		return ids
+	}
+
+	assert.Equal(t, []uuid.UUID{failedID, debitID, creditID}, search(url.Values{}))
+	assert.Equal(t, []uuid.UUID{creditID, debitID, failedID}, search(url.Values{"sort": {"created_at"}}))
+	assert.Equal(t, []uuid.UUID{failedID, creditID, debitID}, search(url.Values{"sort": {"-amount_cents"}}))
+	assert.Equal(t, []uuid.UUID{failedID}, search(url.Values{"status": {"FAILED,CANCELLED"}}))
+	assert.Equal(t, []uuid.UUID{failedID, debitID}, search(url.Values{"type": {"DEBIT"}, "currency": {"USD"}}))
+	assert.Equal(t, []uuid.UUID{debitID, creditID}, search(url.Values{"min_amount_cents": {"2500"}, "max_amount_cents": {"10000"}}))
+	assert.Equal(t, []uuid.UUID{debitID}, search(url.Values{"metadata[order_id]": {orderID}}))
+	assert.Equal(t, []uuid.UUID{debitID, creditID}, search(url.Values{"metadata[channel]": {"web"}}))
+	assert.Equal(t, []uuid.UUID{creditID}, search(url.Values{"metadata[attempt]": {"1"}}))
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format("2006-01-02")
+	assert.Empty(t, search(url.Values{"created_from": {tomorrow}}))
+	assert.Len(t, search(url.Values{"created_to": {tomorrow}}), 3)
+
+	for _, invalid := range []string{"status=DONE", "type=REFUND", "sort=amount", "min_amount_cents=-1", "created_from=yesterday", "currency=usd"} {
+		requestAs(t, apiKey, http.MethodGet, "/v1/transactions?"+invalid, nil, http.StatusBadRequest, nil)
+	}
+}
+
+func TestE2E_TransactionPagination(t *testing.T) {
+	accountID := createAccount(t, "USD")
+	var created []uuid.UUID
+	for i := 0; i < 5; i++ {
+		created = append(created, createTransaction(t, accountID, int64(100+i), "USD", types.TransactionTypeCredit, uuid.New().String()))
+	}
+
+	type page struct {
+		Transactions []types.Transaction `json:"transactions"`
+		NextCursor   *string             `json:"next_cursor"`
+		PrevCursor   *string             `json:"prev_cursor"`
+	}
+	list := func(cursor *string) (page, []uuid.UUID) {
+		query := url.Values{"account_id": {accountID.String()}, "limit": {"2"}}
+		if cursor != nil {
+			query.Set("cursor", *cursor)
+		}
+		var p page
+		getJSON(t, "/v1/transactions?"+query.Encode(), &p)
+		ids := make([]uuid.UUID, len(p.Transactions))
+		for i, transaction := range p.Transactions {
+			ids[i] = transaction.ID
+		}
+		return p, ids
+	}
+
+	first, ids := list(nil)
+	assert.Equal(t, []uuid.UUID{created[4], created[3]}, ids)
+	assert.Nil(t, first.PrevCursor)
+	require.NotNil(t, first.NextCursor)
+
+	// A transaction created between pages does not shift the following ones
+	createTransaction(t, accountID, 500, "USD", types.TransactionTypeCredit, uuid.New().String())
+
+	second, ids := list(first.NextCursor)
+	assert.Equal(t, []uuid.UUID{created[2], created[1]}, ids)
+	require.NotNil(t, second.NextCursor)
+	require.NotNil(t, second.PrevCursor)
+
+	last, ids := list(second.NextCursor)
+	assert.Equal(t, []uuid.UUID{created[0]}, ids)
+	assert.Nil(t, last.NextCursor)
+
+	_, ids = list(second.PrevCursor)
+	assert.Equal(t, []uuid.UUID{created[4], created[3]}, ids)
+
+	// A cursor only continues the filters and sort it was issued for
+	requestAs(t, apiKey, http.MethodGet, "/v1/transactions?cursor=not-a-cursor", nil, http.StatusBadRequest, nil)
+	requestAs(t, apiKey, http.MethodGet, "/v1/transactions?sort=amount_cents&cursor="+url.QueryEscape(*first.NextCursor), nil, http.StatusBadRequest, nil)
+	requestAs(t, apiKey, http.MethodGet, "/v1/transactions?cursor="+url.QueryEscape(*first.NextCursor), nil, http.StatusBadRequest, nil)
+	requestAs(t, apiKey, http.MethodGet, fmt.Sprintf("/v1/transactions?account_id=%s&currency=EUR&cursor=%s", accountID, url.QueryEscape(*first.NextCursor)), nil, http.StatusBadRequest, nil)
+}
+
+func TestE2E_RecurringSchedule(t *testing.T) {
+	accountID := createAccount(t, "USD")
+
+	// The first occurrence is due at start_at and run by the schedule runner
+	startAt := time.Now().UTC().Truncate(time.Second)
+	var schedule types.RecurringSchedule
+	postJSON(t, "/v1/schedules", types.CreateScheduleRequest{
+		Template: types.TransactionTemplate{
+			AccountID:   accountID,
+			AmountCents: 1500,
+			Currency:    "USD",
+			Type:        types.TransactionTypeCredit,
+		},
+		RRule:   "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=12",
+		StartAt: &startAt,
+	}, http.StatusCreated, &schedule)
+	assert.Equal(t, types.ScheduleStatusActive, schedule.Status)
+	assert.Equal(t, "FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=12", schedule.RRule)
+	require.NotNil(t, schedule.NextRunAt)
+
+	deadline := time.Now().Add(90 * time.Second)
+	for schedule.OccurrenceCount == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Second)
+		getJSON(t, fmt.Sprintf("/v1/schedules/%s", schedule.ID), &schedule)
+	}
+	require.Equal(t, 1, schedule.OccurrenceCount)
+	require.NotNil(t, schedule.LastTransactionID)
+	assert.True(t, schedule.NextRunAt.After(startAt))
+
+	transaction := getTransaction(t, *schedule.LastTransactionID)
+	assert.Equal(t, fmt.Sprintf("schedule:%s:%s", schedule.ID, schedule.LastRunAt.UTC().Format(time.RFC3339)), transaction.IdempotencyKey)
+	waitForTransactionStatus(t, transaction.ID, types.TransactionStatusProcessed, 30*time.Second)
+
+	// Pause, then cancel
+	paused := types.ScheduleStatusPaused
+	patchJSON(t, fmt.Sprintf("/v1/schedules/%s", schedule.ID), types.UpdateScheduleRequest{Status: &paused}, http.StatusOK, &schedule)
+	assert.Equal(t, types.ScheduleStatusPaused, schedule.Status)
+
+	deleteJSON(t, fmt.Sprintf("/v1/schedules/%s", schedule.ID), http.StatusOK, &schedule)
+	assert.Equal(t, types.ScheduleStatusCancelled, schedule.Status)
+	deleteJSON(t, fmt.Sprintf("/v1/schedules/%s", schedule.ID), http.StatusConflict, nil)
+	patchJSON(t, fmt.Sprintf("/v1/schedules/%s", schedule.ID), types.UpdateScheduleRequest{Status: &paused}, http.StatusConflict, nil)
+}
+
+func TestE2E_TransactionBatch(t *testing.T) {
+	accountID := createAccount(t, "USD")
+	credit := func(amountCents int64) types.CreateTransactionRequest {
+		return types.CreateTransactionRequest{
+			AccountID:      accountID,
+			AmountCents:    amountCents,
+			Currency:       "USD",
+			Type:           types.TransactionTypeCredit,
+			IdempotencyKey: uuid.New().String(),
+		}
+	}
+
+	// ATOMIC: every item is committed
+	var result types.TransactionBatchResult
+	postJSON(t, "/v1/transactions/batch", types.CreateTransactionBatchRequest{
+		Mode:         types.BatchModeAtomic,
+		Transactions: []types.CreateTransactionRequest{credit(1000), credit(2000)},
+	}, http.StatusCreated, &result)
+	assert.True(t, result.Committed)
+	assert.Equal(t, 2, result.SucceededCount)
+	require.Len(t, result.Items, 2)
+	for _, item := range result.Items {
+		require.NotNil(t, item.Transaction)
+		waitForTransactionStatus(t, item.Transaction.ID, types.TransactionStatusProcessed, 30*time.Second)
+	}
+
+	// ATOMIC: one failing item rolls back the batch
+	missing := credit(500)
+	missing.AccountID = uuid.New()
+	rolledBack := credit(4000)
+	var failed types.TransactionBatchResult
+	postJSON(t, "/v1/transactions/batch", types.CreateTransactionBatchRequest{
+		Mode:         types.BatchModeAtomic,
+		Transactions: []types.CreateTransactionRequest{rolledBack, missing},
+	}, http.StatusUnprocessableEntity, &failed)
+	assert.False(t, failed.Committed)
+	require.Len(t, failed.Items, 2)
+	for i, item := range failed.Items {
+		assert.Equal(t, i, item.Index)
+		assert.False(t, item.Committed)
+		assert.Nil(t, item.Transaction)
+	}
+	assert.Equal(t, http.StatusFailedDependency, failed.Items[0].Status)
+	assert.Equal(t, http.StatusNotFound, failed.Items[1].Status)
+
+	// BEST_EFFORT: failing items are reported, the rest committed
+	invalid := credit(0)
+	postJSON(t, "/v1/transactions/batch", types.CreateTransactionBatchRequest{
+		Mode:         types.BatchModeBestEffort,
+		Transactions: []types.CreateTransactionRequest{rolledBack, invalid, missing, credit(3000)},
+	}, http.StatusOK, &result)
+	assert.True(t, result.Committed)
+	assert.Equal(t, 2, result.SucceededCount)
+	assert.Equal(t, 2, result.FailedCount)
+	require.Len(t, result.Items, 4)
+	statuses := []int{http.StatusCreated, http.StatusBadRequest, http.StatusNotFound, http.StatusCreated}
+	for i, item := range result.Items {
+		assert.Equal(t, i, item.Index)
+		assert.Equal(t, statuses[i], item.Status)
+	}
+	waitForTransactionStatus(t, result.Items[0].Transaction.ID, types.TransactionStatusProcessed, 30*time.Second)
+	waitForTransactionStatus(t, result.Items[3].Transaction.ID, types.TransactionStatusProcessed, 30*time.Second)
+
+	assert.Equal(t, int64(10000), getAccount(t, accountID).BalanceCents)
+}
+
+func TestE2E_DebitLimits(t *testing.T) {
+	// A tier of its own keeps the test independent of other accounts
+	tier := "E2E-" + uuid.New().String()[:8]
+	dailyLimit := int64(5000)
+	var tierLimits types.TierLimits
+	putJSON(t, "/v1/admin/limit-tiers/"+tier, types.TransactionLimits{MaxDailyDebitCents: &dailyLimit}, http.StatusOK, &tierLimits)
+
+	account := createAccountWithRequest(t, types.CreateAccountRequest{Currency: "USD", Tier: tier})
+	createTransaction(t, account.ID, 10000, "USD", types.TransactionTypeCredit, uuid.New().String())
+
+	// The account override wins over the tier for the single debit limit
+	singleLimit := int64(3000)
+	var accountLimits types.AccountLimits
+	putJSON(t, fmt.Sprintf("/v1/accounts/%s/limits", account.ID), types.SetAccountLimitsRequest{
+		Overrides: types.TransactionLimits{MaxSingleDebitCents: &singleLimit},
+	}, http.StatusOK, &accountLimits)
+	require.NotNil(t, accountLimits.Limits.MaxSingleDebitCents)
+	require.NotNil(t, accountLimits.Limits.MaxDailyDebitCents)
+	assert.Equal(t, singleLimit, *accountLimits.Limits.MaxSingleDebitCents)
+	assert.Equal(t, dailyLimit, *accountLimits.Limits.MaxDailyDebitCents)
+
+	// Over the single debit limit: failed by the API without reaching the worker
+	var rejected types.Transaction
+	postJSON(t, "/v1/transactions", types.CreateTransactionRequest{
+		AccountID:      account.ID,
+		AmountCents:    4000,
+		Currency:       "USD",
+		Type:           types.TransactionTypeDebit,
+		IdempotencyKey: uuid.New().String(),
+	}, http.StatusCreated, &rejected)
+	assert.Equal(t, types.TransactionStatusFailed, rejected.Status)
+	require.NotNil(t, rejected.FailureCode)
+	assert.Equal(t, types.FailureCodeSingleDebitLimit, *rejected.FailureCode)
+
+	// A card authorization is held to the same limits
+	postJSON(t, "/v1/transactions", types.CreateTransactionRequest{
+		AccountID:      account.ID,
+		AmountCents:    4000,
+		Currency:       "USD",
+		Type:           types.TransactionTypeAuthorize,
+		IdempotencyKey: uuid.New().String(),
+	}, http.StatusCreated, &rejected)
+	assert.Equal(t, types.TransactionStatusFailed, rejected.Status)
+	require.NotNil(t, rejected.FailureCode)
+	assert.Equal(t, types.FailureCodeSingleDebitLimit, *rejected.FailureCode)
+
+	debitID := createTransaction(t, account.ID, 3000, "USD", types.TransactionTypeDebit, uuid.New().String())
+	waitForTransactionStatus(t, debitID, types.TransactionStatusProcessed, 30*time.Second)
+
+	getJSON(t, fmt.Sprintf("/v1/accounts/%s/limits", account.ID), &accountLimits)
+	assert.Equal(t, int64(3000), accountLimits.Usage.DailyDebitCents)
+	require.NotNil(t, accountLimits.Headroom.DailyDebitCents)
+	assert.Equal(t, int64(2000), *accountLimits.Headroom.DailyDebitCents)
+	assert.Equal(t, int64(2000), *accountLimits.Headroom.SingleDebitCents)
+
+	// Over the daily total
+	postJSON(t, "/v1/transactions", types.CreateTransactionRequest{
+		AccountID:      account.ID,
+		AmountCents:    2500,
+		Currency:       "USD",
+		Type:           types.TransactionTypeDebit,
+		IdempotencyKey: uuid.New().String(),
+	}, http.StatusCreated, &rejected)
+	assert.Equal(t, types.TransactionStatusFailed, rejected.Status)
+	require.NotNil(t, rejected.FailureCode)
+	assert.Equal(t, types.FailureCodeDailyAmountLimit, *rejected.FailureCode)
+
+	assert.Equal(t, int64(7000), getAccount(t, account.ID).BalanceCents)
+}
+
+func TestE2E_RiskRules(t *testing.T) {
+	// Rules are global, so they only match transactions tagged by this test
+	tag := uuid.New().String()
+	reviewRule := types.RiskRule{
+		Name:     "e2e-review-" + tag,
+		Action:   types.RiskActionReview,
+		Priority: 10,
+		Conditions: []types.RiskCondition{
+			{Field: "metadata.e2e_tag", Op: types.RiskOpEq, Value: tag},
+			{Field: "amount_cents", Op: types.RiskOpGte, Value: 5000},
+		},
+		Enabled: true,
+	}
+	denyRule := types.RiskRule{
+		Name:     "e2e-deny-" + tag,
+		Action:   types.RiskActionDeny,
+		Priority: 5,
+		Conditions: []types.RiskCondition{
+			{Field: "metadata.e2e_tag", Op: types.RiskOpEq, Value: tag},
+			{Field: "metadata.country", Op: types.RiskOpIn, Value: []string{"XX"}},
+		},
+		Enabled: true,
+	}
+	for _, rule := range []types.RiskRule{reviewRule, denyRule} {
+		putJSON(t, "/v1/admin/risk-rules/"+rule.Name, rule, http.StatusOK, nil)
+		name := rule.Name
+		t.Cleanup(func() { deleteJSON(t, "/v1/admin/risk-rules/"+name, http.StatusNoContent, nil) })
+	}
+	// Let the worker pick up the new rules (RISK_RULES_REFRESH_INTERVAL)
+	time.Sleep(3 * time.Second)
+
+	accountID := createAccount(t, "USD")
+	creditID := createTransaction(t, accountID, 10000, "USD", types.TransactionTypeCredit, uuid.New().String())
+	waitForTransactionStatus(t, creditID, types.TransactionStatusProcessed, 30*time.Second)
+
+	debit := func(amountCents int64, metadata map[string]string) uuid.UUID {
+		metadata["e2e_tag"] = tag
+		body, _ := json.Marshal(metadata)
+		return createTransactionWithRequest(t, types.CreateTransactionRequest{
+			AccountID:      accountID,
+			AmountCents:    amountCents,
+			Currency:       "USD",
+			Type:           types.TransactionTypeDebit,
+			IdempotencyKey: uuid.New().String(),
+			Metadata:       body,
+		})
+	}
+
+	// Rejected in review
+	rejectedID := debit(6000, map[string]string{})
+	waitForTransactionStatus(t, rejectedID, types.TransactionStatusInReview, 30*time.Second)
+	var rejected types.Transaction
+	postJSON(t, fmt.Sprintf("/v1/transactions/%s/reject", rejectedID), types.ReviewTransactionRequest{Note: "unknown merchant"}, http.StatusOK, &rejected)
+	assert.Equal(t, types.TransactionStatusFailed, rejected.Status)
+	require.NotNil(t, rejected.FailureCode)
+	assert.Equal(t, types.FailureCodeReviewRejected, *rejected.FailureCode)
+	require.NotNil(t, rejected.ReviewDecision)
+	assert.Equal(t, types.ReviewDecisionRejected, *rejected.ReviewDecision)
+	postJSON(t, fmt.Sprintf("/v1/transactions/%s/approve", rejectedID), nil, http.StatusConflict, nil)
+
+	// Approved in review, then applied without being screened again
+	approvedID := debit(5000, map[string]string{})
+	waitForTransactionStatus(t, approvedID, types.TransactionStatusInReview, 30*time.Second)
+	review := getTransaction(t, approvedID)
+	require.NotNil(t, review.ReviewReason)
+	assert.Contains(t, *review.ReviewReason, reviewRule.Name)
+	postJSON(t, fmt.Sprintf("/v1/transactions/%s/approve", approvedID), nil, http.StatusOK, nil)
+	waitForTransactionStatus(t, approvedID, types.TransactionStatusProcessed, 30*time.Second)
+
+	// Denied outright
+	deniedID := debit(100, map[string]string{"country": "XX"})
+	waitForTransactionStatus(t, deniedID, types.TransactionStatusFailed, 30*time.Second)
+	denied := getTransaction(t, deniedID)
+	require.NotNil(t, denied.FailureCode)
+	assert.Equal(t, types.FailureCodeRiskDenied, *denied.FailureCode)
+
+	// The debit leg of a transfer is screened like a debit
+	toAccountID := createAccount(t, "USD")
+	metadata, _ := json.Marshal(map[string]string{"e2e_tag": tag, "country": "XX"})
+	var transfer types.Transfer
+	postJSON(t, "/v1/transfers", types.CreateTransferRequest{
+		FromAccountID:  accountID,
+		ToAccountID:    toAccountID,
+		AmountCents:    100,
+		Currency:       "USD",
+		IdempotencyKey: uuid.New().String(),
+		Metadata:       metadata,
+	}, http.StatusCreated, &transfer)
+	waitForTransactionStatus(t, transfer.CreditTransactionID, types.TransactionStatusFailed, 30*time.Second)
+	deniedLeg := getTransaction(t, transfer.DebitTransactionID)
+	assert.Equal(t, types.TransactionStatusFailed, deniedLeg.Status)
+	require.NotNil(t, deniedLeg.FailureCode)
+	assert.Equal(t, types.FailureCodeRiskDenied, *deniedLeg.FailureCode)
+
+	assert.Equal(t, int64(5000), getAccount(t, accountID).BalanceCents)
+	assert.Equal(t, int64(0), getAccount(t, toAccountID).BalanceCents)
+}
+
+func TestE2E_Fees(t *testing.T) {
+	// XTS is the ISO 4217 testing code, so the schedule touches no other test
+	minFee, maxFee := int64(50), int64(500)
+	var schedule types.FeeSchedule
+	putJSON(t, "/v1/admin/fee-schedules/DEBIT/XTS", types.SetFeeScheduleRequest{
+		FlatCents: 25,
+		RateBps:   100,
+		MinCents:  &minFee,
+		MaxCents:  &maxFee,
+		Tiers:     []types.FeeTier{{MinMonthlyVolumeCents: 100000, RateBps: 50}},
+	}, http.StatusOK, &schedule)
+	t.Cleanup(func() { deleteJSON(t, "/v1/admin/fee-schedules/DEBIT/XTS", http.StatusNoContent, nil) })
+
+	accountID := createAccount(t, "XTS")
+	creditID := createTransaction(t, accountID, 1000000, "XTS", types.TransactionTypeCredit, uuid.New().String())
+	waitForTransactionStatus(t, creditID, types.TransactionStatusProcessed, 30*time.Second)
+	assert.Nil(t, getTransaction(t, creditID).FeeCents)
+
+	cases := []struct {
+		amountCents int64
+		feeCents    int64
+	}{
+		{10000, 125},  // 25 + 1%
+		{100000, 500}, // capped at max_cents
+		{1000, 50},    // tier reached: 0.5%, raised to min_cents
+	}
+	for _, c := range cases {
+		debitID := createTransaction(t, accountID, c.amountCents, "XTS", types.TransactionTypeDebit, uuid.New().String())
+		waitForTransactionStatus(t, debitID, types.TransactionStatusProcessed, 30*time.Second)
+
+		debit := getTransaction(t, debitID)
+		require.NotNil(t, debit.FeeCents)
+		assert.Equal(t, c.feeCents, *debit.FeeCents)
+		require.NotNil(t, debit.FeeTransactionID)
+
+		fee := getTransaction(t, *debit.FeeTransactionID)
+		assert.Equal(t, types.TransactionStatusProcessed, fee.Status)
+		assert.Equal(t, types.TransactionTypeDebit, fee.Type)
+		assert.Equal(t, c.feeCents, fee.AmountCents)
+		require.NotNil(t, fee.FeeForTransactionID)
+		assert.Equal(t, debitID, *fee.FeeForTransactionID)
+	}
+
+	assert.Equal(t, int64(1000000-10000-125-100000-500-1000-50), getAccount(t, accountID).BalanceCents)
+}
+
+func TestE2E_Interest(t *testing.T) {
+	accountID := createAccount(t, "USD")
+
+	rate := "0.0425"
+	var account types.Account
+	putJSON(t, fmt.Sprintf("/v1/accounts/%s/interest", accountID), types.SetInterestRequest{
+		AnnualRate: &rate,
+		DayCount:   types.DayCount30360,
+	}, http.StatusOK, &account)
+	require.NotNil(t, account.InterestRate)
+	assert.Equal(t, rate, *account.InterestRate)
+	require.NotNil(t, account.InterestDayCount)
+	assert.Equal(t, types.DayCount30360, *account.InterestDayCount)
+
+	invalid := "1.5"
+	putJSON(t, fmt.Sprintf("/v1/accounts/%s/interest", accountID), types.SetInterestRequest{AnnualRate: &invalid}, http.StatusBadRequest, nil)
+	putJSON(t, fmt.Sprintf("/v1/accounts/%s/interest", accountID), types.SetInterestRequest{AnnualRate: &rate, DayCount: "ACT/360"}, http.StatusBadRequest, nil)
+
+	// Interest accrues from today, so no day has ended yet
+	var accruals struct {
+		Items []types.InterestAccrual `json:"items"`
+	}
+	getJSON(t, fmt.Sprintf("/v1/accounts/%s/interest-accruals", accountID), &accruals)
+	assert.Empty(t, accruals.Items)
+
+	putJSON(t, fmt.Sprintf("/v1/accounts/%s/interest", accountID), types.SetInterestRequest{}, http.StatusOK, &account)
+	assert.Nil(t, account.InterestRate)
+	assert.Nil(t, account.InterestDayCount)
+}
+
+func TestE2E_Customers(t *testing.T) {
+	email := "ada@example.com"
+	var alice, bob types.Customer
+	postJSON(t, "/v1/customers", types.CreateCustomerRequest{FullName: "Alice", Email: &email}, http.StatusCreated, &alice)
+	postJSON(t, "/v1/customers", types.CreateCustomerRequest{FullName: "Bob"}, http.StatusCreated, &bob)
+	postJSON(t, "/v1/customers", types.CreateCustomerRequest{FullName: ""}, http.StatusBadRequest, nil)
+
+	name := "Alice Smith"
+	var updated types.Customer
+	patchJSON(t, fmt.Sprintf("/v1/customers/%s", alice.ID), types.UpdateCustomerRequest{FullName: &name}, http.StatusOK, &updated)
+	assert.Equal(t, name, updated.FullName)
+	require.NotNil(t, updated.Email)
+	assert.Equal(t, email, *updated.Email)
+
+	// Alice owns a USD and a EUR account and shares a second USD account with Bob
+	usd := createAccountWithRequest(t, types.CreateAccountRequest{Currency: "USD", CustomerID: &alice.ID})
+	eur := createAccountWithRequest(t, types.CreateAccountRequest{Currency: "EUR", CustomerID: &alice.ID})
+	joint := createAccountWithRequest(t, types.CreateAccountRequest{Currency: "USD", CustomerID: &bob.ID, JointOwnerIDs: []uuid.UUID{alice.ID}})
+	require.NotNil(t, usd.CustomerID)
+	assert.Equal(t, alice.ID, *usd.CustomerID)
+
+	for _, credit := range []struct {
+		accountID uuid.UUID
+		amount    int64
+		currency  string
+	}{{usd.ID, 10000, "USD"}, {eur.ID, 5000, "EUR"}, {joint.ID, 2500, "USD"}} {
+		id := createTransaction(t, credit.accountID, credit.amount, credit.currency, types.TransactionTypeCredit, uuid.New().String())
+		waitForTransactionStatus(t, id, types.TransactionStatusProcessed, 30*time.Second)
+	}
+
+	var holdings types.CustomerAccounts
+	getJSON(t, fmt.Sprintf("/v1/customers/%s/accounts", alice.ID), &holdings)
+	require.Len(t, holdings.Accounts, 3)
+	roles := make(map[uuid.UUID]types.AccountOwnerRole)
+	for _, account := range holdings.Accounts {
+		roles[account.ID] = account.Role
+	}
+	assert.Equal(t, types.AccountOwnerRolePrimary, roles[usd.ID])
+	assert.Equal(t, types.AccountOwnerRoleJoint, roles[joint.ID])
+	require.Len(t, holdings.Balances, 2)
+	assert.Equal(t, "EUR", holdings.Balances[0].Currency)
+	assert.Equal(t, int64(5000), holdings.Balances[0].BalanceCents)
+	assert.Equal(t, "USD", holdings.Balances[1].Currency)
+	assert.Equal(t, 2, holdings.Balances[1].AccountCount)
+	assert.Equal(t, int64(12500), holdings.Balances[1].BalanceCents)
+
+	// The primary owner stays; a joint owner can be removed
+	deleteJSON(t, fmt.Sprintf("/v1/accounts/%s/owners/%s", joint.ID, bob.ID), http.StatusConflict, nil)
+	deleteJSON(t, fmt.Sprintf("/v1/accounts/%s/owners/%s", joint.ID, alice.ID), http.StatusNoContent, nil)
+	getJSON(t, fmt.Sprintf("/v1/customers/%s/accounts", alice.ID), &holdings)
+	assert.Len(t, holdings.Accounts, 2)
+
+	var owners struct {
+		Items []types.AccountOwner `json:"items"`
+	}
+	postJSON(t, fmt.Sprintf("/v1/accounts/%s/owners", joint.ID), types.AddAccountOwnerRequest{CustomerID: alice.ID}, http.StatusCreated, &owners)
+	require.Len(t, owners.Items, 2)
+	assert.Equal(t, bob.ID, owners.Items[0].CustomerID)
+	postJSON(t, fmt.Sprintf("/v1/accounts/%s/owners", joint.ID), types.AddAccountOwnerRequest{CustomerID: alice.ID}, http.StatusConflict, nil)
+
+	missing := uuid.New()
+	postJSON(t, "/v1/accounts", types.CreateAccountRequest{Currency: "USD", CustomerID: &missing}, http.StatusNotFound, nil)
+}
+
+func TestE2E_ListAccounts(t *testing.T) {
+	// Scoped to one owner so accounts of other tests do not match
+	var owner types.Customer
+	postJSON(t, "/v1/customers", types.CreateCustomerRequest{FullName: "Grace"}, http.StatusCreated, &owner)
+	usd := createAccountWithRequest(t, types.CreateAccountRequest{Currency: "USD", CustomerID: &owner.ID})
+	eur := createAccountWithRequest(t, types.CreateAccountRequest{Currency: "EUR", CustomerID: &owner.ID})
+	savings := createAccountWithRequest(t, types.CreateAccountRequest{Currency: "USD", CustomerID: &owner.ID})
+
+	for _, credit := range []struct {
+		accountID uuid.UUID
+		amount    int64
+		currency  string
+	}{{usd.ID, 10000, "USD"}, {eur.ID, 5000, "EUR"}, {savings.ID, 2500, "USD"}} {
+		id := createTransaction(t, credit.accountID, credit.amount, credit.currency, types.TransactionTypeCredit, uuid.New().String())
+		waitForTransactionStatus(t, id, types.TransactionStatusProcessed, 30*time.Second)
+	}
+	postJSON(t, fmt.Sprintf("/v1/accounts/%s/suspend", savings.ID), types.ChangeAccountStatusRequest{
+		ReasonCode: types.AccountStatusReasonDormant,
+	}, http.StatusOK, nil)
+
+	type listResponse struct {
+		Accounts   []types.Account      `json:"accounts"`
+		Totals     []types.AccountTotal `json:"totals"`
+		NextCursor *string              `json:"next_cursor"`
+		PrevCursor *string              `json:"prev_cursor"`
+	}
+	list := func(query url.Values) (listResponse, []uuid.UUID) {
+		query.Set("owner_id", owner.ID.String())
+		var listed listResponse
+		getJSON(t, "/v1/accounts?"+query.Encode(), &listed)
+		ids := make([]uuid.UUID, len(listed.Accounts))
+		for i, account := range listed.Accounts {
+			ids[i] = account.ID
+		}
+		return listed, ids
+	}
+
+	all, ids := list(url.Values{})
+	assert.Equal(t, []uuid.UUID{savings.ID, eur.ID, usd.ID}, ids)
+	assert.Nil(t, all.NextCursor)
+	require.Len(t, all.Totals, 2)
+	assert.Equal(t, "EUR", all.Totals[0].Currency)
+	assert.Equal(t, 1, all.Totals[0].AccountCount)
+	assert.Equal(t, int64(5000), all.Totals[0].BalanceCents)
+	assert.Equal(t, "USD", all.Totals[1].Currency)
+	assert.Equal(t, 2, all.Totals[1].AccountCount)
+	assert.Equal(t, int64(12500), all.Totals[1].BalanceCents)
+	assert.Equal(t, "125.00", all.Totals[1].BalanceDecimal)
+
+	_, ids = list(url.Values{"status": {"ACTIVE"}, "currency": {"USD"}})
+	assert.Equal(t, []uuid.UUID{usd.ID}, ids)
+	_, ids = list(url.Values{"min_balance_cents": {"3000"}, "sort": {"balance_cents"}})
+	assert.Equal(t, []uuid.UUID{eur.ID, usd.ID}, ids)
+
+	// Totals cover every matching account, not just the page
+	first, ids := list(url.Values{"limit": {"2"}})
+	assert.Equal(t, []uuid.UUID{savings.ID, eur.ID}, ids)
+	assert.Len(t, first.Totals, 2)
+	require.NotNil(t, first.NextCursor)
+	second, ids := list(url.Values{"limit": {"2"}, "cursor": {*first.NextCursor}})
+	assert.Equal(t, []uuid.UUID{usd.ID}, ids)
+	assert.Nil(t, second.NextCursor)
+	require.NotNil(t, second.PrevCursor)
+
+	// A cursor only continues the filters it was issued for
+	mismatched := url.Values{"owner_id": {owner.ID.String()}, "currency": {"USD"}, "limit": {"2"}, "cursor": {*first.NextCursor}}
+	requestAs(t, apiKey, http.MethodGet, "/v1/accounts?"+mismatched.Encode(), nil, http.StatusBadRequest, nil)
+
+	// Accounts of another tenant are never listed
+	var tenantB listResponse
+	requestAs(t, tenantBAPIKey, http.MethodGet, "/v1/accounts?owner_id="+owner.ID.String(), nil, http.StatusOK, &tenantB)
+	assert.Empty(t, tenantB.Accounts)
+	assert.Empty(t, tenantB.Totals)
+
+	for _, invalid := range []string{"status=OPEN", "owner_id=grace", "min_balance_cents=ten", "sort=-id", "cursor=abc"} {
+		requestAs(t, apiKey, http.MethodGet, "/v1/accounts?"+invalid, nil, http.StatusBadRequest, nil)
+	}
+}
+
+func TestE2E_Currencies(t *testing.T) {
+	for _, code := range []string{"usd", "XYZ", ""} {
+		postJSON(t, "/v1/accounts", types.CreateAccountRequest{Currency: code}, http.StatusBadRequest, nil)
+	}
+
+	usdAccountID := createAccount(t, "USD")
+	postJSON(t, "/v1/transactions", types.CreateTransactionRequest{
+		AccountID:      usdAccountID,
+		AmountCents:    100,
+		Currency:       "ABC",
+		Type:           types.TransactionTypeCredit,
+		IdempotencyKey: uuid.New().String(),
+	}, http.StatusBadRequest, nil)
+
+	// JPY has no minor unit and KWD has three
+	jpyAccountID := createAccount(t, "JPY")
+	jpyCreditID := createTransaction(t, jpyAccountID, 1500, "JPY", types.TransactionTypeCredit, uuid.New().String())
+	waitForTransactionStatus(t, jpyCreditID, types.TransactionStatusProcessed, 30*time.Second)
+	assert.Equal(t, "1500", getTransaction(t, jpyCreditID).AmountDecimal)
+
+	kwdAccountID := createAccount(t, "KWD")
+	kwdCreditID := createTransaction(t, kwdAccountID, 1234, "KWD", types.TransactionTypeCredit, uuid.New().String())
+	waitForTransactionStatus(t, kwdCreditID, types.TransactionStatusProcessed, 30*time.Second)
+	kwdAccount := getAccount(t, kwdAccountID)
+	assert.Equal(t, "1.234", kwdAccount.BalanceDecimal)
+	assert.Equal(t, "1.234", kwdAccount.AvailableBalanceDecimal)
+
+	// 1.00 USD at 150 JPY per USD is 150 yen, not 15000
+	postJSON(t, "/v1/fx/rates", types.CreateFXRateRequest{
+		BaseCurrency:  "USD",
+		QuoteCurrency: "JPY",
+		Rate:          "150",
+	}, http.StatusCreated, nil)
+	convertedID := createTransaction(t, jpyAccountID, 100, "USD", types.TransactionTypeCredit, uuid.New().String())
+	waitForTransactionStatus(t, convertedID, types.TransactionStatusProcessed, 30*time.Second)
+	converted := getTransaction(t, convertedID)
+	assert.Equal(t, "1.00", converted.AmountDecimal)
+	require.NotNil(t, converted.SettledAmountCents)
+	assert.Equal(t, int64(150), *converted.SettledAmountCents)
+	assert.Equal(t, "1650", getAccount(t, jpyAccountID).BalanceDecimal)
+
+	var currencies struct {
+		Items []currency.Currency `json:"items"`
+	}
+	getJSON(t, "/v1/currencies", &currencies)
+	exponents := make(map[string]int)
+	for _, c := range currencies.Items {
+		exponents[c.Code] = c.Exponent
+	}
+	assert.Equal(t, 0, exponents["JPY"])
+	assert.Equal(t, 3, exponents["KWD"])
+	assert.Equal(t, 2, exponents["USD"])
+}
+
+func TestE2E_TenantIsolation(t *testing.T) {
+	defaultAccount := createAccountWithRequest(t, types.CreateAccountRequest{Currency: "USD"})
+	assert.Equal(t, "default", defaultAccount.TenantID)
+	defaultTxID := createTransaction(t, defaultAccount.ID, 1000, "USD", types.TransactionTypeCredit, "tenant-default-credit-"+uuid.New().String())
+	waitForTransactionStatus(t, defaultTxID, types.TransactionStatusProcessed, 10*time.Second)
+
+	var tenantAccount types.Account
+	requestAs(t, tenantBAPIKey, "POST", "/v1/accounts", types.CreateAccountRequest{Currency: "USD"}, http.StatusCreated, &tenantAccount)
+	assert.Equal(t, "tenant-b", tenantAccount.TenantID)
+
+	// tenant-b's own transactions are processed by the worker as usual
+	var credit types.Transaction
+	requestAs(t, tenantBAPIKey, "POST", "/v1/transactions", types.CreateTransactionRequest{
+		AccountID:      tenantAccount.ID,
+		AmountCents:    500,
+		Currency:       "USD",
+		Type:           types.TransactionTypeCredit,
+		IdempotencyKey: "tenant-b-credit-" + uuid.New().String(),
+	}, http.StatusCreated, &credit)
+	assert.Equal(t, "tenant-b", credit.TenantID)
+	require.Eventually(t, func() bool {
+		var transaction types.Transaction
+		requestAs(t, tenantBAPIKey, "GET", fmt.Sprintf("/v1/transactions/%s", credit.ID), nil, http.StatusOK, &transaction)
+		return transaction.Status == types.TransactionStatusProcessed
+	}, 10*time.Second, 200*time.Millisecond)
+
+	// Neither tenant sees the other's accounts or transactions
+	requestAs(t, tenantBAPIKey, "GET", fmt.Sprintf("/v1/accounts/%s", defaultAccount.ID), nil, http.StatusNotFound, nil)
+	requestAs(t, tenantBAPIKey, "GET", fmt.Sprintf("/v1/transactions/%s", defaultTxID), nil, http.StatusNotFound, nil)
+	requestAs(t, tenantBAPIKey, "GET", fmt.Sprintf("/v1/accounts/%s/balance", defaultAccount.ID), nil, http.StatusNotFound, nil)
+	requestAs(t, apiKey, "GET", fmt.Sprintf("/v1/accounts/%s", tenantAccount.ID), nil, http.StatusNotFound, nil)
+	requestAs(t, apiKey, "GET", fmt.Sprintf("/v1/transactions/%s", credit.ID), nil, http.StatusNotFound, nil)
+
+	var listed struct {
+		Transactions []types.Transaction `json:"transactions"`
+	}
+	requestAs(t, apiKey, "GET", fmt.Sprintf("/v1/transactions?account_id=%s", tenantAccount.ID), nil, http.StatusOK, &listed)
+	assert.Empty(t, listed.Transactions)
+
+	// Nor can they move money on the other's accounts
+	requestAs(t, tenantBAPIKey, "POST", "/v1/transactions", types.CreateTransactionRequest{
+		AccountID:      defaultAccount.ID,
+		AmountCents:    100,
+		Currency:       "USD",
+		Type:           types.TransactionTypeDebit,
+		IdempotencyKey: "tenant-b-steal-" + uuid.New().String(),
+	}, http.StatusNotFound, nil)
+	requestAs(t, tenantBAPIKey, "POST", "/v1/transfers", types.CreateTransferRequest{
+		FromAccountID:  tenantAccount.ID,
+		ToAccountID:    defaultAccount.ID,
+		AmountCents:    100,
+		Currency:       "USD",
+		IdempotencyKey: "tenant-b-transfer-" + uuid.New().String(),
+	}, http.StatusNotFound, nil)
+	requestAs(t, tenantBAPIKey, "POST", fmt.Sprintf("/v1/accounts/%s/suspend", defaultAccount.ID), types.ChangeAccountStatusRequest{ReasonCode: types.AccountStatusReasonCustomerRequest}, http.StatusNotFound, nil)
+
+	assert.Equal(t, int64(1000), getAccount(t, defaultAccount.ID).BalanceCents)
+
+	// Customers belong to a tenant too, and cannot own another tenant's accounts
+	var customer types.Customer
+	postJSON(t, "/v1/customers", types.CreateCustomerRequest{FullName: "Default Tenant Customer"}, http.StatusCreated, &customer)
+	name := "Renamed"
+	requestAs(t, tenantBAPIKey, "GET", fmt.Sprintf("/v1/customers/%s", customer.ID), nil, http.StatusNotFound, nil)
+	requestAs(t, tenantBAPIKey, "PATCH", fmt.Sprintf("/v1/customers/%s", customer.ID), types.UpdateCustomerRequest{FullName: &name}, http.StatusNotFound, nil)
+	requestAs(t, tenantBAPIKey, "GET", fmt.Sprintf("/v1/customers/%s/accounts", customer.ID), nil, http.StatusNotFound, nil)
+	requestAs(t, tenantBAPIKey, "POST", "/v1/accounts", types.CreateAccountRequest{Currency: "USD", CustomerID: &customer.ID}, http.StatusNotFound, nil)
+
+	var tenantCustomer types.Customer
+	requestAs(t, tenantBAPIKey, "POST", "/v1/customers", types.CreateCustomerRequest{FullName: "Tenant B Customer"}, http.StatusCreated, &tenantCustomer)
+	var ownedAccount types.Account
+	requestAs(t, tenantBAPIKey, "POST", "/v1/accounts", types.CreateAccountRequest{Currency: "USD", CustomerID: &tenantCustomer.ID}, http.StatusCreated, &ownedAccount)
+	requestAs(t, tenantBAPIKey, "POST", fmt.Sprintf("/v1/accounts/%s/owners", ownedAccount.ID), types.AddAccountOwnerRequest{CustomerID: customer.ID}, http.StatusNotFound, nil)
+
+	var fetched types.Customer
+	getJSON(t, fmt.Sprintf("/v1/customers/%s", customer.ID), &fetched)
+	assert.Equal(t, "Default Tenant Customer", fetched.FullName)
+
+	// Schedules belong to the tenant of their account
+	startAt := time.Now().UTC().AddDate(1, 0, 0)
+	var schedule types.RecurringSchedule
+	postJSON(t, "/v1/schedules", types.CreateScheduleRequest{
+		Template: types.TransactionTemplate{
+			AccountID:   defaultAccount.ID,
+			AmountCents: 100,
+			Currency:    "USD",
+			Type:        types.TransactionTypeCredit,
+		},
+		RRule:   "FREQ=MONTHLY;COUNT=1",
+		StartAt: &startAt,
+	}, http.StatusCreated, &schedule)
+	paused := types.ScheduleStatusPaused
+	requestAs(t, tenantBAPIKey, "GET", fmt.Sprintf("/v1/schedules/%s", schedule.ID), nil, http.StatusNotFound, nil)
+	requestAs(t, tenantBAPIKey, "PATCH", fmt.Sprintf("/v1/schedules/%s", schedule.ID), types.UpdateScheduleRequest{Status: &paused}, http.StatusNotFound, nil)
+	requestAs(t, tenantBAPIKey, "DELETE", fmt.Sprintf("/v1/schedules/%s", schedule.ID), nil, http.StatusNotFound, nil)
+
+	var schedules struct {
+		Items []types.RecurringSchedule `json:"items"`
+	}
+	requestAs(t, tenantBAPIKey, "GET", fmt.Sprintf("/v1/schedules?account_id=%s", defaultAccount.ID), nil, http.StatusOK, &schedules)
+	assert.Empty(t, schedules.Items)
+
+	getJSON(t, fmt.Sprintf("/v1/schedules/%s", schedule.ID), &schedule)
+	assert.Equal(t, types.ScheduleStatusActive, schedule.Status)
+	deleteJSON(t, fmt.Sprintf("/v1/schedules/%s", schedule.ID), http.StatusOK, nil)
+
+	// Configuration shared by all tenants is left to the default tenant
+	requestAs(t, tenantBAPIKey, "GET", "/v1/admin/reconciliations", nil, http.StatusForbidden, nil)
+	requestAs(t, tenantBAPIKey, "PUT", "/v1/admin/risk-rules/tenant-b-rule", nil, http.StatusForbidden, nil)
+	requestAs(t, tenantBAPIKey, "DELETE", "/v1/admin/fee-schedules/DEBIT/USD", nil, http.StatusForbidden, nil)
+	requestAs(t, tenantBAPIKey, "POST", "/v1/fx/rates", nil, http.StatusForbidden, nil)
+	requestAs(t, tenantBAPIKey, "GET", "/v1/fx/rates", nil, http.StatusOK, nil)
+	requestAs(t, apiKey, "GET", "/v1/admin/limit-tiers", nil, http.StatusOK, nil)
+
+	// Unknown keys are rejected
+	requestAs(t, "not-a-key", "GET", fmt.Sprintf("/v1/accounts/%s", defaultAccount.ID), nil, http.StatusUnauthorized, nil)
+}
+
 func createAccount(t *testing.T, currency string) uuid.UUID {
-	req := types.CreateAccountRequest{
+	return createAccountWithRequest(t, types.CreateAccountRequest{
 		Currency: currency,
-	}
+	}).ID
+}
 
+func createAccountWithRequest(t *testing.T, req types.CreateAccountRequest) *types.Account {
 	body, _ := json.Marshal(req)
 	resp, err := http.Post(
 		fmt.Sprintf("%s/v1/accounts", apiBaseURL),
@@ -90,18 +1275,20 @@ func createAccount(t *testing.T, currency string) uuid.UUID {
 	err = json.NewDecoder(resp.Body).Decode(&account)
 	require.NoError(t, err)
 
-	return account.ID
+	return &account
 }
 
 func createTransaction(t *testing.T, accountID uuid.UUID, amountCents int64, currency string, txType types.TransactionType, idempotencyKey string) uuid.UUID {
-	req := types.CreateTransactionRequest{
+	return createTransactionWithRequest(t, types.CreateTransactionRequest{
 		AccountID:      accountID,
 		AmountCents:    amountCents,
 		Currency:       currency,
 		Type:           txType,
 		IdempotencyKey: idempotencyKey,
-	}
+	})
+}
 
+func createTransactionWithRequest(t *testing.T, req types.CreateTransactionRequest) uuid.UUID {
 	body, _ := json.Marshal(req)
 	httpReq, _ := http.NewRequest("POST", fmt.Sprintf("%s/v1/transactions", apiBaseURL), bytes.NewBuffer(body))
 	httpReq.Header.Set("Content-Type", "application/json")
@@ -121,6 +1308,190 @@ func createTransaction(t *testing.T, accountID uuid.UUID, amountCents int64, cur
 	return transaction.ID
 }
 
+func createTransfer(t *testing.T, fromAccountID, toAccountID uuid.UUID, amountCents int64, currency string, idempotencyKey string) *types.Transfer {
+	req := types.CreateTransferRequest{
+		FromAccountID:  fromAccountID,
+		ToAccountID:    toAccountID,
+		AmountCents:    amountCents,
+		Currency:       currency,
+		IdempotencyKey: idempotencyKey,
+	}
+
+	body, _ := json.Marshal(req)
+	httpReq, _ := http.NewRequest("POST", fmt.Sprintf("%s/v1/transfers", apiBaseURL), bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", apiKey)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var transfer types.Transfer
+	err = json.NewDecoder(resp.Body).Decode(&transfer)
+	require.NoError(t, err)
+
+	return &transfer
+}
+
+func reverseTransaction(t *testing.T, transactionID uuid.UUID, amountCents int64, idempotencyKey string) (int, *types.Transaction) {
+	req := types.ReverseTransactionRequest{
+		AmountCents:    amountCents,
+		IdempotencyKey: idempotencyKey,
+	}
+
+	body, _ := json.Marshal(req)
+	httpReq, _ := http.NewRequest("POST", fmt.Sprintf("%s/v1/transactions/%s/reverse", apiBaseURL, transactionID), bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", apiKey)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return resp.StatusCode, nil
+	}
+
+	var transaction types.Transaction
+	err = json.NewDecoder(resp.Body).Decode(&transaction)
+	require.NoError(t, err)
+
+	return resp.StatusCode, &transaction
+}
+
+func updateOverdraftLimit(t *testing.T, accountID uuid.UUID, overdraftLimitCents int64) *types.Account {
+	req := types.UpdateOverdraftLimitRequest{
+		OverdraftLimitCents: overdraftLimitCents,
+		Reason:              "e2e limit increase",
+	}
+
+	body, _ := json.Marshal(req)
+	httpReq, _ := http.NewRequest("PUT", fmt.Sprintf("%s/v1/accounts/%s/overdraft-limit", apiBaseURL, accountID), bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", apiKey)
+	httpReq.Header.Set("X-Actor", "e2e")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var account types.Account
+	err = json.NewDecoder(resp.Body).Decode(&account)
+	require.NoError(t, err)
+
+	return &account
+}
+
+func postJSON(t *testing.T, path string, req interface{}, expectedStatus int, out interface{}) {
+	body, _ := json.Marshal(req)
+	httpReq, _ := http.NewRequest("POST", apiBaseURL+path, bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", apiKey)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, expectedStatus, resp.StatusCode)
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+}
+
+func putJSON(t *testing.T, path string, req interface{}, expectedStatus int, out interface{}) {
+	body, _ := json.Marshal(req)
+	httpReq, _ := http.NewRequest("PUT", apiBaseURL+path, bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", apiKey)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, expectedStatus, resp.StatusCode)
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+}
+
+func patchJSON(t *testing.T, path string, req interface{}, expectedStatus int, out interface{}) {
+	body, _ := json.Marshal(req)
+	httpReq, _ := http.NewRequest("PATCH", apiBaseURL+path, bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", apiKey)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, expectedStatus, resp.StatusCode)
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+}
+
+func deleteJSON(t *testing.T, path string, expectedStatus int, out interface{}) {
+	httpReq, _ := http.NewRequest("DELETE", apiBaseURL+path, nil)
+	httpReq.Header.Set("X-API-Key", apiKey)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, expectedStatus, resp.StatusCode)
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+}
+
+func getJSON(t *testing.T, path string, out interface{}) {
+	httpReq, _ := http.NewRequest("GET", apiBaseURL+path, nil)
+	httpReq.Header.Set("X-API-Key", apiKey)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+}
+
+// requestAs sends a request authenticated with the given API key
+func requestAs(t *testing.T, key, method, path string, req interface{}, expectedStatus int, out interface{}) {
+	var body *bytes.Buffer
+	if req != nil {
+		payload, _ := json.Marshal(req)
+		body = bytes.NewBuffer(payload)
+	} else {
+		body = &bytes.Buffer{}
+	}
+	httpReq, _ := http.NewRequest(method, apiBaseURL+path, body)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("X-API-Key", key)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, expectedStatus, resp.StatusCode)
+	if out != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+}
+
 func getAccount(t *testing.T, accountID uuid.UUID) *types.Account {
 	httpReq, _ := http.NewRequest("GET", fmt.Sprintf("%s/v1/accounts/%s", apiBaseURL, accountID.String()), nil)
 	httpReq.Header.Set("X-API-Key", apiKey)
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/yash/transaction-system/shared/config"
+	"github.com/yash/transaction-system/shared/db"
+	"github.com/yash/transaction-system/worker/internal/reconcile"
+	"go.uber.org/zap"
+)
+
+// exitDrift is the exit status of a run that found drifted balances, so cron
+// and CI can tell drift from a failed run (status 1)
+const exitDrift = 2
+
+// reconcile runs one balance reconciliation pass and exits. Run it on demand
+// or from cron, e.g. nightly with repair:
+//
+//	reconcile -repair
+func main() {
+	repair := flag.Bool("repair", false, "reset drifted balances to the expected balance when the ledger agrees; also enabled by RECONCILIATION_AUTO_REPAIR")
+	flag.Parse()
+
+	// Initialize logger
+	logger, err := initLogger()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to initialize logger: %v", err))
+	}
+	defer func() {
+		_ = logger.Sync()
+	}()
+
+	// Load config
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logger.Fatal("Failed to load config", zap.Error(err))
+	}
+
+	// Connect to database
+	database, err := db.NewDB(cfg.GetPostgresDSN(), logger)
+	if err != nil {
+		logger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	defer database.Close()
+
+	reconciler := reconcile.NewReconciler(database.DB, 0, *repair || cfg.ReconciliationAutoRepair, logger)
+
+	run, err := reconciler.Run(context.Background())
+	if err != nil {
+		logger.Fatal("Reconciliation failed", zap.Error(err))
+	}
+
+	fmt.Printf("Run %s scanned %d accounts: %d drifted, %d repaired\n",
+		run.ID, run.AccountsScanned, run.DriftAccounts, run.RepairedAccounts)
+	for _, finding := range run.Findings {
+		fmt.Printf("  account %s (%s): balance %d, expected %d, ledger %d, drift %d, repaired %t\n",
+			finding.AccountID, finding.Currency, finding.BalanceCents, finding.ExpectedBalanceCents,
+			finding.LedgerBalanceCents, finding.DriftCents, finding.Repaired)
+	}
+
+	if run.DriftAccounts > 0 {
+		_ = logger.Sync()
+		database.Close()
+		os.Exit(exitDrift)
+	}
+}
+
+func initLogger() (*zap.Logger, error) {
+	env := os.Getenv("ENV")
+	if env == "production" {
+		return zap.NewProduction()
+	}
+	return zap.NewDevelopment()
+}
@@ -9,12 +9,18 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/yash/transaction-system/shared/config"
 	"github.com/yash/transaction-system/shared/db"
+	"github.com/yash/transaction-system/shared/risk"
 	"github.com/yash/transaction-system/shared/tracing"
 	"github.com/yash/transaction-system/worker/internal/consumer"
+	"github.com/yash/transaction-system/worker/internal/interest"
 	"github.com/yash/transaction-system/worker/internal/processor"
+	"github.com/yash/transaction-system/worker/internal/reconcile"
+	"github.com/yash/transaction-system/worker/internal/scheduler"
+	"github.com/yash/transaction-system/worker/internal/snapshot"
 	"go.uber.org/zap"
 )
 
@@ -49,7 +55,33 @@ func main() {
 	defer database.Close()
 
 	// Create processor
-	transactionProcessor := processor.NewTransactionProcessor(database.DB, logger)
+	fxRevenueAccounts := make(map[string]uuid.UUID)
+	for currency, accountID := range cfg.FXRevenueAccounts {
+		id, err := uuid.Parse(accountID)
+		if err != nil {
+			logger.Fatal("Invalid FX revenue account", zap.String("currency", currency), zap.Error(err))
+		}
+		fxRevenueAccounts[currency] = id
+	}
+	feeRevenueAccounts := make(map[string]uuid.UUID)
+	for currency, accountID := range cfg.FeeRevenueAccounts {
+		id, err := uuid.Parse(accountID)
+		if err != nil {
+			logger.Fatal("Invalid fee revenue account", zap.String("currency", currency), zap.Error(err))
+		}
+		feeRevenueAccounts[currency] = id
+	}
+
+	var riskSource risk.Source = risk.NewDBSource(database.DB, cfg.RiskRulesRefreshInterval, logger)
+	if cfg.RiskRulesFile != "" {
+		fileSource, err := risk.NewFileSource(cfg.RiskRulesFile)
+		if err != nil {
+			logger.Fatal("Failed to load risk rules", zap.Error(err))
+		}
+		riskSource = fileSource
+	}
+
+	transactionProcessor := processor.NewTransactionProcessor(database.DB, cfg.HoldTTL, fxRevenueAccounts, feeRevenueAccounts, risk.NewEngine(riskSource), logger)
 
 	// Create consumer
 	kafkaConsumer := consumer.NewKafkaConsumer(
@@ -76,10 +108,33 @@ func main() {
 		}
 	}()
 
-	// Start consumer
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	// Start hold expirer
+	holdExpirer := processor.NewHoldExpirer(database.DB, cfg.HoldExpiryInterval, 100, logger)
+	go holdExpirer.Start(ctx)
+
+	// Start transaction scheduler
+	transactionScheduler := scheduler.NewScheduler(database.DB, cfg.SchedulerInterval, 100, logger)
+	go transactionScheduler.Start(ctx)
+
+	// Start balance snapshotter
+	snapshotter := snapshot.NewSnapshotter(database.DB, cfg.SnapshotInterval, logger)
+	go snapshotter.Start(ctx)
+
+	// Start interest accruer
+	accruer := interest.NewAccruer(database.DB, cfg.InterestAccrualInterval, logger)
+	go accruer.Start(ctx)
+
+	// Start balance reconciler unless it is left to cmd/reconcile
+	if cfg.ReconciliationInterval > 0 {
+		reconciler := reconcile.NewReconciler(database.DB, cfg.ReconciliationInterval, cfg.ReconciliationAutoRepair, logger)
+		go reconciler.Start(ctx)
+	}
+
+	// Start consumer
+
 	if err := kafkaConsumer.Start(ctx); err != nil {
 		logger.Fatal("Consumer failed", zap.Error(err))
 	}
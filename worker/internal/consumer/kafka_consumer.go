@@ -130,7 +130,7 @@ func (c *KafkaConsumer) processMessage(ctx context.Context) error {
 			time.Sleep(backoff)
 		}
 
-		shouldRetry, lastErr = c.processor.ProcessTransactionCreated(ctx, envelope)
+		shouldRetry, lastErr = c.processor.ProcessEvent(ctx, envelope)
 
 		if !shouldRetry {
 			// Success or non-retryable error
@@ -0,0 +1,357 @@
+package interest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/fx"
+	"github.com/yash/transaction-system/shared/interest"
+	"github.com/yash/transaction-system/shared/outbox"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/txstatus"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// settleDelay is how long after midnight a day is accrued, so that DB
+// transactions which stamped postings before midnight have committed
+const settleDelay = 5 * time.Minute
+
+// Accruer accrues daily interest on interest-bearing accounts and, once a
+// month has ended, posts the month's interest as a CREDIT or DEBIT through
+// the outbox like any other transaction
+type Accruer struct {
+	db       *sql.DB
+	logger   *zap.Logger
+	interval time.Duration
+}
+
+// NewAccruer creates a new interest accruer
+func NewAccruer(db *sql.DB, interval time.Duration, logger *zap.Logger) *Accruer {
+	return &Accruer{
+		db:       db,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// Start accrues and posts immediately and then on every interval until the
+// context is cancelled
+func (a *Accruer) Start(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	a.logger.Info("Interest accruer started", zap.Duration("interval", a.interval))
+
+	for {
+		a.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			a.logger.Info("Interest accruer stopping...")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *Accruer) run(ctx context.Context) {
+	// The last day that has fully ended
+	through := time.Now().UTC().Add(-settleDelay).Truncate(24*time.Hour).AddDate(0, 0, -1)
+	accrued, err := a.AccrueThrough(ctx, through)
+	if err != nil {
+		a.logger.Error("Failed to accrue interest", zap.Error(err))
+	}
+	if accrued > 0 {
+		a.logger.Info("Accrued interest", zap.Int("accruals", accrued), zap.Time("through", through))
+	}
+
+	// Months before the one containing the next day to accrue are complete
+	next := through.AddDate(0, 0, 1)
+	posted, err := a.PostBefore(ctx, time.Date(next.Year(), next.Month(), 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		a.logger.Error("Failed to post interest", zap.Error(err))
+	}
+	if posted > 0 {
+		a.logger.Info("Posted interest", zap.Int("transactions", posted))
+	}
+}
+
+// interestAccount is an account interest accrues on
+type interestAccount struct {
+	id        uuid.UUID
+	kind      types.AccountKind
+	rate      string
+	dayCount  types.DayCountConvention
+	startDate time.Time
+	lastDate  *time.Time
+}
+
+// AccrueThrough accrues every day up to and including the given day that an
+// interest-bearing account has not accrued yet. A day already accrued is
+// never accrued again, so reruns and concurrent workers are safe. An account
+// that fails is logged and retried on the next run.
+func (a *Accruer) AccrueThrough(ctx context.Context, through time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	query := `
+		SELECT a.id, a.kind, a.interest_rate, a.interest_day_count, a.interest_start_date,
+		       (SELECT MAX(accrual_date) FROM interest_accruals i WHERE i.account_id = a.id)
+		FROM accounts a
+		WHERE a.interest_rate IS NOT NULL AND a.status <> $1 AND a.system_code IS NULL
+	`
+	rows, err := a.db.QueryContext(ctx, query, types.AccountStatusClosed)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query interest-bearing accounts: %w", err)
+	}
+	var accounts []interestAccount
+	for rows.Next() {
+		var account interestAccount
+		if err := rows.Scan(&account.id, &account.kind, &account.rate, &account.dayCount, &account.startDate, &account.lastDate); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan interest-bearing account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	accrued := 0
+	for _, account := range accounts {
+		day := account.startDate.UTC()
+		if account.lastDate != nil && !account.lastDate.Before(day) {
+			day = account.lastDate.UTC().AddDate(0, 0, 1)
+		}
+		for ; !day.After(through); day = day.AddDate(0, 0, 1) {
+			created, err := a.accrueDay(ctx, account, day)
+			if err != nil {
+				// Leave the account for the next run rather than skip a day
+				a.logger.Error("Failed to accrue interest",
+					zap.String("account_id", account.id.String()),
+					zap.String("date", day.Format("2006-01-02")),
+					zap.Error(err),
+				)
+				break
+			}
+			if created {
+				accrued++
+			}
+		}
+	}
+
+	return accrued, nil
+}
+
+// accrueDay records the interest an account accrued on its balance at the end
+// of the day. Returns false if the day was already accrued.
+func (a *Accruer) accrueDay(ctx context.Context, account interestAccount, day time.Time) (bool, error) {
+	rate, err := interest.ParseRate(account.rate)
+	if err != nil {
+		return false, err
+	}
+
+	balanceQuery := `
+		WITH snapshot AS (
+			SELECT snapshot_at, balance_cents
+			FROM account_balance_snapshots
+			WHERE account_id = $1 AND snapshot_at <= $2
+			ORDER BY snapshot_at DESC
+			LIMIT 1
+		)
+		SELECT COALESCE((SELECT balance_cents FROM snapshot), 0) + COALESCE(SUM(
+			CASE p.direction WHEN 'CREDIT' THEN p.amount_cents ELSE -p.amount_cents END
+		), 0)
+		FROM postings p
+		WHERE p.account_id = $1 AND p.created_at < $2
+		  AND p.created_at >= COALESCE((SELECT snapshot_at FROM snapshot), '-infinity')
+	`
+	var balance int64
+	if err := a.db.QueryRowContext(ctx, balanceQuery, account.id, day.AddDate(0, 0, 1)).Scan(&balance); err != nil {
+		return false, fmt.Errorf("failed to compute end-of-day balance: %w", err)
+	}
+
+	amount := interest.Accrue(interest.InterestBalance(account.kind, balance), rate, account.dayCount, day)
+
+	insertQuery := `
+		INSERT INTO interest_accruals (account_id, accrual_date, balance_cents, annual_rate, day_count, amount_cents, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (account_id, accrual_date) DO NOTHING
+	`
+	result, err := a.db.ExecContext(ctx, insertQuery,
+		account.id, day.Format("2006-01-02"), balance, account.rate, account.dayCount, amount.FloatString(12),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to record accrual: %w", err)
+	}
+	created, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if created > 0 {
+		interestAccrualsTotal.Inc()
+	}
+	return created > 0, nil
+}
+
+// PostBefore posts the unposted accruals of every month that starts before
+// the given month, one transaction per account and month, rounded half up to
+// the cent. Returns the number of transactions created; an account that
+// fails is logged and retried on the next run.
+func (a *Accruer) PostBefore(ctx context.Context, month time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	query := `
+		SELECT DISTINCT account_id, date_trunc('month', accrual_date)::date
+		FROM interest_accruals
+		WHERE posted_at IS NULL AND accrual_date < $1
+	`
+	rows, err := a.db.QueryContext(ctx, query, month.Format("2006-01-02"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to query unposted accruals: %w", err)
+	}
+	type period struct {
+		accountID uuid.UUID
+		month     time.Time
+	}
+	var periods []period
+	for rows.Next() {
+		var p period
+		if err := rows.Scan(&p.accountID, &p.month); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan unposted accruals: %w", err)
+		}
+		periods = append(periods, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	posted := 0
+	for _, p := range periods {
+		created, err := a.postMonth(ctx, p.accountID, p.month.UTC())
+		if err != nil {
+			a.logger.Error("Failed to post interest",
+				zap.String("account_id", p.accountID.String()),
+				zap.String("period", p.month.Format("2006-01")),
+				zap.Error(err),
+			)
+			continue
+		}
+		if created {
+			posted++
+		}
+	}
+
+	return posted, nil
+}
+
+// postMonth posts an account's unposted accruals of a month in one DB
+// transaction: it creates the PENDING interest transaction with its outbox
+// event and marks the accruals posted. The idempotency key interest:<YYYY-MM>
+// keeps a month from being posted twice. Interest that rounds to zero cents,
+// or accrued by an account closed since, is marked posted without a transaction.
+func (a *Accruer) postMonth(ctx context.Context, accountID uuid.UUID, month time.Time) (bool, error) {
+	tx, err := a.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			a.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	var tenantID string
+	var kind types.AccountKind
+	var currency string
+	var status types.AccountStatus
+	lockQuery := `SELECT tenant_id, kind, currency, status FROM accounts WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, lockQuery, accountID).Scan(&tenantID, &kind, &currency, &status); err != nil {
+		return false, fmt.Errorf("failed to lock account: %w", err)
+	}
+	// The transaction and its event belong to the account's tenant
+	ctx = tenant.WithID(ctx, tenantID)
+
+	start, end := month.Format("2006-01-02"), month.AddDate(0, 1, 0).Format("2006-01-02")
+	var total string
+	sumQuery := `
+		SELECT COALESCE(SUM(amount_cents), 0)::text
+		FROM interest_accruals
+		WHERE account_id = $1 AND accrual_date >= $2 AND accrual_date < $3 AND posted_at IS NULL
+	`
+	if err := tx.QueryRowContext(ctx, sumQuery, accountID, start, end).Scan(&total); err != nil {
+		return false, fmt.Errorf("failed to sum accruals: %w", err)
+	}
+	totalCents, ok := new(big.Rat).SetString(total)
+	if !ok {
+		return false, fmt.Errorf("invalid accrual total %q", total)
+	}
+	amountCents := fx.Round(totalCents)
+
+	var transactionID *uuid.UUID
+	if amountCents > 0 && status != types.AccountStatusClosed {
+		periodName := month.Format("2006-01")
+		metadata, _ := json.Marshal(map[string]string{"interest_period": periodName})
+		transaction := types.Transaction{
+			ID:             uuid.New(),
+			AccountID:      accountID,
+			AmountCents:    amountCents,
+			Currency:       currency,
+			Type:           interest.PostingType(kind),
+			IdempotencyKey: "interest:" + periodName,
+			Metadata:       metadata,
+			InterestPeriod: &periodName,
+		}
+		insertQuery := `
+			INSERT INTO transactions (id, tenant_id, account_id, amount_cents, currency, type, status, idempotency_key,
+			                          metadata, interest_period, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW(), NOW())
+			ON CONFLICT (account_id, idempotency_key) DO NOTHING
+		`
+		result, err := tx.ExecContext(ctx, insertQuery,
+			transaction.ID, tenantID, accountID, amountCents, currency, transaction.Type, types.TransactionStatusPending,
+			transaction.IdempotencyKey, metadata, periodName,
+		)
+		if err != nil {
+			return false, fmt.Errorf("failed to create interest transaction: %w", err)
+		}
+		if created, err := result.RowsAffected(); err != nil || created == 0 {
+			return false, fmt.Errorf("interest for %s already posted", periodName)
+		}
+		if err := txstatus.RecordCreated(ctx, tx, transaction.ID, types.TransactionStatusPending, "", txstatus.ActorWorker); err != nil {
+			return false, err
+		}
+		if err := outbox.Write(ctx, tx, "transaction", transaction.ID, types.EventTypeTransactionCreated, transaction.CreatedPayload()); err != nil {
+			return false, err
+		}
+		transactionID = &transaction.ID
+	}
+
+	markQuery := `
+		UPDATE interest_accruals
+		SET posted_at = NOW(), transaction_id = $1
+		WHERE account_id = $2 AND accrual_date >= $3 AND accrual_date < $4 AND posted_at IS NULL
+	`
+	if _, err := tx.ExecContext(ctx, markQuery, transactionID, accountID, start, end); err != nil {
+		return false, fmt.Errorf("failed to mark accruals posted: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	if transactionID != nil {
+		interestPostedCentsTotal.WithLabelValues(string(interest.PostingType(kind)), currency).Add(float64(amountCents))
+	}
+
+	return transactionID != nil, nil
+}
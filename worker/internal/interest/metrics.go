@@ -0,0 +1,23 @@
+package interest
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	interestAccrualsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "interest_accruals_total",
+			Help: "Total number of daily interest accruals recorded",
+		},
+	)
+
+	interestPostedCentsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "interest_posted_cents_total",
+			Help: "Total interest posted in cents, by transaction type and currency",
+		},
+		[]string{"type", "currency"},
+	)
+)
@@ -0,0 +1,189 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// ErrUnbalancedEntry is returned when a journal entry violates the double-entry invariant
+var ErrUnbalancedEntry = errors.New("unbalanced journal entry")
+
+// Ledger writes double-entry journal entries and keeps account balances in sync with them
+type Ledger struct {
+	logger *zap.Logger
+}
+
+// NewLedger creates a new ledger
+func NewLedger(logger *zap.Logger) *Ledger {
+	return &Ledger{
+		logger: logger,
+	}
+}
+
+// Validate checks that a journal entry has at least one debit and one credit
+// and that debits equal credits for every currency
+func Validate(entry types.JournalEntry) error {
+	var debits, credits int
+	totals := make(map[string]int64)
+
+	for _, posting := range entry.Postings {
+		if posting.AmountCents <= 0 {
+			return fmt.Errorf("%w: posting amount must be positive", ErrUnbalancedEntry)
+		}
+		switch posting.Direction {
+		case types.PostingDirectionDebit:
+			debits++
+		case types.PostingDirectionCredit:
+			credits++
+		default:
+			return fmt.Errorf("%w: invalid posting direction %q", ErrUnbalancedEntry, posting.Direction)
+		}
+		totals[posting.Currency] += posting.SignedAmount()
+	}
+
+	if debits == 0 || credits == 0 {
+		return fmt.Errorf("%w: entry needs at least one debit and one credit", ErrUnbalancedEntry)
+	}
+
+	for currency, total := range totals {
+		if total != 0 {
+			return fmt.Errorf("%w: %s postings net to %d", ErrUnbalancedEntry, currency, total)
+		}
+	}
+
+	return nil
+}
+
+// Post validates a journal entry, persists it with its postings and applies the
+// postings to the balances of the affected accounts. It must run inside the
+// caller's DB transaction. Returns the new balance of every non-system account.
+//
+// System account balances are not maintained on this path to avoid a hot row
+// shared by every transaction; they are derived from postings on demand.
+func (l *Ledger) Post(ctx context.Context, tx *sql.Tx, entry *types.JournalEntry) (map[uuid.UUID]int64, error) {
+	if err := Validate(*entry); err != nil {
+		return nil, err
+	}
+
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+
+	insertEntryQuery := `
+		INSERT INTO journal_entries (id, transaction_id, description, created_at)
+		VALUES ($1, $2, $3, NOW())
+		RETURNING created_at
+	`
+	err := tx.QueryRowContext(ctx, insertEntryQuery, entry.ID, entry.TransactionID, entry.Description).Scan(&entry.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert journal entry: %w", err)
+	}
+
+	insertPostingQuery := `
+		INSERT INTO postings (id, journal_entry_id, transaction_id, account_id, direction, amount_cents, currency, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	deltas := make(map[uuid.UUID]int64)
+	currencies := make(map[uuid.UUID]string)
+	for i := range entry.Postings {
+		posting := &entry.Postings[i]
+		posting.ID = uuid.New()
+		posting.JournalEntryID = entry.ID
+		posting.CreatedAt = entry.CreatedAt
+		if posting.TransactionID == nil {
+			posting.TransactionID = entry.TransactionID
+		}
+
+		_, err := tx.ExecContext(ctx, insertPostingQuery,
+			posting.ID, posting.JournalEntryID, posting.TransactionID, posting.AccountID, posting.Direction,
+			posting.AmountCents, posting.Currency, posting.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to insert posting: %w", err)
+		}
+
+		deltas[posting.AccountID] += posting.SignedAmount()
+		currencies[posting.AccountID] = posting.Currency
+	}
+
+	// Apply balances in a deterministic order to keep lock ordering stable
+	accountIDs := make([]uuid.UUID, 0, len(deltas))
+	for accountID := range deltas {
+		accountIDs = append(accountIDs, accountID)
+	}
+	sort.Slice(accountIDs, func(i, j int) bool {
+		return accountIDs[i].String() < accountIDs[j].String()
+	})
+
+	balances := make(map[uuid.UUID]int64)
+	for _, accountID := range accountIDs {
+		var accountCurrency string
+		var systemCode sql.NullString
+		accountQuery := `SELECT currency, system_code FROM accounts WHERE id = $1`
+		err := tx.QueryRowContext(ctx, accountQuery, accountID).Scan(&accountCurrency, &systemCode)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: account %s not found", ErrUnbalancedEntry, accountID)
+			}
+			return nil, fmt.Errorf("failed to load account: %w", err)
+		}
+
+		if accountCurrency != currencies[accountID] {
+			return nil, fmt.Errorf("%w: posting currency %s does not match account %s currency %s",
+				ErrUnbalancedEntry, currencies[accountID], accountID, accountCurrency)
+		}
+
+		if systemCode.Valid {
+			continue
+		}
+
+		var newBalance int64
+		updateBalanceQuery := `
+			UPDATE accounts
+			SET balance_cents = balance_cents + $1, updated_at = NOW()
+			WHERE id = $2
+			RETURNING balance_cents
+		`
+		err = tx.QueryRowContext(ctx, updateBalanceQuery, deltas[accountID], accountID).Scan(&newBalance)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update balance: %w", err)
+		}
+		balances[accountID] = newBalance
+	}
+
+	l.logger.Debug("Journal entry posted",
+		zap.String("journal_entry_id", entry.ID.String()),
+		zap.Int("postings", len(entry.Postings)),
+	)
+
+	return balances, nil
+}
+
+// SystemAccountID returns the system account for the given code and currency,
+// creating it on first use
+func (l *Ledger) SystemAccountID(ctx context.Context, tx *sql.Tx, code, currency string) (uuid.UUID, error) {
+	insertQuery := `
+		INSERT INTO accounts (id, currency, balance_cents, status, system_code, created_at, updated_at)
+		VALUES ($1, $2, 0, $3, $4, NOW(), NOW())
+		ON CONFLICT (system_code, currency) WHERE system_code IS NOT NULL DO NOTHING
+	`
+	_, err := tx.ExecContext(ctx, insertQuery, uuid.New(), currency, types.AccountStatusActive, code)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create system account: %w", err)
+	}
+
+	var accountID uuid.UUID
+	selectQuery := `SELECT id FROM accounts WHERE system_code = $1 AND currency = $2`
+	if err := tx.QueryRowContext(ctx, selectQuery, code, currency).Scan(&accountID); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to load system account: %w", err)
+	}
+
+	return accountID, nil
+}
@@ -0,0 +1,103 @@
+package ledger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/types"
+)
+
+func debit(amountCents int64, currency string) types.Posting {
+	return types.Posting{AccountID: uuid.New(), Direction: types.PostingDirectionDebit, AmountCents: amountCents, Currency: currency}
+}
+
+func credit(amountCents int64, currency string) types.Posting {
+	return types.Posting{AccountID: uuid.New(), Direction: types.PostingDirectionCredit, AmountCents: amountCents, Currency: currency}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		postings []types.Posting
+		wantErr  bool
+	}{
+		{
+			name:     "balanced entry",
+			postings: []types.Posting{debit(1000, "USD"), credit(1000, "USD")},
+		},
+		{
+			name:     "split credit",
+			postings: []types.Posting{debit(1000, "USD"), credit(700, "USD"), credit(300, "USD")},
+		},
+		{
+			name: "balanced multi-currency FX entry",
+			postings: []types.Posting{
+				debit(1000, "EUR"), credit(1000, "EUR"),
+				debit(1085, "USD"), credit(1080, "USD"), credit(5, "USD"),
+			},
+		},
+		{
+			name:    "empty entry",
+			wantErr: true,
+		},
+		{
+			name:     "single posting",
+			postings: []types.Posting{debit(1000, "USD")},
+			wantErr:  true,
+		},
+		{
+			name:     "debits only",
+			postings: []types.Posting{debit(1000, "USD"), debit(1000, "USD")},
+			wantErr:  true,
+		},
+		{
+			name:     "unbalanced entry",
+			postings: []types.Posting{debit(1000, "USD"), credit(999, "USD")},
+			wantErr:  true,
+		},
+		{
+			name:     "sides in different currencies",
+			postings: []types.Posting{debit(1000, "USD"), credit(1000, "EUR")},
+			wantErr:  true,
+		},
+		{
+			name: "one currency unbalanced",
+			postings: []types.Posting{
+				debit(1000, "EUR"), credit(1000, "EUR"),
+				debit(1085, "USD"), credit(1080, "USD"),
+			},
+			wantErr: true,
+		},
+		{
+			name:     "zero amount",
+			postings: []types.Posting{debit(0, "USD"), credit(0, "USD")},
+			wantErr:  true,
+		},
+		{
+			name:     "negative amount",
+			postings: []types.Posting{debit(-1000, "USD"), credit(-1000, "USD")},
+			wantErr:  true,
+		},
+		{
+			name: "invalid direction",
+			postings: []types.Posting{
+				debit(1000, "USD"), credit(1000, "USD"),
+				{AccountID: uuid.New(), Direction: "SIDEWAYS", AmountCents: 1, Currency: "USD"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(types.JournalEntry{Postings: tt.postings})
+			if tt.wantErr && !errors.Is(err, ErrUnbalancedEntry) {
+				t.Errorf("Validate() = %v; want ErrUnbalancedEntry", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Validate() = %v; want nil", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/fees"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/txstatus"
+	"github.com/yash/transaction-system/shared/types"
+)
+
+// chargeFee charges the fee schedule of the transaction's type and account
+// currency once the transaction has been posted. The fee is its own PROCESSED
+// DEBIT, linked to the transaction and posted to the fee revenue account in
+// the same DB transaction. Reversals and interest are free. Returns the
+// account balance after the fee.
+func (p *TransactionProcessor) chargeFee(ctx context.Context, tx *sql.Tx, payload types.TransactionCreatedPayload, account accountState, balance int64) (int64, error) {
+	if !fees.Chargeable(payload.Type) || payload.ReversesTransactionID != nil || payload.InterestPeriod != nil {
+		return balance, nil
+	}
+	schedule, err := fees.Lookup(ctx, tx, payload.Type, account.Currency)
+	if err != nil || schedule == nil {
+		return balance, err
+	}
+
+	// Converted transactions are priced on the amount settled in the account currency
+	var amountCents int64
+	amountQuery := `SELECT COALESCE(settled_amount_cents, amount_cents) FROM transactions WHERE id = $1`
+	if err := tx.QueryRowContext(ctx, amountQuery, payload.TransactionID).Scan(&amountCents); err != nil {
+		return 0, fmt.Errorf("failed to load transaction amount: %w", err)
+	}
+	volume, err := fees.MonthlyVolume(ctx, tx, payload.AccountID, payload.Type, time.Now(), payload.TransactionID)
+	if err != nil {
+		return 0, err
+	}
+	feeCents := fees.Calculate(*schedule, amountCents, volume)
+	if feeCents <= 0 {
+		return balance, nil
+	}
+
+	after := account
+	if err := tx.QueryRowContext(ctx, `SELECT balance_cents, held_cents FROM accounts WHERE id = $1`, payload.AccountID).Scan(&after.BalanceCents, &after.HeldCents); err != nil {
+		return 0, fmt.Errorf("failed to load account balance: %w", err)
+	}
+	if !after.CanDebit(feeCents) {
+		return 0, reject("%s", after.insufficientFunds("fee", feeCents))
+	}
+
+	feeID := uuid.New()
+	insertQuery := `
+		INSERT INTO transactions (id, tenant_id, account_id, amount_cents, currency, type, status, idempotency_key,
+		                          fee_for_transaction_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+	`
+	_, err = tx.ExecContext(ctx, insertQuery, feeID, tenant.FromContext(ctx), payload.AccountID, feeCents, account.Currency,
+		types.TransactionTypeDebit, types.TransactionStatusProcessed, "fee:"+payload.TransactionID.String(), payload.TransactionID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create fee transaction: %w", err)
+	}
+	if err := txstatus.RecordCreated(ctx, tx, feeID, types.TransactionStatusProcessed, "", txstatus.ActorWorker); err != nil {
+		return 0, err
+	}
+
+	revenueAccountID, err := p.feeRevenueAccountID(ctx, tx, account.Currency)
+	if err != nil {
+		return 0, err
+	}
+	entry := types.JournalEntry{
+		TransactionID: &feeID,
+		Description:   fmt.Sprintf("fee for transaction %s", payload.TransactionID),
+		Postings: []types.Posting{
+			{AccountID: payload.AccountID, Direction: types.PostingDirectionDebit, AmountCents: feeCents, Currency: account.Currency},
+			{AccountID: revenueAccountID, Direction: types.PostingDirectionCredit, AmountCents: feeCents, Currency: account.Currency},
+		},
+	}
+	balances, err := p.ledger.Post(ctx, tx, &entry)
+	if err != nil {
+		return 0, err
+	}
+
+	linkQuery := `
+		UPDATE transactions
+		SET fee_cents = $1, fee_transaction_id = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+	if _, err := tx.ExecContext(ctx, linkQuery, feeCents, feeID, payload.TransactionID); err != nil {
+		return 0, fmt.Errorf("failed to link fee transaction: %w", err)
+	}
+	feesChargedTotal.WithLabelValues(string(payload.Type), account.Currency).Add(float64(feeCents))
+
+	return balances[payload.AccountID], nil
+}
+
+// feeRevenueAccountID returns the configured fee revenue account for the
+// currency, falling back to the FEE_REVENUE system account
+func (p *TransactionProcessor) feeRevenueAccountID(ctx context.Context, tx *sql.Tx, currency string) (uuid.UUID, error) {
+	if accountID, ok := p.feeRevenueAccounts[currency]; ok {
+		return accountID, nil
+	}
+	return p.ledger.SystemAccountID(ctx, tx, types.SystemAccountFeeRevenue, currency)
+}
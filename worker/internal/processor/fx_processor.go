@@ -0,0 +1,185 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/fx"
+	"github.com/yash/transaction-system/shared/types"
+)
+
+// applyConversion applies a CREDIT or DEBIT whose currency differs from the
+// account currency. The amount is converted at the locked quote or the current
+// rate; the journal entry moves the transaction amount through the FX position
+// accounts of both currencies and books the spread as revenue.
+func (p *TransactionProcessor) applyConversion(ctx context.Context, tx *sql.Tx, payload types.TransactionCreatedPayload, account accountState, direction types.PostingDirection, occurredAt time.Time) (int64, error) {
+	conversion, rate, err := p.resolveConversion(ctx, tx, payload, account.Currency, direction, occurredAt)
+	if err != nil {
+		return 0, err
+	}
+	if conversion.MidCents <= 0 || conversion.SettledCents <= 0 {
+		return 0, reject("amount too small to convert: %d %s", payload.AmountCents, payload.Currency)
+	}
+	if direction == types.PostingDirectionDebit && !account.CanDebit(conversion.SettledCents) {
+		return 0, reject("%s", account.insufficientFunds("debit", conversion.SettledCents))
+	}
+	if direction == types.PostingDirectionDebit && payload.ReversesTransactionID == nil {
+		if err := p.checkLimits(ctx, tx, payload.TransactionID, payload.AccountID, conversion.SettledCents); err != nil {
+			return 0, err
+		}
+	}
+
+	settlementAccountID, err := p.ledger.SystemAccountID(ctx, tx, types.SystemAccountSettlement, payload.Currency)
+	if err != nil {
+		return 0, err
+	}
+	sourcePositionID, err := p.ledger.SystemAccountID(ctx, tx, types.SystemAccountFXPosition, payload.Currency)
+	if err != nil {
+		return 0, err
+	}
+	targetPositionID, err := p.ledger.SystemAccountID(ctx, tx, types.SystemAccountFXPosition, account.Currency)
+	if err != nil {
+		return 0, err
+	}
+
+	entry := types.JournalEntry{
+		TransactionID: &payload.TransactionID,
+		Description:   fmt.Sprintf("%s transaction %s converted %s->%s", direction, payload.TransactionID, payload.Currency, account.Currency),
+		Postings: []types.Posting{
+			{AccountID: settlementAccountID, Direction: direction.Opposite(), AmountCents: payload.AmountCents, Currency: payload.Currency},
+			{AccountID: sourcePositionID, Direction: direction, AmountCents: payload.AmountCents, Currency: payload.Currency},
+			{AccountID: targetPositionID, Direction: direction.Opposite(), AmountCents: conversion.MidCents, Currency: account.Currency},
+			{AccountID: payload.AccountID, Direction: direction, AmountCents: conversion.SettledCents, Currency: account.Currency},
+		},
+	}
+
+	// Positive spread is earned; a reversal hands it back
+	if conversion.SpreadCents != 0 {
+		revenueAccountID, err := p.fxRevenueAccountID(ctx, tx, account.Currency)
+		if err != nil {
+			return 0, err
+		}
+		spread := types.Posting{AccountID: revenueAccountID, Direction: types.PostingDirectionCredit, AmountCents: conversion.SpreadCents, Currency: account.Currency}
+		if conversion.SpreadCents < 0 {
+			spread.Direction = types.PostingDirectionDebit
+			spread.AmountCents = -conversion.SpreadCents
+		}
+		entry.Postings = append(entry.Postings, spread)
+	}
+
+	balances, err := p.ledger.Post(ctx, tx, &entry)
+	if err != nil {
+		return 0, err
+	}
+
+	recordQuery := `
+		UPDATE transactions
+		SET fx_rate = $1, settled_amount_cents = $2, settled_currency = $3, fx_spread_cents = $4, updated_at = NOW()
+		WHERE id = $5
+	`
+	_, err = tx.ExecContext(ctx, recordQuery, rate, conversion.SettledCents, account.Currency, conversion.SpreadCents, payload.TransactionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record conversion: %w", err)
+	}
+
+	return balances[payload.AccountID], nil
+}
+
+// resolveConversion converts the transaction amount into the account
+// currency. Reversals reuse the original conversion pro rata so a full
+// reversal returns exactly what was settled; otherwise the locked quote or the
+// current rate applies. A quote must not have expired by occurredAt, the time
+// of the transaction's event. Returns the conversion and the mid rate used.
+func (p *TransactionProcessor) resolveConversion(ctx context.Context, tx *sql.Tx, payload types.TransactionCreatedPayload, accountCurrency string, direction types.PostingDirection, occurredAt time.Time) (fx.Conversion, string, error) {
+	if payload.ReversesTransactionID != nil {
+		return p.reverseConversion(ctx, tx, payload, direction)
+	}
+
+	var rate *fx.Rate
+	if payload.FXQuoteID != nil {
+		var base, quote, value string
+		var spreadBps int
+		var expiresAt time.Time
+		quoteQuery := `SELECT base_currency, quote_currency, rate, spread_bps, expires_at FROM fx_quotes WHERE id = $1`
+		err := tx.QueryRowContext(ctx, quoteQuery, payload.FXQuoteID).Scan(&base, &quote, &value, &spreadBps, &expiresAt)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fx.Conversion{}, "", reject("fx quote %s not found", payload.FXQuoteID)
+			}
+			return fx.Conversion{}, "", fmt.Errorf("failed to load fx quote: %w", err)
+		}
+		if base != payload.Currency || quote != accountCurrency {
+			return fx.Conversion{}, "", reject("fx quote %s is for %s/%s, not %s/%s", payload.FXQuoteID, base, quote, payload.Currency, accountCurrency)
+		}
+		// An approved transaction's event is only written on approval
+		if occurredAt.After(expiresAt) {
+			return fx.Conversion{}, "", &rejectionError{
+				reason: fmt.Sprintf("fx quote %s expired at %s", payload.FXQuoteID, expiresAt.UTC().Format(time.RFC3339)),
+				code:   types.FailureCodeQuoteExpired,
+			}
+		}
+		parsed, err := fx.ParseRate(value)
+		if err != nil {
+			return fx.Conversion{}, "", reject("fx quote %s has an invalid rate", payload.FXQuoteID)
+		}
+		rate = &fx.Rate{Value: parsed, SpreadBps: spreadBps}
+	} else {
+		var err error
+		rate, err = fx.LatestRate(ctx, tx, payload.Currency, accountCurrency)
+		if err != nil {
+			if errors.Is(err, fx.ErrRateNotFound) {
+				return fx.Conversion{}, "", reject("currency mismatch: no fx rate for %s/%s", payload.Currency, accountCurrency)
+			}
+			return fx.Conversion{}, "", err
+		}
+	}
+
+	conversion := fx.Convert(payload.AmountCents, payload.Currency, accountCurrency, *rate, direction == types.PostingDirectionDebit)
+	return conversion, fx.FormatRate(rate.Value), nil
+}
+
+// reverseConversion scales the original transaction's conversion to the
+// reversed amount, with the spread handed back instead of charged
+func (p *TransactionProcessor) reverseConversion(ctx context.Context, tx *sql.Tx, payload types.TransactionCreatedPayload, direction types.PostingDirection) (fx.Conversion, string, error) {
+	var originalAmount int64
+	var rate sql.NullString
+	var settledCents, spreadCents sql.NullInt64
+	originalQuery := `
+		SELECT amount_cents, fx_rate, settled_amount_cents, fx_spread_cents
+		FROM transactions
+		WHERE id = $1
+	`
+	err := tx.QueryRowContext(ctx, originalQuery, payload.ReversesTransactionID).Scan(&originalAmount, &rate, &settledCents, &spreadCents)
+	if err != nil {
+		return fx.Conversion{}, "", fmt.Errorf("failed to load original conversion: %w", err)
+	}
+	if !rate.Valid || !settledCents.Valid || !spreadCents.Valid {
+		return fx.Conversion{}, "", reject("original transaction %s was not converted", payload.ReversesTransactionID)
+	}
+
+	share := big.NewRat(payload.AmountCents, originalAmount)
+	settled := fx.Round(new(big.Rat).Mul(new(big.Rat).SetInt64(settledCents.Int64), share))
+	spread := -fx.Round(new(big.Rat).Mul(new(big.Rat).SetInt64(spreadCents.Int64), share))
+
+	// The mid amount always balances the account leg against the spread leg
+	mid := settled + spread
+	if direction == types.PostingDirectionDebit {
+		mid = settled - spread
+	}
+
+	return fx.Conversion{MidCents: mid, SpreadCents: spread, SettledCents: settled}, rate.String, nil
+}
+
+// fxRevenueAccountID returns the configured FX revenue account for the
+// currency, falling back to the FX_REVENUE system account
+func (p *TransactionProcessor) fxRevenueAccountID(ctx context.Context, tx *sql.Tx, currency string) (uuid.UUID, error) {
+	if accountID, ok := p.fxRevenueAccounts[currency]; ok {
+		return accountID, nil
+	}
+	return p.ledger.SystemAccountID(ctx, tx, types.SystemAccountFXRevenue, currency)
+}
@@ -0,0 +1,162 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// HoldExpirer releases authorization holds whose TTL has passed
+type HoldExpirer struct {
+	db        *sql.DB
+	logger    *zap.Logger
+	interval  time.Duration
+	batchSize int
+}
+
+// NewHoldExpirer creates a new hold expirer
+func NewHoldExpirer(db *sql.DB, interval time.Duration, batchSize int, logger *zap.Logger) *HoldExpirer {
+	return &HoldExpirer{
+		db:        db,
+		logger:    logger,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Start runs the expiry loop until the context is cancelled
+func (e *HoldExpirer) Start(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	e.logger.Info("Hold expirer started", zap.Duration("interval", e.interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.logger.Info("Hold expirer stopping...")
+			return
+		case <-ticker.C:
+			expired, err := e.expireBatch(ctx)
+			if err != nil {
+				e.logger.Error("Failed to expire holds", zap.Error(err))
+				continue
+			}
+			if expired > 0 {
+				e.logger.Info("Expired authorization holds", zap.Int("count", expired))
+			}
+		}
+	}
+}
+
+// expireBatch expires up to batchSize holds, each in its own DB transaction
+func (e *HoldExpirer) expireBatch(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	query := `
+		SELECT id
+		FROM transactions
+		WHERE hold_status = $1 AND hold_expires_at < NOW()
+		ORDER BY hold_expires_at ASC
+		LIMIT $2
+	`
+	rows, err := e.db.QueryContext(ctx, query, types.HoldStatusHeld, e.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query expired holds: %w", err)
+	}
+	var holdIDs []uuid.UUID
+	for rows.Next() {
+		var holdID uuid.UUID
+		if err := rows.Scan(&holdID); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan hold: %w", err)
+		}
+		holdIDs = append(holdIDs, holdID)
+	}
+	rows.Close()
+
+	expired := 0
+	for _, holdID := range holdIDs {
+		ok, err := e.expireHold(ctx, holdID)
+		if err != nil {
+			return expired, fmt.Errorf("hold %s: %w", holdID, err)
+		}
+		if ok {
+			expired++
+		}
+	}
+	holdsExpiredTotal.Add(float64(expired))
+
+	return expired, nil
+}
+
+// expireHold releases a hold if it is still open and past its TTL, and
+// reports whether it did. The account is locked before the authorization,
+// in the order captures and voids lock them, so the two cannot deadlock.
+func (e *HoldExpirer) expireHold(ctx context.Context, holdID uuid.UUID) (bool, error) {
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			e.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	var accountID uuid.UUID
+	lockAccountQuery := `
+		SELECT id
+		FROM accounts
+		WHERE id = (SELECT account_id FROM transactions WHERE id = $1)
+		FOR UPDATE
+	`
+	if err := tx.QueryRowContext(ctx, lockAccountQuery, holdID).Scan(&accountID); err != nil {
+		return false, fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	// A capture or void may have closed the hold since it was selected
+	var amountCents int64
+	lockHoldQuery := `
+		SELECT amount_cents
+		FROM transactions
+		WHERE id = $1 AND hold_status = $2 AND hold_expires_at < NOW()
+		FOR UPDATE
+	`
+	if err := tx.QueryRowContext(ctx, lockHoldQuery, holdID, types.HoldStatusHeld).Scan(&amountCents); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to lock hold: %w", err)
+	}
+
+	releaseQuery := `
+		UPDATE accounts
+		SET held_cents = held_cents - $1, updated_at = NOW()
+		WHERE id = $2
+	`
+	if _, err := tx.ExecContext(ctx, releaseQuery, amountCents, accountID); err != nil {
+		return false, fmt.Errorf("failed to release hold: %w", err)
+	}
+
+	expireQuery := `
+		UPDATE transactions
+		SET hold_status = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+	if _, err := tx.ExecContext(ctx, expireQuery, types.HoldStatusExpired, holdID); err != nil {
+		return false, fmt.Errorf("failed to expire hold: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
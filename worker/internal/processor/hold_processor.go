@@ -0,0 +1,147 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/types"
+)
+
+// authorizationState is the locked state of an AUTHORIZE transaction
+type authorizationState struct {
+	AccountID     uuid.UUID
+	AmountCents   int64
+	Status        types.TransactionStatus
+	HoldStatus    *types.HoldStatus
+	HoldExpiresAt *time.Time
+}
+
+// applyAuthorize reserves funds against the available balance without moving
+// them. The authorization counts against the debit limits; its captures do not.
+func (p *TransactionProcessor) applyAuthorize(ctx context.Context, tx *sql.Tx, payload types.TransactionCreatedPayload, account accountState) (int64, error) {
+	if !account.CanDebit(payload.AmountCents) {
+		return 0, reject("%s", account.insufficientFunds("authorize", payload.AmountCents))
+	}
+	if err := p.checkLimits(ctx, tx, payload.TransactionID, payload.AccountID, payload.AmountCents); err != nil {
+		return 0, err
+	}
+
+	holdQuery := `
+		UPDATE accounts
+		SET held_cents = held_cents + $1, updated_at = NOW()
+		WHERE id = $2
+	`
+	if _, err := tx.ExecContext(ctx, holdQuery, payload.AmountCents, payload.AccountID); err != nil {
+		return 0, fmt.Errorf("failed to place hold: %w", err)
+	}
+
+	authorizeQuery := `
+		UPDATE transactions
+		SET hold_status = $1, hold_expires_at = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+	_, err := tx.ExecContext(ctx, authorizeQuery, types.HoldStatusHeld, time.Now().Add(p.holdTTL), payload.TransactionID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record hold: %w", err)
+	}
+
+	return account.BalanceCents, nil
+}
+
+// applyCapture settles a held authorization. A partial capture settles the
+// captured amount and releases the remainder of the hold.
+func (p *TransactionProcessor) applyCapture(ctx context.Context, tx *sql.Tx, payload types.TransactionCreatedPayload, account accountState) (int64, error) {
+	auth, err := p.lockActiveAuthorization(ctx, tx, payload)
+	if err != nil {
+		return 0, err
+	}
+
+	if payload.AmountCents > auth.AmountCents {
+		return 0, reject("capture exceeds authorized amount: authorized=%d, capture=%d", auth.AmountCents, payload.AmountCents)
+	}
+
+	if err := p.releaseHold(ctx, tx, *payload.AuthorizationID, auth, types.HoldStatusCaptured, &payload.AmountCents); err != nil {
+		return 0, err
+	}
+
+	return p.postAgainstSettlement(ctx, tx, payload.TransactionID, payload.AccountID, types.PostingDirectionDebit, payload.AmountCents, account.Currency)
+}
+
+// applyVoid releases a held authorization without moving funds
+func (p *TransactionProcessor) applyVoid(ctx context.Context, tx *sql.Tx, payload types.TransactionCreatedPayload, account accountState) (int64, error) {
+	auth, err := p.lockActiveAuthorization(ctx, tx, payload)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := p.releaseHold(ctx, tx, *payload.AuthorizationID, auth, types.HoldStatusVoided, nil); err != nil {
+		return 0, err
+	}
+
+	return account.BalanceCents, nil
+}
+
+// lockActiveAuthorization locks the authorization referenced by a CAPTURE or
+// VOID and checks that its hold is still open
+func (p *TransactionProcessor) lockActiveAuthorization(ctx context.Context, tx *sql.Tx, payload types.TransactionCreatedPayload) (*authorizationState, error) {
+	if payload.AuthorizationID == nil {
+		return nil, reject("authorization_id is required for %s", payload.Type)
+	}
+
+	var auth authorizationState
+	var txType types.TransactionType
+	lockQuery := `
+		SELECT account_id, amount_cents, type, status, hold_status, hold_expires_at
+		FROM transactions
+		WHERE id = $1
+		FOR UPDATE
+	`
+	err := tx.QueryRowContext(ctx, lockQuery, payload.AuthorizationID).Scan(
+		&auth.AccountID, &auth.AmountCents, &txType, &auth.Status, &auth.HoldStatus, &auth.HoldExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, reject("authorization not found")
+		}
+		return nil, fmt.Errorf("failed to lock authorization: %w", err)
+	}
+
+	if txType != types.TransactionTypeAuthorize || auth.AccountID != payload.AccountID {
+		return nil, reject("transaction %s is not an authorization for this account", payload.AuthorizationID)
+	}
+	if auth.HoldStatus == nil || *auth.HoldStatus != types.HoldStatusHeld {
+		return nil, reject("authorization is not active")
+	}
+	if auth.HoldExpiresAt != nil && auth.HoldExpiresAt.Before(time.Now()) {
+		return nil, reject("authorization has expired")
+	}
+
+	return &auth, nil
+}
+
+// releaseHold closes an authorization hold and returns its funds to the available balance
+func (p *TransactionProcessor) releaseHold(ctx context.Context, tx *sql.Tx, authorizationID uuid.UUID, auth *authorizationState, holdStatus types.HoldStatus, capturedAmountCents *int64) error {
+	releaseQuery := `
+		UPDATE accounts
+		SET held_cents = held_cents - $1, updated_at = NOW()
+		WHERE id = $2
+	`
+	if _, err := tx.ExecContext(ctx, releaseQuery, auth.AmountCents, auth.AccountID); err != nil {
+		return fmt.Errorf("failed to release hold: %w", err)
+	}
+
+	closeQuery := `
+		UPDATE transactions
+		SET hold_status = $1, captured_amount_cents = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+	if _, err := tx.ExecContext(ctx, closeQuery, holdStatus, capturedAmountCents, authorizationID); err != nil {
+		return fmt.Errorf("failed to close hold: %w", err)
+	}
+
+	return nil
+}
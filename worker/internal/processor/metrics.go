@@ -38,5 +38,28 @@ var (
 		},
 	)
 
+	holdsExpiredTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "authorization_holds_expired_total",
+			Help: "Total number of authorization holds released by expiry",
+		},
+	)
+
+	riskDecisionsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "risk_decisions_total",
+			Help: "Total number of transactions screened by the risk rules, by decision",
+		},
+		[]string{"decision"},
+	)
+
+	feesChargedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "fees_charged_cents_total",
+			Help: "Total fees charged in cents, by transaction type and currency",
+		},
+		[]string{"type", "currency"},
+	)
+
 	RetryCounter = retryCounter
 )
@@ -0,0 +1,82 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/outbox"
+	"github.com/yash/transaction-system/shared/types"
+)
+
+// applyReversal applies a transaction that reverses a processed one and emits
+// transaction.reversed. The original is locked so that concurrent reversals
+// can never refund more than the original amount in total.
+func (p *TransactionProcessor) applyReversal(ctx context.Context, tx *sql.Tx, payload types.TransactionCreatedPayload, account accountState, occurredAt time.Time) (int64, error) {
+	var originalAccountID uuid.UUID
+	var originalAmount int64
+	var originalType types.TransactionType
+	var originalStatus types.TransactionStatus
+	lockQuery := `
+		SELECT account_id, amount_cents, type, status
+		FROM transactions
+		WHERE id = $1
+		FOR UPDATE
+	`
+	err := tx.QueryRowContext(ctx, lockQuery, payload.ReversesTransactionID).Scan(
+		&originalAccountID, &originalAmount, &originalType, &originalStatus,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, reject("original transaction not found")
+		}
+		return 0, fmt.Errorf("failed to lock original transaction: %w", err)
+	}
+
+	reversalType, ok := originalType.ReversalType()
+	if !ok || reversalType != payload.Type || originalAccountID != payload.AccountID {
+		return 0, reject("transaction %s cannot be reversed by a %s", payload.ReversesTransactionID, payload.Type)
+	}
+	if originalStatus != types.TransactionStatusProcessed {
+		return 0, reject("original transaction is %s, not PROCESSED", originalStatus)
+	}
+
+	var reversedCents int64
+	reversedQuery := `
+		SELECT COALESCE(SUM(amount_cents), 0)
+		FROM transactions
+		WHERE reverses_transaction_id = $1 AND status = 'PROCESSED'
+	`
+	if err := tx.QueryRowContext(ctx, reversedQuery, payload.ReversesTransactionID).Scan(&reversedCents); err != nil {
+		return 0, fmt.Errorf("failed to sum reversals: %w", err)
+	}
+	if reversedCents+payload.AmountCents > originalAmount {
+		return 0, reject("reversal exceeds original amount: original=%d, reversed=%d, reversal=%d",
+			originalAmount, reversedCents, payload.AmountCents)
+	}
+
+	newBalance, err := p.applyBalanceChange(ctx, tx, payload, account, occurredAt)
+	if err != nil {
+		return 0, err
+	}
+
+	totalReversed := reversedCents + payload.AmountCents
+	reversedPayload := types.TransactionReversedPayload{
+		TransactionID:         payload.TransactionID,
+		ReversesTransactionID: *payload.ReversesTransactionID,
+		AccountID:             payload.AccountID,
+		AmountCents:           payload.AmountCents,
+		Currency:              payload.Currency,
+		TotalReversedCents:    totalReversed,
+		FullyReversed:         totalReversed == originalAmount,
+		NewBalance:            newBalance,
+	}
+	if err := outbox.Write(ctx, tx, "transaction", *payload.ReversesTransactionID, types.EventTypeTransactionReversed, reversedPayload); err != nil {
+		return 0, err
+	}
+
+	return newBalance, nil
+}
@@ -9,21 +9,59 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/currency"
+	"github.com/yash/transaction-system/shared/limits"
+	"github.com/yash/transaction-system/shared/risk"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/txstatus"
 	"github.com/yash/transaction-system/shared/types"
+	"github.com/yash/transaction-system/worker/internal/ledger"
 	"go.uber.org/zap"
 )
 
 // TransactionProcessor processes transaction events
 type TransactionProcessor struct {
-	db     *sql.DB
-	logger *zap.Logger
+	db                 *sql.DB
+	ledger             *ledger.Ledger
+	holdTTL            time.Duration
+	fxRevenueAccounts  map[string]uuid.UUID
+	feeRevenueAccounts map[string]uuid.UUID
+	riskEngine         *risk.Engine
+	logger             *zap.Logger
 }
 
-// NewTransactionProcessor creates a new transaction processor
-func NewTransactionProcessor(db *sql.DB, logger *zap.Logger) *TransactionProcessor {
+// NewTransactionProcessor creates a new transaction processor. FX spread is
+// posted to fxRevenueAccounts by currency, or to the FX_REVENUE system account,
+// and fees likewise to feeRevenueAccounts or FEE_REVENUE. Transactions are
+// screened by riskEngine before they are applied.
+func NewTransactionProcessor(db *sql.DB, holdTTL time.Duration, fxRevenueAccounts, feeRevenueAccounts map[string]uuid.UUID, riskEngine *risk.Engine, logger *zap.Logger) *TransactionProcessor {
 	return &TransactionProcessor{
-		db:     db,
-		logger: logger,
+		db:                 db,
+		ledger:             ledger.NewLedger(logger),
+		holdTTL:            holdTTL,
+		fxRevenueAccounts:  fxRevenueAccounts,
+		feeRevenueAccounts: feeRevenueAccounts,
+		riskEngine:         riskEngine,
+		logger:             logger,
+	}
+}
+
+// ProcessEvent dispatches an event to the handler for its type. Handlers only
+// touch rows of the envelope's tenant; events published before tenants
+// existed belong to the default tenant.
+// Returns: (shouldRetry bool, error)
+func (p *TransactionProcessor) ProcessEvent(ctx context.Context, envelope types.EventEnvelope) (bool, error) {
+	ctx = tenant.WithID(ctx, envelope.TenantID)
+	switch envelope.EventType {
+	case types.EventTypeTransactionCreated:
+		return p.ProcessTransactionCreated(ctx, envelope)
+	case types.EventTypeTransferCreated:
+		return p.ProcessTransferCreated(ctx, envelope)
+	default:
+		// Outcome events (e.g. transfer.processed) share the topic but need no work here
+		eventsConsumedTotal.WithLabelValues(envelope.EventType, "ignored").Inc()
+		p.logger.Debug("Ignoring event", zap.String("event_type", envelope.EventType))
+		return false, nil
 	}
 }
 
@@ -45,11 +83,11 @@ func (p *TransactionProcessor) ProcessTransactionCreated(ctx context.Context, en
 	}
 
 	// Check idempotency: has this event been processed?
-	var processedEventID uuid.UUID
-	checkQuery := `SELECT event_id FROM processed_events WHERE event_id = $1`
-	err := p.db.QueryRowContext(ctx, checkQuery, envelope.EventID).Scan(&processedEventID)
-
-	if err == nil {
+	processed, err := p.isEventProcessed(ctx, envelope.EventID)
+	if err != nil {
+		return true, err
+	}
+	if processed {
 		// Already processed - idempotent no-op
 		eventsConsumedTotal.WithLabelValues(envelope.EventType, "duplicate").Inc()
 		p.logger.Info("Event already processed (idempotent)",
@@ -59,10 +97,6 @@ func (p *TransactionProcessor) ProcessTransactionCreated(ctx context.Context, en
 		return false, nil
 	}
 
-	if !errors.Is(err, sql.ErrNoRows) {
-		return true, fmt.Errorf("failed to check idempotency: %w", err)
-	}
-
 	// Start transaction for atomic processing
 	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{
 		Isolation: sql.LevelSerializable,
@@ -77,21 +111,11 @@ func (p *TransactionProcessor) ProcessTransactionCreated(ctx context.Context, en
 	}()
 
 	// Insert into processed_events first (idempotency check)
-	insertProcessedQuery := `
-		INSERT INTO processed_events (event_id, transaction_id)
-		VALUES ($1, $2)
-		ON CONFLICT (event_id) DO NOTHING
-	`
-	result, err := tx.ExecContext(ctx, insertProcessedQuery, envelope.EventID, payload.TransactionID)
+	claimed, err := claimEvent(ctx, tx, envelope.EventID, payload.TransactionID)
 	if err != nil {
-		return true, fmt.Errorf("failed to insert processed event: %w", err)
+		return true, err
 	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return true, fmt.Errorf("failed to get rows affected: %w", err)
-	}
-	if rowsAffected == 0 {
+	if !claimed {
 		// Another worker processed it - no-op
 		tx.Commit()
 		p.logger.Info("Event processed by another worker (idempotent)",
@@ -100,85 +124,131 @@ func (p *TransactionProcessor) ProcessTransactionCreated(ctx context.Context, en
 		return false, nil
 	}
 
-	// Update transaction status to PROCESSING
-	updateStatusQuery := `
-		UPDATE transactions
-		SET status = 'PROCESSING', updated_at = NOW()
-		WHERE id = $1 AND status = 'PENDING'
-	`
-	_, err = tx.ExecContext(ctx, updateStatusQuery, payload.TransactionID)
-	if err != nil {
-		return true, fmt.Errorf("failed to update transaction status: %w", err)
+	// Lock the transaction. One cancelled through the API after its event was
+	// published is skipped; the claimed event keeps redeliveries from retrying.
+	tenantID := tenant.FromContext(ctx)
+	var status types.TransactionStatus
+	lockTransactionQuery := `SELECT status FROM transactions WHERE id = $1 AND tenant_id = $2 FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, lockTransactionQuery, payload.TransactionID, tenantID).Scan(&status); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, fmt.Errorf("transaction not found")
+		}
+		return true, fmt.Errorf("failed to lock transaction: %w", err)
+	}
+	if status == types.TransactionStatusCancelled {
+		if err := tx.Commit(); err != nil {
+			return true, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+		eventsConsumedTotal.WithLabelValues(envelope.EventType, "cancelled").Inc()
+		p.logger.Info("Skipping cancelled transaction",
+			zap.String("event_id", envelope.EventID.String()),
+			zap.String("transaction_id", payload.TransactionID.String()),
+		)
+		return false, nil
 	}
 
-	// Lock account row and update balance
-	var currentBalance int64
-	var accountCurrency string
+	// Move the transaction to PROCESSING. Any status but PENDING is a state
+	// machine violation that retrying cannot fix.
+	if _, err := txstatus.Transition(ctx, tx, payload.TransactionID, types.TransactionStatusProcessing, "", txstatus.ActorWorker); err != nil {
+		if errors.Is(err, types.ErrInvalidTransition) {
+			return false, err
+		}
+		return true, err
+	}
+
+	// Lock account row. An account of another tenant is not found; a missing
+	// account is permanent, so it fails the transaction rather than retrying.
+	var account accountState
 	lockAccountQuery := `
-		SELECT balance_cents, currency
+		SELECT balance_cents, held_cents, overdraft_limit_cents, kind, currency, status
 		FROM accounts
-		WHERE id = $1
+		WHERE id = $1 AND tenant_id = $2
 		FOR UPDATE
 	`
-	err = tx.QueryRowContext(ctx, lockAccountQuery, payload.AccountID).Scan(&currentBalance, &accountCurrency)
+	err = tx.QueryRowContext(ctx, lockAccountQuery, payload.AccountID, tenantID).Scan(
+		&account.BalanceCents, &account.HeldCents, &account.OverdraftLimitCents, &account.Kind, &account.Currency, &account.Status,
+	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return false, fmt.Errorf("account not found")
+			failureReason := fmt.Sprintf("account %s not found", payload.AccountID)
+			if err := p.failTransaction(ctx, tx, payload.TransactionID, failureReason, types.FailureCodeAccountNotFound); err != nil {
+				return true, err
+			}
+
+			eventsConsumedTotal.WithLabelValues(envelope.EventType, "failed").Inc()
+			tx.Commit()
+			return false, fmt.Errorf("transaction rejected: %s", failureReason)
 		}
 		return true, fmt.Errorf("failed to lock account: %w", err)
 	}
 
-	// Validate currency match
-	if accountCurrency != payload.Currency {
-		return false, fmt.Errorf("currency mismatch: account=%s, transaction=%s", accountCurrency, payload.Currency)
+	// Screen against the risk rules with the account locked, so velocity
+	// facts include every transaction applied before this one
+	decision, err := p.screen(ctx, tx, payload, account)
+	if err != nil {
+		return true, err
 	}
 
-	// Calculate new balance
-	var newBalance int64
-	if payload.Type == types.TransactionTypeCredit {
-		newBalance = currentBalance + payload.AmountCents
-	} else { // DEBIT
-		newBalance = currentBalance - payload.AmountCents
-	}
-
-	// Validate debit doesn't go negative (business rule)
-	if newBalance < 0 && payload.Type == types.TransactionTypeDebit {
-		// Mark transaction as failed
-		failureReason := fmt.Sprintf("insufficient balance: current=%d, debit=%d", currentBalance, payload.AmountCents)
-		failQuery := `
-			UPDATE transactions
-			SET status = 'FAILED', failure_reason = $1, updated_at = NOW()
-			WHERE id = $2
-		`
-		_, err = tx.ExecContext(ctx, failQuery, failureReason, payload.TransactionID)
-		if err != nil {
-			return true, fmt.Errorf("failed to mark transaction as failed: %w", err)
-		}
-
-		eventsConsumedTotal.WithLabelValues(envelope.EventType, "failed").Inc()
-		tx.Commit()
-		return false, fmt.Errorf("insufficient balance: %s", failureReason)
+	// A rejection after postings were made (e.g. an unaffordable fee) rolls
+	// back to here before the transaction is failed
+	if _, err := tx.ExecContext(ctx, `SAVEPOINT apply_transaction`); err != nil {
+		return true, fmt.Errorf("failed to create savepoint: %w", err)
 	}
 
-	// Update account balance
-	updateBalanceQuery := `
-		UPDATE accounts
-		SET balance_cents = $1, updated_at = NOW()
-		WHERE id = $2
-	`
-	_, err = tx.ExecContext(ctx, updateBalanceQuery, newBalance, payload.AccountID)
+	// Apply the transaction according to its type. Only CREDIT and DEBIT are
+	// converted between currencies; holds stay in the account currency.
+	var newBalance int64
+	isBalanceChange := payload.Type == types.TransactionTypeCredit || payload.Type == types.TransactionTypeDebit
+	switch {
+	case account.Status == types.AccountStatusClosed:
+		err = reject("account is closed")
+	case !currency.Valid(payload.Currency):
+		err = reject("unsupported currency %s", payload.Currency)
+	case decision.Action == types.RiskActionDeny:
+		err = &rejectionError{reason: decision.Reason, code: types.FailureCodeRiskDenied}
+	case decision.Action == types.RiskActionReview:
+		return p.holdForReview(ctx, tx, envelope, payload, decision)
+	case account.Currency != payload.Currency && !isBalanceChange:
+		err = reject("currency mismatch: account=%s, transaction=%s", account.Currency, payload.Currency)
+	case isBalanceChange && payload.ReversesTransactionID != nil:
+		newBalance, err = p.applyReversal(ctx, tx, payload, account, envelope.OccurredAt)
+	case isBalanceChange:
+		newBalance, err = p.applyBalanceChange(ctx, tx, payload, account, envelope.OccurredAt)
+	case payload.Type == types.TransactionTypeAuthorize:
+		newBalance, err = p.applyAuthorize(ctx, tx, payload, account)
+	case payload.Type == types.TransactionTypeCapture:
+		newBalance, err = p.applyCapture(ctx, tx, payload, account)
+	case payload.Type == types.TransactionTypeVoid:
+		newBalance, err = p.applyVoid(ctx, tx, payload, account)
+	default:
+		err = reject("unsupported transaction type %s", payload.Type)
+	}
+	if err == nil {
+		newBalance, err = p.chargeFee(ctx, tx, payload, account, newBalance)
+	}
 	if err != nil {
-		return true, fmt.Errorf("failed to update balance: %w", err)
+		var rejection *rejectionError
+		if errors.As(err, &rejection) {
+			// Business rule violation - mark transaction as failed
+			if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT apply_transaction`); err != nil {
+				return true, fmt.Errorf("failed to roll back to savepoint: %w", err)
+			}
+			if err := p.failTransaction(ctx, tx, payload.TransactionID, rejection.reason, rejection.code); err != nil {
+				return true, err
+			}
+
+			eventsConsumedTotal.WithLabelValues(envelope.EventType, "failed").Inc()
+			tx.Commit()
+			return false, fmt.Errorf("transaction rejected: %s", rejection.reason)
+		}
+		if errors.Is(err, ledger.ErrUnbalancedEntry) {
+			return false, err
+		}
+		return true, err
 	}
 
 	// Mark transaction as PROCESSED
-	markProcessedQuery := `
-		UPDATE transactions
-		SET status = 'PROCESSED', updated_at = NOW()
-		WHERE id = $1
-	`
-	_, err = tx.ExecContext(ctx, markProcessedQuery, payload.TransactionID)
-	if err != nil {
+	if _, err := txstatus.Transition(ctx, tx, payload.TransactionID, types.TransactionStatusProcessed, "", txstatus.ActorWorker); err != nil {
 		return true, fmt.Errorf("failed to mark transaction as processed: %w", err)
 	}
 
@@ -191,10 +261,235 @@ func (p *TransactionProcessor) ProcessTransactionCreated(ctx context.Context, en
 	p.logger.Info("Transaction processed successfully",
 		zap.String("transaction_id", payload.TransactionID.String()),
 		zap.String("account_id", payload.AccountID.String()),
-		zap.Int64("old_balance", currentBalance),
+		zap.Int64("old_balance", account.BalanceCents),
 		zap.Int64("new_balance", newBalance),
 		zap.String("type", string(payload.Type)),
 	)
 
 	return false, nil
 }
+
+// accountState is the locked state of the account a transaction applies to
+type accountState struct {
+	BalanceCents        int64
+	HeldCents           int64
+	OverdraftLimitCents int64
+	Kind                types.AccountKind
+	Currency            string
+	Status              types.AccountStatus
+}
+
+// Available returns the balance not reserved by authorization holds
+func (a accountState) Available() int64 {
+	return a.BalanceCents - a.HeldCents
+}
+
+// CanDebit reports whether the available balance plus the overdraft limit
+// covers the amount. A credit line only lends up to its credit limit: an
+// overpaid, positive balance is owed back to the borrower and cannot be drawn.
+func (a accountState) CanDebit(amountCents int64) bool {
+	available := a.Available()
+	if a.Kind == types.AccountKindCreditLine && available > 0 {
+		available = 0
+	}
+	return available+a.OverdraftLimitCents >= amountCents
+}
+
+// insufficientFunds describes why a debit of the given kind was refused
+func (a accountState) insufficientFunds(kind string, amountCents int64) string {
+	return fmt.Sprintf("insufficient balance: current=%d, available=%d, overdraft_limit=%d, %s=%d",
+		a.BalanceCents, a.Available(), a.OverdraftLimitCents, kind, amountCents)
+}
+
+// rejectionError is a business rule violation. The transaction is marked
+// FAILED instead of being retried. Code is set for violations clients are
+// expected to handle, such as debit limits.
+type rejectionError struct {
+	reason string
+	code   types.FailureCode
+}
+
+func (e *rejectionError) Error() string {
+	return e.reason
+}
+
+func reject(format string, args ...interface{}) error {
+	return &rejectionError{reason: fmt.Sprintf(format, args...)}
+}
+
+// checkLimits rejects a debit that would exceed the account's limits given
+// the debits already processed. The account row must be locked so concurrent
+// debits are checked one after another.
+func (p *TransactionProcessor) checkLimits(ctx context.Context, tx *sql.Tx, transactionID, accountID uuid.UUID, amountCents int64) error {
+	violation, err := limits.Check(ctx, tx, accountID, amountCents, time.Now(), limits.ProcessedStatuses, transactionID)
+	if err != nil {
+		return err
+	}
+	if violation != nil {
+		return &rejectionError{reason: violation.Reason, code: violation.Code}
+	}
+	return nil
+}
+
+// screen evaluates the risk rules against a new DEBIT, CREDIT or AUTHORIZE.
+// Reversals, interest postings, captures and voids of screened holds, and
+// transactions a reviewer has approved are allowed without evaluation.
+func (p *TransactionProcessor) screen(ctx context.Context, tx *sql.Tx, payload types.TransactionCreatedPayload, account accountState) (risk.Decision, error) {
+	allow := risk.Decision{Action: types.RiskActionAllow}
+	switch {
+	case account.Status == types.AccountStatusClosed, payload.ReversesTransactionID != nil, payload.InterestPeriod != nil:
+		return allow, nil
+	case payload.Type != types.TransactionTypeDebit && payload.Type != types.TransactionTypeCredit && payload.Type != types.TransactionTypeAuthorize:
+		return allow, nil
+	}
+
+	var reviewDecision *types.ReviewDecision
+	if err := tx.QueryRowContext(ctx, `SELECT review_decision FROM transactions WHERE id = $1`, payload.TransactionID).Scan(&reviewDecision); err != nil {
+		return allow, fmt.Errorf("failed to load review decision: %w", err)
+	}
+	if reviewDecision != nil && *reviewDecision == types.ReviewDecisionApproved {
+		return allow, nil
+	}
+
+	decision, err := p.riskEngine.Evaluate(ctx, tx, payload)
+	if err != nil {
+		return allow, fmt.Errorf("failed to evaluate risk rules: %w", err)
+	}
+	riskDecisionsTotal.WithLabelValues(string(decision.Action)).Inc()
+	if decision.Rule != "" {
+		p.logger.Info("Risk rule matched",
+			zap.String("transaction_id", payload.TransactionID.String()),
+			zap.String("rule", decision.Rule),
+			zap.String("action", string(decision.Action)),
+		)
+	}
+	return decision, nil
+}
+
+// holdForReview parks a transaction in IN_REVIEW until it is approved or
+// rejected through the API. Approval writes a new transaction.created event.
+func (p *TransactionProcessor) holdForReview(ctx context.Context, tx *sql.Tx, envelope types.EventEnvelope, payload types.TransactionCreatedPayload, decision risk.Decision) (bool, error) {
+	if _, err := txstatus.Transition(ctx, tx, payload.TransactionID, types.TransactionStatusInReview, decision.Reason, txstatus.ActorWorker); err != nil {
+		return true, fmt.Errorf("failed to hold transaction for review: %w", err)
+	}
+	reviewQuery := `
+		UPDATE transactions
+		SET review_reason = $1, updated_at = NOW()
+		WHERE id = $2
+	`
+	if _, err := tx.ExecContext(ctx, reviewQuery, decision.Reason, payload.TransactionID); err != nil {
+		return true, fmt.Errorf("failed to hold transaction for review: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return true, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	eventsConsumedTotal.WithLabelValues(envelope.EventType, "in_review").Inc()
+	p.logger.Info("Transaction held for review",
+		zap.String("transaction_id", payload.TransactionID.String()),
+		zap.String("rule", decision.Rule),
+	)
+	return false, nil
+}
+
+// applyBalanceChange applies a CREDIT or DEBIT against the settlement account,
+// converting it first if it is not in the account currency. occurredAt is the
+// time of its event.
+func (p *TransactionProcessor) applyBalanceChange(ctx context.Context, tx *sql.Tx, payload types.TransactionCreatedPayload, account accountState, occurredAt time.Time) (int64, error) {
+	direction := types.PostingDirectionCredit
+	if payload.Type == types.TransactionTypeDebit {
+		direction = types.PostingDirectionDebit
+	}
+	if payload.Currency != account.Currency {
+		return p.applyConversion(ctx, tx, payload, account, direction, occurredAt)
+	}
+
+	// Validate debit stays within the overdraft limit (business rule). Interest
+	// charged on a credit line is owed regardless, so it is always posted.
+	isInterest := payload.InterestPeriod != nil
+	if direction == types.PostingDirectionDebit && !isInterest && !account.CanDebit(payload.AmountCents) {
+		return 0, reject("%s", account.insufficientFunds("debit", payload.AmountCents))
+	}
+	if direction == types.PostingDirectionDebit && !isInterest && payload.ReversesTransactionID == nil {
+		if err := p.checkLimits(ctx, tx, payload.TransactionID, payload.AccountID, payload.AmountCents); err != nil {
+			return 0, err
+		}
+	}
+
+	return p.postAgainstSettlement(ctx, tx, payload.TransactionID, payload.AccountID, direction, payload.AmountCents, account.Currency)
+}
+
+// postAgainstSettlement posts a balanced journal entry between the account and
+// the settlement account; the account balance is derived from the postings
+func (p *TransactionProcessor) postAgainstSettlement(ctx context.Context, tx *sql.Tx, transactionID, accountID uuid.UUID, direction types.PostingDirection, amountCents int64, currency string) (int64, error) {
+	settlementAccountID, err := p.ledger.SystemAccountID(ctx, tx, types.SystemAccountSettlement, currency)
+	if err != nil {
+		return 0, err
+	}
+
+	entry := types.JournalEntry{
+		TransactionID: &transactionID,
+		Description:   fmt.Sprintf("%s transaction %s", direction, transactionID),
+		Postings: []types.Posting{
+			{AccountID: accountID, Direction: direction, AmountCents: amountCents, Currency: currency},
+			{AccountID: settlementAccountID, Direction: direction.Opposite(), AmountCents: amountCents, Currency: currency},
+		},
+	}
+	balances, err := p.ledger.Post(ctx, tx, &entry)
+	if err != nil {
+		return 0, err
+	}
+
+	return balances[accountID], nil
+}
+
+// failTransaction marks a transaction as FAILED with the given reason and, if
+// not empty, failure code
+func (p *TransactionProcessor) failTransaction(ctx context.Context, tx *sql.Tx, transactionID uuid.UUID, failureReason string, failureCode types.FailureCode) error {
+	if _, err := txstatus.Transition(ctx, tx, transactionID, types.TransactionStatusFailed, failureReason, txstatus.ActorWorker); err != nil {
+		return fmt.Errorf("failed to mark transaction as failed: %w", err)
+	}
+	failQuery := `
+		UPDATE transactions
+		SET failure_reason = $1, failure_code = NULLIF($2, ''), updated_at = NOW()
+		WHERE id = $3
+	`
+	if _, err := tx.ExecContext(ctx, failQuery, failureReason, string(failureCode), transactionID); err != nil {
+		return fmt.Errorf("failed to mark transaction as failed: %w", err)
+	}
+	return nil
+}
+
+// isEventProcessed reports whether an event has already been applied
+func (p *TransactionProcessor) isEventProcessed(ctx context.Context, eventID uuid.UUID) (bool, error) {
+	var processedEventID uuid.UUID
+	checkQuery := `SELECT event_id FROM processed_events WHERE event_id = $1`
+	err := p.db.QueryRowContext(ctx, checkQuery, eventID).Scan(&processedEventID)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to check idempotency: %w", err)
+}
+
+// claimEvent records the event in processed_events inside the processing
+// transaction. Returns false if another worker already claimed it.
+func claimEvent(ctx context.Context, tx *sql.Tx, eventID, transactionID uuid.UUID) (bool, error) {
+	insertProcessedQuery := `
+		INSERT INTO processed_events (event_id, transaction_id)
+		VALUES ($1, $2)
+		ON CONFLICT (event_id) DO NOTHING
+	`
+	result, err := tx.ExecContext(ctx, insertProcessedQuery, eventID, transactionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert processed event: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
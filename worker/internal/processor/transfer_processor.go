@@ -0,0 +1,284 @@
+package processor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/currency"
+	"github.com/yash/transaction-system/shared/outbox"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/txstatus"
+	"github.com/yash/transaction-system/shared/types"
+	"github.com/yash/transaction-system/worker/internal/ledger"
+	"go.uber.org/zap"
+)
+
+// ProcessTransferCreated processes a transfer.created event. Both legs are
+// applied in a single DB transaction so a transfer never half-completes.
+// Returns: (shouldRetry bool, error)
+func (p *TransactionProcessor) ProcessTransferCreated(ctx context.Context, envelope types.EventEnvelope) (bool, error) {
+	start := time.Now()
+	defer func() {
+		workerProcessingDuration.WithLabelValues(envelope.EventType).Observe(time.Since(start).Seconds())
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var payload types.TransferCreatedPayload
+	if err := json.Unmarshal(envelope.Payload, &payload); err != nil {
+		return false, fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	processed, err := p.isEventProcessed(ctx, envelope.EventID)
+	if err != nil {
+		return true, err
+	}
+	if processed {
+		eventsConsumedTotal.WithLabelValues(envelope.EventType, "duplicate").Inc()
+		p.logger.Info("Event already processed (idempotent)",
+			zap.String("event_id", envelope.EventID.String()),
+			zap.String("transfer_id", payload.TransferID.String()),
+		)
+		return false, nil
+	}
+
+	tx, err := p.db.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelSerializable,
+	})
+	if err != nil {
+		return true, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			p.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	claimed, err := claimEvent(ctx, tx, envelope.EventID, payload.DebitTransactionID)
+	if err != nil {
+		return true, err
+	}
+	if !claimed {
+		tx.Commit()
+		p.logger.Info("Event processed by another worker (idempotent)",
+			zap.String("event_id", envelope.EventID.String()),
+		)
+		return false, nil
+	}
+
+	// Move the transfer and both legs to PROCESSING
+	updateTransferQuery := `
+		UPDATE transfers
+		SET status = 'PROCESSING', updated_at = NOW()
+		WHERE id = $1 AND status = 'PENDING'
+	`
+	if _, err := tx.ExecContext(ctx, updateTransferQuery, payload.TransferID); err != nil {
+		return true, fmt.Errorf("failed to update transfer status: %w", err)
+	}
+	legIDs := []uuid.UUID{payload.DebitTransactionID, payload.CreditTransactionID}
+	for _, legID := range legIDs {
+		if _, err := txstatus.Transition(ctx, tx, legID, types.TransactionStatusProcessing, "", txstatus.ActorWorker); err != nil {
+			if errors.Is(err, types.ErrInvalidTransition) {
+				return false, err
+			}
+			return true, fmt.Errorf("failed to update transfer leg status: %w", err)
+		}
+	}
+	tenantID := tenant.FromContext(ctx)
+
+	// Lock both accounts in a deterministic order to avoid deadlocks between
+	// concurrent transfers in opposite directions. Accounts of another tenant
+	// are not found. A missing account or a currency mismatch is permanent, so
+	// it fails the transfer rather than leaving it PENDING.
+	var failureReason string
+	var failureCode types.FailureCode
+	first, second := payload.FromAccountID, payload.ToAccountID
+	if second.String() < first.String() {
+		first, second = second, first
+	}
+	accounts := make(map[uuid.UUID]accountState)
+	lockAccountQuery := `
+		SELECT balance_cents, held_cents, overdraft_limit_cents, kind, currency, status
+		FROM accounts
+		WHERE id = $1 AND tenant_id = $2
+		FOR UPDATE
+	`
+	for _, accountID := range []uuid.UUID{first, second} {
+		var account accountState
+		err := tx.QueryRowContext(ctx, lockAccountQuery, accountID, tenantID).Scan(
+			&account.BalanceCents, &account.HeldCents, &account.OverdraftLimitCents, &account.Kind, &account.Currency, &account.Status,
+		)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				if failureReason == "" {
+					failureReason = fmt.Sprintf("account %s not found", accountID)
+					failureCode = types.FailureCodeAccountNotFound
+				}
+				continue
+			}
+			return true, fmt.Errorf("failed to lock account: %w", err)
+		}
+		if account.Currency != payload.Currency && failureReason == "" {
+			failureReason = fmt.Sprintf("currency mismatch: account=%s, transfer=%s", account.Currency, payload.Currency)
+		}
+		accounts[accountID] = account
+	}
+
+	// Validate neither account was closed after the transfer was accepted and
+	// the source account can cover it
+	from, to := accounts[payload.FromAccountID], accounts[payload.ToAccountID]
+	switch {
+	case failureReason != "":
+	case !currency.Valid(payload.Currency):
+		failureReason = fmt.Sprintf("unsupported currency %s", payload.Currency)
+	case from.Status == types.AccountStatusClosed:
+		failureReason = fmt.Sprintf("account %s is closed", payload.FromAccountID)
+	case to.Status == types.AccountStatusClosed:
+		failureReason = fmt.Sprintf("account %s is closed", payload.ToAccountID)
+	case !from.CanDebit(payload.AmountCents):
+		failureReason = from.insufficientFunds("debit", payload.AmountCents)
+	default:
+		// The debit leg is screened like a DEBIT and counts against the source
+		// account's limits
+		err := p.screenTransfer(ctx, tx, payload, from)
+		if err == nil {
+			err = p.checkLimits(ctx, tx, payload.DebitTransactionID, payload.FromAccountID, payload.AmountCents)
+		}
+		var rejection *rejectionError
+		if errors.As(err, &rejection) {
+			failureReason, failureCode = rejection.reason, rejection.code
+		} else if err != nil {
+			return true, err
+		}
+	}
+	if failureReason != "" {
+		if err := p.failTransfer(ctx, tx, payload, failureReason, failureCode); err != nil {
+			return true, err
+		}
+
+		eventsConsumedTotal.WithLabelValues(envelope.EventType, "failed").Inc()
+		tx.Commit()
+		return false, fmt.Errorf("transfer rejected: %s", failureReason)
+	}
+
+	// Apply both legs as one balanced journal entry
+	entry := types.JournalEntry{
+		Description: fmt.Sprintf("Transfer %s", payload.TransferID),
+		Postings: []types.Posting{
+			{TransactionID: &payload.DebitTransactionID, AccountID: payload.FromAccountID, Direction: types.PostingDirectionDebit, AmountCents: payload.AmountCents, Currency: payload.Currency},
+			{TransactionID: &payload.CreditTransactionID, AccountID: payload.ToAccountID, Direction: types.PostingDirectionCredit, AmountCents: payload.AmountCents, Currency: payload.Currency},
+		},
+	}
+	newBalances, err := p.ledger.Post(ctx, tx, &entry)
+	if err != nil {
+		if errors.Is(err, ledger.ErrUnbalancedEntry) {
+			return false, err
+		}
+		return true, err
+	}
+
+	// Mark the transfer and both legs as PROCESSED
+	markTransferQuery := `
+		UPDATE transfers
+		SET status = 'PROCESSED', updated_at = NOW()
+		WHERE id = $1
+	`
+	if _, err := tx.ExecContext(ctx, markTransferQuery, payload.TransferID); err != nil {
+		return true, fmt.Errorf("failed to mark transfer as processed: %w", err)
+	}
+	for _, legID := range legIDs {
+		if _, err := txstatus.Transition(ctx, tx, legID, types.TransactionStatusProcessed, "", txstatus.ActorWorker); err != nil {
+			return true, fmt.Errorf("failed to mark transfer leg as processed: %w", err)
+		}
+	}
+
+	processedPayload := types.TransferProcessedPayload{
+		TransferID:         payload.TransferID,
+		FromAccountID:      payload.FromAccountID,
+		ToAccountID:        payload.ToAccountID,
+		AmountCents:        payload.AmountCents,
+		Currency:           payload.Currency,
+		FromAccountBalance: newBalances[payload.FromAccountID],
+		ToAccountBalance:   newBalances[payload.ToAccountID],
+	}
+	if err := outbox.Write(ctx, tx, "transfer", payload.TransferID, types.EventTypeTransferProcessed, processedPayload); err != nil {
+		return true, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return true, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	eventsConsumedTotal.WithLabelValues(envelope.EventType, "success").Inc()
+	p.logger.Info("Transfer processed successfully",
+		zap.String("transfer_id", payload.TransferID.String()),
+		zap.String("from_account_id", payload.FromAccountID.String()),
+		zap.String("to_account_id", payload.ToAccountID.String()),
+		zap.Int64("amount_cents", payload.AmountCents),
+	)
+
+	return false, nil
+}
+
+// screenTransfer evaluates the risk rules against the debit leg of a transfer.
+// Transfers cannot be held for review, so a REVIEW rule rejects one like DENY.
+func (p *TransactionProcessor) screenTransfer(ctx context.Context, tx *sql.Tx, payload types.TransferCreatedPayload, from accountState) error {
+	debit := types.TransactionCreatedPayload{
+		TransactionID:  payload.DebitTransactionID,
+		AccountID:      payload.FromAccountID,
+		AmountCents:    payload.AmountCents,
+		Currency:       payload.Currency,
+		Type:           types.TransactionTypeDebit,
+		IdempotencyKey: payload.IdempotencyKey,
+		Metadata:       payload.Metadata,
+	}
+	decision, err := p.screen(ctx, tx, debit, from)
+	if err != nil {
+		return err
+	}
+	if decision.Action != types.RiskActionAllow {
+		return &rejectionError{reason: decision.Reason, code: types.FailureCodeRiskDenied}
+	}
+	return nil
+}
+
+// failTransfer marks a transfer and both legs as FAILED and emits transfer.failed
+func (p *TransactionProcessor) failTransfer(ctx context.Context, tx *sql.Tx, payload types.TransferCreatedPayload, failureReason string, failureCode types.FailureCode) error {
+	failTransferQuery := `
+		UPDATE transfers
+		SET status = 'FAILED', failure_reason = $1, failure_code = NULLIF($2, ''), updated_at = NOW()
+		WHERE id = $3
+	`
+	if _, err := tx.ExecContext(ctx, failTransferQuery, failureReason, string(failureCode), payload.TransferID); err != nil {
+		return fmt.Errorf("failed to mark transfer as failed: %w", err)
+	}
+
+	for _, legID := range []uuid.UUID{payload.DebitTransactionID, payload.CreditTransactionID} {
+		if _, err := txstatus.Transition(ctx, tx, legID, types.TransactionStatusFailed, failureReason, txstatus.ActorWorker); err != nil {
+			return fmt.Errorf("failed to mark transfer leg as failed: %w", err)
+		}
+	}
+	failLegsQuery := `
+		UPDATE transactions
+		SET failure_reason = $1, failure_code = NULLIF($2, ''), updated_at = NOW()
+		WHERE transfer_id = $3
+	`
+	if _, err := tx.ExecContext(ctx, failLegsQuery, failureReason, string(failureCode), payload.TransferID); err != nil {
+		return fmt.Errorf("failed to mark transfer legs as failed: %w", err)
+	}
+
+	failedPayload := types.TransferFailedPayload{
+		TransferID:    payload.TransferID,
+		FromAccountID: payload.FromAccountID,
+		ToAccountID:   payload.ToAccountID,
+		FailureReason: failureReason,
+		FailureCode:   failureCode,
+	}
+	return outbox.Write(ctx, tx, "transfer", payload.TransferID, types.EventTypeTransferFailed, failedPayload)
+}
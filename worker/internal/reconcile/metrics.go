@@ -0,0 +1,22 @@
+package reconcile
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	driftAccounts = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "reconciliation_drift_accounts",
+			Help: "Number of accounts whose balance drifted from their transactions in the last reconciliation run",
+		},
+	)
+
+	repairsTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "reconciliation_repairs_total",
+			Help: "Total number of account balances repaired by reconciliation",
+		},
+	)
+)
@@ -0,0 +1,265 @@
+package reconcile
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// Reconciler verifies that every account's stored balance equals the signed
+// sum of its PROCESSED transactions and records any drift it finds
+type Reconciler struct {
+	db         *sql.DB
+	logger     *zap.Logger
+	interval   time.Duration
+	autoRepair bool
+}
+
+// NewReconciler creates a new balance reconciler. With autoRepair set, a
+// drifted balance is reset to the expected balance when the ledger agrees.
+func NewReconciler(db *sql.DB, interval time.Duration, autoRepair bool, logger *zap.Logger) *Reconciler {
+	return &Reconciler{
+		db:         db,
+		logger:     logger,
+		interval:   interval,
+		autoRepair: autoRepair,
+	}
+}
+
+// Start reconciles immediately and then on every interval until the context is cancelled
+func (r *Reconciler) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.logger.Info("Balance reconciler started",
+		zap.Duration("interval", r.interval),
+		zap.Bool("auto_repair", r.autoRepair),
+	)
+
+	for {
+		if _, err := r.Run(ctx); err != nil {
+			r.logger.Error("Reconciliation failed", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Balance reconciler stopping...")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run performs one reconciliation pass and persists its findings. Returns
+// the completed run with its findings.
+func (r *Reconciler) Run(ctx context.Context) (*types.ReconciliationRun, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	runID := uuid.New()
+	startQuery := `INSERT INTO reconciliation_runs (id, status, auto_repair, started_at) VALUES ($1, $2, $3, NOW())`
+	if _, err := r.db.ExecContext(ctx, startQuery, runID, types.ReconciliationStatusRunning, r.autoRepair); err != nil {
+		return nil, fmt.Errorf("failed to start reconciliation run: %w", err)
+	}
+
+	scanned, findings, err := r.scan(ctx)
+	if err != nil {
+		r.finishRun(ctx, runID, types.ReconciliationStatusFailed, scanned, 0, 0, err)
+		return nil, err
+	}
+
+	repaired := 0
+	for i := range findings {
+		finding := &findings[i]
+		if r.autoRepair && finding.LedgerBalanceCents == finding.ExpectedBalanceCents {
+			ok, err := r.repair(ctx, runID, *finding)
+			if err != nil {
+				r.logger.Error("Failed to repair balance", zap.String("account_id", finding.AccountID.String()), zap.Error(err))
+			}
+			finding.Repaired = ok
+			if ok {
+				repaired++
+			}
+		}
+
+		finding.ID, finding.RunID = uuid.New(), runID
+		insertQuery := `
+			INSERT INTO reconciliation_findings (id, run_id, account_id, currency, balance_cents, expected_balance_cents, ledger_balance_cents, drift_cents, repaired, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		`
+		_, err := r.db.ExecContext(ctx, insertQuery,
+			finding.ID, runID, finding.AccountID, finding.Currency, finding.BalanceCents,
+			finding.ExpectedBalanceCents, finding.LedgerBalanceCents, finding.DriftCents, finding.Repaired,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to record finding: %w", err)
+			r.finishRun(ctx, runID, types.ReconciliationStatusFailed, scanned, len(findings), repaired, err)
+			return nil, err
+		}
+
+		r.logger.Warn("Balance drift detected",
+			zap.String("account_id", finding.AccountID.String()),
+			zap.Int64("balance_cents", finding.BalanceCents),
+			zap.Int64("expected_balance_cents", finding.ExpectedBalanceCents),
+			zap.Int64("ledger_balance_cents", finding.LedgerBalanceCents),
+			zap.Bool("repaired", finding.Repaired),
+		)
+	}
+
+	driftAccounts.Set(float64(len(findings) - repaired))
+	r.finishRun(ctx, runID, types.ReconciliationStatusCompleted, scanned, len(findings), repaired, nil)
+
+	r.logger.Info("Reconciliation completed",
+		zap.String("run_id", runID.String()),
+		zap.Int("accounts_scanned", scanned),
+		zap.Int("drift_accounts", len(findings)),
+		zap.Int("repaired_accounts", repaired),
+	)
+
+	return &types.ReconciliationRun{
+		ID:               runID,
+		Status:           types.ReconciliationStatusCompleted,
+		AutoRepair:       r.autoRepair,
+		AccountsScanned:  scanned,
+		DriftAccounts:    len(findings),
+		RepairedAccounts: repaired,
+		Findings:         findings,
+	}, nil
+}
+
+// scan compares every non-system account against its transactions and
+// postings in one consistent snapshot. The worker updates the balance and the
+// transaction status in the same DB transaction, so a healthy account never
+// shows drift here.
+func (r *Reconciler) scan(ctx context.Context) (int, []types.ReconciliationFinding, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// Converted transactions moved the account by their settled amount
+	query := `
+		SELECT a.id, a.currency, a.balance_cents, COALESCE(t.total, 0), COALESCE(l.total, 0)
+		FROM accounts a
+		LEFT JOIN (
+			SELECT account_id, SUM(CASE type
+				WHEN 'CREDIT' THEN COALESCE(settled_amount_cents, amount_cents)
+				WHEN 'DEBIT' THEN -COALESCE(settled_amount_cents, amount_cents)
+				WHEN 'CAPTURE' THEN -amount_cents
+				ELSE 0
+			END) AS total
+			FROM transactions
+			WHERE status = 'PROCESSED'
+			GROUP BY account_id
+		) t ON t.account_id = a.id
+		LEFT JOIN (
+			SELECT account_id, SUM(CASE direction WHEN 'CREDIT' THEN amount_cents ELSE -amount_cents END) AS total
+			FROM postings
+			GROUP BY account_id
+		) l ON l.account_id = a.id
+		WHERE a.system_code IS NULL
+	`
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to query balances: %w", err)
+	}
+	defer rows.Close()
+
+	scanned := 0
+	var findings []types.ReconciliationFinding
+	for rows.Next() {
+		var f types.ReconciliationFinding
+		if err := rows.Scan(&f.AccountID, &f.Currency, &f.BalanceCents, &f.ExpectedBalanceCents, &f.LedgerBalanceCents); err != nil {
+			return scanned, nil, fmt.Errorf("failed to scan balance: %w", err)
+		}
+		scanned++
+
+		if f.BalanceCents != f.ExpectedBalanceCents || f.LedgerBalanceCents != f.ExpectedBalanceCents {
+			f.DriftCents = f.BalanceCents - f.ExpectedBalanceCents
+			findings = append(findings, f)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return scanned, nil, fmt.Errorf("failed to query balances: %w", err)
+	}
+
+	return scanned, findings, nil
+}
+
+// repair resets a drifted balance to the expected balance. The update only
+// applies if the balance is still the one observed by the scan, so it never
+// overwrites a transaction processed in the meantime.
+func (r *Reconciler) repair(ctx context.Context, runID uuid.UUID, finding types.ReconciliationFinding) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	updateQuery := `
+		UPDATE accounts
+		SET balance_cents = $1, updated_at = NOW()
+		WHERE id = $2 AND balance_cents = $3
+	`
+	result, err := tx.ExecContext(ctx, updateQuery, finding.ExpectedBalanceCents, finding.AccountID, finding.BalanceCents)
+	if err != nil {
+		return false, fmt.Errorf("failed to repair balance: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return false, nil
+	}
+
+	details, err := json.Marshal(map[string]interface{}{
+		"run_id":                 runID,
+		"previous_balance_cents": finding.BalanceCents,
+		"balance_cents":          finding.ExpectedBalanceCents,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal audit details: %w", err)
+	}
+	// The audit entry belongs to the account's tenant
+	auditQuery := `
+		INSERT INTO audit_logs (id, tenant_id, action, entity_type, entity_id, details, created_at, created_by)
+		SELECT $1, tenant_id, $2, $3, $4, $5, NOW(), $6 FROM accounts WHERE id = $4
+	`
+	_, err = tx.ExecContext(ctx, auditQuery, uuid.New(), types.AuditActionBalanceRepaired, types.AuditEntityAccount, finding.AccountID, details, "reconciliation")
+	if err != nil {
+		return false, fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit repair: %w", err)
+	}
+
+	repairsTotal.Inc()
+	return true, nil
+}
+
+// finishRun records the outcome of a run
+func (r *Reconciler) finishRun(ctx context.Context, runID uuid.UUID, status types.ReconciliationStatus, scanned, drifted, repaired int, runErr error) {
+	var errMsg *string
+	if runErr != nil {
+		msg := runErr.Error()
+		errMsg = &msg
+	}
+
+	query := `
+		UPDATE reconciliation_runs
+		SET status = $1, accounts_scanned = $2, drift_accounts = $3, repaired_accounts = $4, error = $5, completed_at = NOW()
+		WHERE id = $6
+	`
+	if _, err := r.db.ExecContext(ctx, query, status, scanned, drifted, repaired, errMsg, runID); err != nil {
+		r.logger.Error("Failed to record reconciliation run", zap.String("run_id", runID.String()), zap.Error(err))
+	}
+}
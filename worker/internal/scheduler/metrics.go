@@ -0,0 +1,14 @@
+package scheduler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var scheduledReleasedTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "scheduled_transactions_released_total",
+		Help: "Total number of scheduled transactions released at their execute_at, by outcome",
+	},
+	[]string{"status"},
+)
@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yash/transaction-system/shared/outbox"
+	"github.com/yash/transaction-system/shared/tenant"
+	"github.com/yash/transaction-system/shared/txstatus"
+	"github.com/yash/transaction-system/shared/types"
+	"go.uber.org/zap"
+)
+
+// Scheduler releases future-dated transactions once their execute_at has
+// passed by writing the transaction.created outbox event the API deferred
+type Scheduler struct {
+	db        *sql.DB
+	logger    *zap.Logger
+	interval  time.Duration
+	batchSize int
+}
+
+// NewScheduler creates a new transaction scheduler
+func NewScheduler(db *sql.DB, interval time.Duration, batchSize int, logger *zap.Logger) *Scheduler {
+	return &Scheduler{
+		db:        db,
+		logger:    logger,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Start runs the release loop until the context is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.logger.Info("Transaction scheduler started", zap.Duration("interval", s.interval))
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Transaction scheduler stopping...")
+			return
+		case <-ticker.C:
+			// Drain the backlog before waiting for the next tick
+			for {
+				released, failed, err := s.releaseBatch(ctx)
+				if err != nil {
+					s.logger.Error("Failed to release scheduled transactions", zap.Error(err))
+					break
+				}
+				if released > 0 || failed > 0 {
+					s.logger.Info("Released scheduled transactions", zap.Int("count", released), zap.Int("failed", failed))
+				}
+				if released+failed < s.batchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// releaseBatch releases up to batchSize due transactions in a single DB
+// transaction. Transactions whose account is no longer ACTIVE fail instead,
+// as they would have at creation. Returns the number released and the number
+// failed.
+func (s *Scheduler) releaseBatch(ctx context.Context) (int, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			s.logger.Error("Failed to rollback transaction", zap.Error(err))
+		}
+	}()
+
+	// Skip rows locked by a concurrent cancellation or another worker
+	query := `
+		SELECT t.id, t.tenant_id, t.account_id, t.amount_cents, t.currency, t.type, t.idempotency_key, t.metadata,
+		       t.authorization_id, t.reverses_transaction_id, t.fx_quote_id, a.status
+		FROM transactions t
+		JOIN accounts a ON a.id = t.account_id
+		WHERE t.status = $1 AND t.execute_at <= NOW()
+		ORDER BY t.execute_at ASC
+		LIMIT $2
+		FOR UPDATE OF t SKIP LOCKED
+	`
+	rows, err := tx.QueryContext(ctx, query, types.TransactionStatusScheduled, s.batchSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to query scheduled transactions: %w", err)
+	}
+
+	type dueTransaction struct {
+		transaction   types.Transaction
+		accountStatus types.AccountStatus
+	}
+	var due []dueTransaction
+	for rows.Next() {
+		var d dueTransaction
+		var metadata []byte
+		t := &d.transaction
+		if err := rows.Scan(
+			&t.ID, &t.TenantID, &t.AccountID, &t.AmountCents, &t.Currency, &t.Type, &t.IdempotencyKey, &metadata,
+			&t.AuthorizationID, &t.ReversesTransactionID, &t.FXQuoteID, &d.accountStatus,
+		); err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("failed to scan scheduled transaction: %w", err)
+		}
+		t.Metadata = metadata
+		due = append(due, d)
+	}
+	rows.Close()
+
+	released, failed := 0, 0
+	for _, d := range due {
+		// The status change and event belong to the transaction's tenant
+		tenantCtx := tenant.WithID(ctx, d.transaction.TenantID)
+		if d.accountStatus != types.AccountStatusActive {
+			failureReason := "account is not active"
+			if _, err := txstatus.Transition(tenantCtx, tx, d.transaction.ID, types.TransactionStatusFailed, failureReason, txstatus.ActorScheduler); err != nil {
+				return 0, 0, fmt.Errorf("failed to fail scheduled transaction: %w", err)
+			}
+			failQuery := `
+				UPDATE transactions
+				SET failure_reason = $1, updated_at = NOW()
+				WHERE id = $2
+			`
+			if _, err := tx.ExecContext(ctx, failQuery, failureReason, d.transaction.ID); err != nil {
+				return 0, 0, fmt.Errorf("failed to fail scheduled transaction: %w", err)
+			}
+			failed++
+			continue
+		}
+
+		if _, err := txstatus.Transition(tenantCtx, tx, d.transaction.ID, types.TransactionStatusPending, "", txstatus.ActorScheduler); err != nil {
+			return 0, 0, fmt.Errorf("failed to release scheduled transaction: %w", err)
+		}
+		if err := outbox.Write(tenantCtx, tx, "transaction", d.transaction.ID, types.EventTypeTransactionCreated, d.transaction.CreatedPayload()); err != nil {
+			return 0, 0, err
+		}
+		released++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	scheduledReleasedTotal.WithLabelValues("released").Add(float64(released))
+	scheduledReleasedTotal.WithLabelValues("failed").Add(float64(failed))
+
+	return released, failed, nil
+}
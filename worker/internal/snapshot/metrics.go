@@ -0,0 +1,13 @@
+package snapshot
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var snapshotsCreatedTotal = promauto.NewCounter(
+	prometheus.CounterOpts{
+		Name: "balance_snapshots_created_total",
+		Help: "Total number of end-of-day account balance snapshots created",
+	},
+)
@@ -0,0 +1,104 @@
+package snapshot
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// settleDelay is how long after midnight a day is snapshotted, so that DB
+// transactions which stamped postings before midnight have committed
+const settleDelay = 5 * time.Minute
+
+// Snapshotter records end-of-day ledger balances so point-in-time balance
+// queries only sum the postings after the latest snapshot
+type Snapshotter struct {
+	db       *sql.DB
+	logger   *zap.Logger
+	interval time.Duration
+}
+
+// NewSnapshotter creates a new balance snapshotter
+func NewSnapshotter(db *sql.DB, interval time.Duration, logger *zap.Logger) *Snapshotter {
+	return &Snapshotter{
+		db:       db,
+		logger:   logger,
+		interval: interval,
+	}
+}
+
+// Start snapshots immediately and then on every interval until the context is cancelled
+func (s *Snapshotter) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.logger.Info("Balance snapshotter started", zap.Duration("interval", s.interval))
+
+	for {
+		s.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Balance snapshotter stopping...")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Snapshotter) run(ctx context.Context) {
+	cutoff := time.Now().UTC().Add(-settleDelay).Truncate(24 * time.Hour)
+	created, err := s.SnapshotAt(ctx, cutoff)
+	if err != nil {
+		s.logger.Error("Failed to snapshot balances", zap.Error(err))
+		return
+	}
+	if created > 0 {
+		s.logger.Info("Snapshotted account balances",
+			zap.Time("snapshot_at", cutoff),
+			zap.Int64("accounts", created),
+		)
+	}
+}
+
+// SnapshotAt records the balance at cutoff of every account that has no
+// snapshot for it yet, building on each account's previous snapshot
+func (s *Snapshotter) SnapshotAt(ctx context.Context, cutoff time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	query := `
+		INSERT INTO account_balance_snapshots (account_id, snapshot_at, balance_cents, created_at)
+		SELECT a.id, $1, COALESCE(prev.balance_cents, 0) + COALESCE((
+			SELECT SUM(CASE p.direction WHEN 'CREDIT' THEN p.amount_cents ELSE -p.amount_cents END)
+			FROM postings p
+			WHERE p.account_id = a.id AND p.created_at < $1
+			  AND p.created_at >= COALESCE(prev.snapshot_at, '-infinity')
+		), 0), NOW()
+		FROM accounts a
+		LEFT JOIN LATERAL (
+			SELECT snapshot_at, balance_cents
+			FROM account_balance_snapshots
+			WHERE account_id = a.id AND snapshot_at < $1
+			ORDER BY snapshot_at DESC
+			LIMIT 1
+		) prev ON true
+		WHERE a.created_at < $1
+		ON CONFLICT (account_id, snapshot_at) DO NOTHING
+	`
+	result, err := s.db.ExecContext(ctx, query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert snapshots: %w", err)
+	}
+
+	created, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	snapshotsCreatedTotal.Add(float64(created))
+
+	return created, nil
+}